@@ -0,0 +1,266 @@
+package kubectlHelpers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"multiregiontests/internal/helpers"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Zeebe backup states reported by GET /actuator/backups/<id>.
+const (
+	ZeebeBackupCompleted    = "COMPLETED"
+	ZeebeBackupFailed       = "FAILED"
+	ZeebeBackupIncomplete   = "INCOMPLETE"
+	ZeebeBackupDoesNotExist = "DOES_NOT_EXIST"
+)
+
+// ZeebeBackupBucketPlaceholder is the CAMUNDA_DATA_BACKUP_S3_BUCKETNAME value in
+// camunda-values.yml that InstallUpgradeC8Helm replaces with the backup bucket.
+const ZeebeBackupBucketPlaceholder = "my-camunda-backup-bucket"
+
+type ZeebeBackupPartition struct {
+	PartitionId   int    `json:"partitionId"`
+	State         string `json:"state"`
+	FailureReason string `json:"failureReason"`
+	CreatedAt     string `json:"createdAt"`
+	BrokerVersion string `json:"brokerVersion"`
+}
+
+type ZeebeBackup struct {
+	BackupId      int64                  `json:"backupId"`
+	State         string                 `json:"state"`
+	FailureReason string                 `json:"failureReason"`
+	Details       []ZeebeBackupPartition `json:"details"`
+}
+
+// TakeBackup schedules a backup of every partition with the given ID. IDs must be
+// greater than the ID of any previous backup.
+func (g *GatewayManagement) TakeBackup(t *testing.T, backupID int64) error {
+	t.Helper()
+	return g.mutate(t, "POST", "/actuator/backups", map[string]int64{"backupId": backupID}, nil)
+}
+
+// Backup returns the state of a Zeebe backup and of each of its partitions.
+func (g *GatewayManagement) Backup(t *testing.T, backupID int64) (*ZeebeBackup, error) {
+	t.Helper()
+
+	var backup ZeebeBackup
+	if err := g.get(t, fmt.Sprintf("/actuator/backups/%d", backupID), &backup); err != nil {
+		return nil, err
+	}
+	return &backup, nil
+}
+
+// Backups lists the Zeebe backups.
+func (g *GatewayManagement) Backups(t *testing.T) ([]ZeebeBackup, error) {
+	t.Helper()
+
+	var backups []ZeebeBackup
+	if err := g.get(t, "/actuator/backups", &backups); err != nil {
+		return nil, err
+	}
+	return backups, nil
+}
+
+// CheckBackupStore lists the Zeebe backups, which fails with a descriptive error
+// when no backup store is configured on the brokers.
+func (g *GatewayManagement) CheckBackupStore(t *testing.T) error {
+	t.Helper()
+
+	if _, err := g.Backups(t); err != nil {
+		return fmt.Errorf("no usable Zeebe backup store (check CAMUNDA_DATA_BACKUP_STORE and CAMUNDA_DATA_BACKUP_S3_* in camunda-values.yml): %w", err)
+	}
+	return nil
+}
+
+// DeleteBackup deletes a Zeebe backup from the backup store.
+func (g *GatewayManagement) DeleteBackup(t *testing.T, backupID int64) error {
+	t.Helper()
+	return g.mutate(t, "DELETE", fmt.Sprintf("/actuator/backups/%d", backupID), nil, nil)
+}
+
+// WaitForBackup polls a Zeebe backup until it is COMPLETED on every partition and
+// fails fast once it is FAILED, INCOMPLETE or DOES_NOT_EXIST.
+func (g *GatewayManagement) WaitForBackup(t *testing.T, backupID int64, maxRetries int, interval time.Duration) (*ZeebeBackup, error) {
+	t.Helper()
+
+	var lastErr error
+	for i := 0; i < maxRetries; i++ {
+		backup, err := g.Backup(t, backupID)
+		switch {
+		case err != nil:
+			lastErr = err
+			t.Logf("[ZEEBE BACKUP] backup %d status request failed (attempt %d/%d): %v", backupID, i+1, maxRetries, err)
+		case backup.State == ZeebeBackupCompleted:
+			for _, p := range backup.Details {
+				if p.State != ZeebeBackupCompleted {
+					return backup, fmt.Errorf("backup %d is %s but partition %d is %s: %s", backupID, backup.State, p.PartitionId, p.State, p.FailureReason)
+				}
+			}
+			return backup, nil
+		case backup.State == ZeebeBackupFailed || backup.State == ZeebeBackupIncomplete || backup.State == ZeebeBackupDoesNotExist:
+			return backup, fmt.Errorf("backup %d is %s: %s", backupID, backup.State, backup.FailureReason)
+		default:
+			lastErr = fmt.Errorf("backup %d is %s", backupID, backup.State)
+			t.Logf("[ZEEBE BACKUP] backup %d is %s (attempt %d/%d)", backupID, backup.State, i+1, maxRetries)
+		}
+		time.Sleep(interval)
+	}
+	return nil, fmt.Errorf("backup %d did not complete after %d attempts: %w", backupID, maxRetries, lastErr)
+}
+
+// CoordinatedBackupOptions configures TakeCoordinatedBackup.
+type CoordinatedBackupOptions struct {
+	// ID is shared by the Zeebe backup and the Elasticsearch snapshots. Zeebe
+	// requires it to be greater than the ID of any previous backup.
+	ID int64
+	// Gateway is the region whose Zeebe gateway pauses exporting and takes the
+	// Zeebe backup; the Zeebe cluster is stretched across both regions.
+	Gateway helpers.Cluster
+	// Regions are the regions whose Elasticsearch is snapshotted.
+	Regions []helpers.Cluster
+	// SnapshotPrefix prefixes the Elasticsearch snapshot names.
+	SnapshotPrefix string
+	// Zeebe takes the Zeebe partition backup. The backup fails before exporting is
+	// paused when the brokers have no backup store configured.
+	Zeebe bool
+	// KeepExportingPaused turns the soft pause into a hard one once the snapshots
+	// are taken and leaves it in place after a successful backup, for callers (such
+	// as the failback) that must restore another region from the snapshot before
+	// anything else is exported.
+	KeepExportingPaused bool
+}
+
+// CoordinatedBackup is a Camunda backup taken by TakeCoordinatedBackup: a Zeebe
+// backup and one Elasticsearch snapshot per region, all for the same ID.
+type CoordinatedBackup struct {
+	ID      int64
+	Gateway helpers.Cluster
+	// Snapshots maps a region (helpers.Cluster.Region) to its snapshot name.
+	Snapshots map[string]string
+	// Zeebe is nil when the Zeebe backup was not taken.
+	Zeebe *ZeebeBackup
+}
+
+// CoordinatedSnapshotName is the name of the Elasticsearch snapshot of region for the
+// coordinated backup with the given ID.
+func CoordinatedSnapshotName(prefix string, backupID int64, region string) string {
+	return fmt.Sprintf("%s-%d-%s", prefix, backupID, region)
+}
+
+// TakeCoordinatedBackup follows the Camunda backup procedure across regions:
+//
+//  1. soft-pause exporting, so the log is not compacted past what the snapshots hold;
+//  2. schedule the Zeebe backup with opts.ID through the gateway management API;
+//  3. snapshot Elasticsearch in every region of opts.Regions;
+//  4. resume exporting (unless opts.KeepExportingPaused);
+//  5. wait for the Zeebe backup to complete on every partition.
+//
+// Only a pause this function started is resumed, also when any step fails, so a
+// failed backup never leaves the cluster paused. When exporting is already paused
+// on entry (e.g. by the failback before it), steps 1 and 4 are skipped and the
+// pause is left to whoever started it.
+func TakeCoordinatedBackup(t *testing.T, opts CoordinatedBackupOptions) *CoordinatedBackup {
+	t.Helper()
+	t.Logf("[COORDINATED BACKUP] Taking backup %d (Zeebe: %t, Elasticsearch regions: %d)", opts.ID, opts.Zeebe, len(opts.Regions))
+
+	backup := &CoordinatedBackup{ID: opts.ID, Gateway: opts.Gateway, Snapshots: map[string]string{}}
+	gateway := NewGatewayManagement(&opts.Gateway.KubectlNamespace)
+	if opts.Zeebe {
+		require.NoError(t, gateway.CheckBackupStore(t), "[COORDINATED BACKUP] Zeebe backup %d", opts.ID)
+	}
+
+	alreadyPaused, err := gateway.ExportingPaused(t)
+	require.NoError(t, err, "[COORDINATED BACKUP] reading the exporting state")
+	paused := false
+	if alreadyPaused {
+		t.Log("[COORDINATED BACKUP] Exporting is already paused; leaving the pause as it is")
+	} else {
+		require.NoError(t, gateway.PauseExporting(t, true), "[COORDINATED BACKUP] pausing exporting")
+		paused = true
+	}
+	defer func() {
+		if paused && (!opts.KeepExportingPaused || t.Failed()) {
+			if err := gateway.ResumeExporting(t); err != nil {
+				t.Errorf("[COORDINATED BACKUP] resuming exporting: %v", err)
+			}
+		}
+	}()
+
+	if opts.Zeebe {
+		require.NoError(t, gateway.TakeBackup(t, opts.ID), "[COORDINATED BACKUP] scheduling Zeebe backup %d", opts.ID)
+		t.Logf("[COORDINATED BACKUP] Scheduled Zeebe backup %d", opts.ID)
+	} else {
+		t.Logf("[COORDINATED BACKUP] Zeebe backup disabled; backup %d covers Elasticsearch only", opts.ID)
+	}
+
+	for _, region := range opts.Regions {
+		name := CoordinatedSnapshotName(opts.SnapshotPrefix, opts.ID, region.Region)
+		es := NewElasticsearch(t, &region.KubectlNamespace)
+		info, err := NewSnapshotManager(es, ElasticBackupRepository).Create(t, name)
+		es.Close()
+		require.NoError(t, err, "[COORDINATED BACKUP] snapshotting Elasticsearch in %s", region.ClusterName)
+		backup.Snapshots[region.Region] = info.Snapshot
+	}
+
+	switch {
+	case !paused:
+		// Not ours to resume or harden.
+	case opts.KeepExportingPaused:
+		require.NoError(t, gateway.PauseExporting(t, false), "[COORDINATED BACKUP] hard-pausing exporting")
+	default:
+		require.NoError(t, gateway.ResumeExporting(t), "[COORDINATED BACKUP] resuming exporting")
+		paused = false
+	}
+
+	if opts.Zeebe {
+		zb, err := gateway.WaitForBackup(t, opts.ID, 60, 10*time.Second)
+		require.NoError(t, err, "[COORDINATED BACKUP] Zeebe backup %d", opts.ID)
+		backup.Zeebe = zb
+		t.Logf("[COORDINATED BACKUP] Zeebe backup %d is %s on %d partitions", zb.BackupId, zb.State, len(zb.Details))
+	}
+
+	t.Logf("[COORDINATED BACKUP] Backup %d complete: snapshots %v", backup.ID, backup.Snapshots)
+	return backup
+}
+
+// RestoreCoordinatedBackup restores the Elasticsearch of target from the snapshot
+// that backup took in the source region, after checking that the Zeebe backup with
+// the same ID is still COMPLETED. Both regions' repositories point at the same
+// bucket, so the snapshot is read from there. Restoring fails for indices that
+// already exist in target, so target must come up without creating the Camunda
+// schema (as the failback deploys the recreated region).
+//
+// The Zeebe backup itself is not restored: that needs the brokers stopped and their
+// volumes emptied, and in a dual-region failback the brokers of the surviving
+// region still hold the state. It is the point in time the snapshots match.
+func RestoreCoordinatedBackup(t *testing.T, backup *CoordinatedBackup, source, target helpers.Cluster) {
+	t.Helper()
+
+	name, ok := backup.Snapshots[source.Region]
+	require.True(t, ok, "[COORDINATED BACKUP] backup %d has no snapshot of %s", backup.ID, source.Region)
+	t.Logf("[COORDINATED BACKUP] Restoring %s from snapshot %s of backup %d", target.ClusterName, name, backup.ID)
+
+	if backup.Zeebe != nil {
+		zb, err := NewGatewayManagement(&backup.Gateway.KubectlNamespace).Backup(t, backup.ID)
+		require.NoError(t, err, "[COORDINATED BACKUP] reading Zeebe backup %d", backup.ID)
+		require.Equal(t, ZeebeBackupCompleted, zb.State, "[COORDINATED BACKUP] Zeebe backup %d: %s", backup.ID, zb.FailureReason)
+	}
+
+	es := NewElasticsearch(t, &target.KubectlNamespace)
+	defer es.Close()
+
+	manager := NewSnapshotManager(es, ElasticBackupRepository)
+	manager.Timeout = 5 * time.Minute
+	require.NoError(t, manager.VerifyRepository(t), "[COORDINATED BACKUP] repository %s is not usable in %s", ElasticBackupRepository, target.ClusterName)
+	_, err := manager.Wait(t, name)
+	require.NoError(t, err, "[COORDINATED BACKUP] snapshot %s is not usable in %s", name, target.ClusterName)
+
+	info, err := es.RestoreSnapshot(ElasticBackupRepository, name)
+	require.NoError(t, err, "[COORDINATED BACKUP] restoring snapshot %s", name)
+	t.Logf("[COORDINATED BACKUP] Restored %s: %d indices, %d/%d shards", info.Snapshot, len(info.Indices), info.Shards.Successful, info.Shards.Total)
+}
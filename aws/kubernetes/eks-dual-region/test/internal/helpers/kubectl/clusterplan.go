@@ -0,0 +1,119 @@
+package kubectlHelpers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Planned operation types of a cluster change that move partition replicas.
+const (
+	OperationBrokerAdd          = "BROKER_ADD"
+	OperationBrokerRemove       = "BROKER_REMOVE"
+	OperationPartitionJoin      = "PARTITION_JOIN"
+	OperationPartitionLeave     = "PARTITION_LEAVE"
+	OperationPartitionBootstrap = "PARTITION_BOOTSTRAP"
+)
+
+// ClusterPlanSummary groups the planned operations of a cluster change by kind.
+type ClusterPlanSummary struct {
+	BrokerAdds    []int
+	BrokerRemoves []int
+	Joins         []TopologyOperation
+	Leaves        []TopologyOperation
+	Bootstraps    []TopologyOperation
+	Other         int
+}
+
+// Summary groups the planned operations of p by kind.
+func (p *ClusterChangePlan) Summary() ClusterPlanSummary {
+	var s ClusterPlanSummary
+	for _, op := range p.PlannedChanges {
+		switch op.Operation {
+		case OperationBrokerAdd:
+			s.BrokerAdds = append(s.BrokerAdds, op.BrokerId)
+		case OperationBrokerRemove:
+			s.BrokerRemoves = append(s.BrokerRemoves, op.BrokerId)
+		case OperationPartitionJoin:
+			s.Joins = append(s.Joins, op)
+		case OperationPartitionLeave:
+			s.Leaves = append(s.Leaves, op)
+		case OperationPartitionBootstrap:
+			s.Bootstraps = append(s.Bootstraps, op)
+		default:
+			s.Other++
+		}
+	}
+	return s
+}
+
+func (s ClusterPlanSummary) String() string {
+	return fmt.Sprintf("add brokers %v, remove brokers %v, %d partition joins, %d leaves, %d bootstraps, %d other operations",
+		s.BrokerAdds, s.BrokerRemoves, len(s.Joins), len(s.Leaves), len(s.Bootstraps), s.Other)
+}
+
+// RegionCount is the number of regions the Zeebe cluster is stretched across
+// (global.multiregion.regions in helm-values/camunda-values.yml).
+const RegionCount = 2
+
+// BrokerRegion returns the region a broker runs in. The dual-region setup gives the
+// brokers of region r the IDs r, r+regions, r+2*regions, ... (even IDs in region 0
+// and odd IDs in region 1 with two regions).
+func BrokerRegion(brokerID, regions int) int {
+	return brokerID % regions
+}
+
+// PartitionReplicas returns the IDs of the brokers hosting each partition.
+func PartitionReplicas(brokers []TopologyBroker) map[int][]int {
+	replicas := map[int][]int{}
+	for _, b := range brokers {
+		for _, p := range b.Partitions {
+			replicas[p.Id] = append(replicas[p.Id], b.Id)
+		}
+	}
+	for _, ids := range replicas {
+		sort.Ints(ids)
+	}
+	return replicas
+}
+
+// CheckRegionBalance verifies that the replicas of every partition in brokers are
+// split as evenly as possible across the regions, i.e. the number of replicas per
+// region differs by at most one, and not at all when the replication factor is a
+// multiple of the number of regions. Losing a region then never loses a partition's
+// quorum for more than its share. The error lists every unbalanced partition.
+func CheckRegionBalance(brokers []TopologyBroker, regions int) error {
+	replicas := PartitionReplicas(brokers)
+	if len(replicas) == 0 {
+		return fmt.Errorf("topology has no partitions")
+	}
+	partitionIDs := make([]int, 0, len(replicas))
+	for id := range replicas {
+		partitionIDs = append(partitionIDs, id)
+	}
+	sort.Ints(partitionIDs)
+
+	var unbalanced []string
+	for _, id := range partitionIDs {
+		perRegion := make([]int, regions)
+		for _, broker := range replicas[id] {
+			perRegion[BrokerRegion(broker, regions)]++
+		}
+		lowest, highest := perRegion[0], perRegion[0]
+		for _, n := range perRegion {
+			lowest = min(lowest, n)
+			highest = max(highest, n)
+		}
+		allowed := 1
+		if len(replicas[id])%regions == 0 {
+			allowed = 0
+		}
+		if highest-lowest > allowed {
+			unbalanced = append(unbalanced, fmt.Sprintf("partition %d on brokers %v (replicas per region %v)", id, replicas[id], perRegion))
+		}
+	}
+	if len(unbalanced) > 0 {
+		return fmt.Errorf("replicas are not balanced across %d regions: %s", regions, strings.Join(unbalanced, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package kubectlHelpers
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// brokersHosting builds a topology from the broker IDs hosting each partition.
+func brokersHosting(replicas map[int][]int) []TopologyBroker {
+	byBroker := map[int][]TopologyPartition{}
+	for partition, brokers := range replicas {
+		for _, b := range brokers {
+			byBroker[b] = append(byBroker[b], TopologyPartition{Id: partition})
+		}
+	}
+	ids := make([]int, 0, len(byBroker))
+	for id := range byBroker {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	brokers := make([]TopologyBroker, 0, len(ids))
+	for _, id := range ids {
+		brokers = append(brokers, TopologyBroker{Id: id, Partitions: byBroker[id]})
+	}
+	return brokers
+}
+
+func TestBrokerRegion(t *testing.T) {
+	for _, tc := range []struct {
+		broker, regions, want int
+	}{
+		{0, 2, 0},
+		{1, 2, 1},
+		{6, 2, 0},
+		{7, 2, 1},
+		{4, 3, 1},
+	} {
+		assert.Equal(t, tc.want, BrokerRegion(tc.broker, tc.regions), "broker %d of %d regions", tc.broker, tc.regions)
+	}
+}
+
+func TestCheckRegionBalance(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		replicas map[int][]int
+		// unbalanced lists the partitions the error must name; nil means balanced.
+		unbalanced []string
+	}{
+		{name: "RF2 balanced", replicas: map[int][]int{1: {0, 1}, 2: {2, 3}}},
+		{name: "RF2 both replicas in region 0", replicas: map[int][]int{1: {0, 1}, 2: {0, 2}}, unbalanced: []string{"partition 2 "}},
+		{name: "RF3 two and one", replicas: map[int][]int{1: {0, 1, 2}, 2: {1, 2, 3}}},
+		{name: "RF3 all in region 0", replicas: map[int][]int{1: {0, 2, 4}}, unbalanced: []string{"partition 1 "}},
+		{name: "RF4 balanced", replicas: map[int][]int{1: {0, 1, 2, 3}, 2: {4, 5, 6, 7}}},
+		{name: "RF4 three and one", replicas: map[int][]int{1: {0, 1, 2, 4}, 2: {1, 3, 5, 6}}, unbalanced: []string{"partition 1 ", "partition 2 "}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckRegionBalance(brokersHosting(tc.replicas), RegionCount)
+			if tc.unbalanced == nil {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, p := range tc.unbalanced {
+				assert.Contains(t, err.Error(), p)
+			}
+		})
+	}
+}
+
+func TestCheckRegionBalanceNoPartitions(t *testing.T) {
+	assert.Error(t, CheckRegionBalance([]TopologyBroker{{Id: 0}, {Id: 1}}, RegionCount))
+}
@@ -0,0 +1,359 @@
+package kubectlHelpers
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/k8s"
+)
+
+const (
+	// ElasticBackupRepository is the snapshot repository registered in both regions.
+	ElasticBackupRepository = "camunda_backup"
+
+	elasticsearchRequestTimeout = 60 * time.Second
+	// elasticsearchSnapshotTimeout bounds calls that wait for a snapshot or a
+	// restore to complete.
+	elasticsearchSnapshotTimeout = 30 * time.Minute
+)
+
+// Elasticsearch is a typed client for the ECK-managed Elasticsearch of one region.
+// Requests go through a kubectl port-forward to ElasticsearchHTTPServiceName (see
+// NewServiceTunnelWithRetry) and authenticate as the elastic user with an
+// Authorization header, so the password never appears in a process argument list
+// the way it did with `kubectl exec ... curl -u elastic:<pw>`.
+type Elasticsearch struct {
+	endpoint string
+	auth     string
+	client   *http.Client
+	close    func()
+}
+
+// NewElasticsearch opens a tunnel to the Elasticsearch HTTP service in the namespace
+// of kubectlOptions. Call Close when done to stop the port-forward.
+func NewElasticsearch(t *testing.T, kubectlOptions *k8s.KubectlOptions) *Elasticsearch {
+	t.Helper()
+
+	password := getElasticsearchPassword(t, kubectlOptions)
+	endpoint, closeFn := NewServiceTunnelWithRetry(t, kubectlOptions, ElasticsearchHTTPServiceName, 0, 9200, 5, 15*time.Second)
+	return &Elasticsearch{
+		endpoint: endpoint,
+		auth:     "Basic " + base64.StdEncoding.EncodeToString([]byte("elastic:"+password)),
+		client:   &http.Client{},
+		close:    closeFn,
+	}
+}
+
+// Close stops the port-forward.
+func (es *Elasticsearch) Close() {
+	es.close()
+}
+
+// ElasticsearchError is a non-2xx Elasticsearch response. Type and Reason come from
+// the "error" object of the body, e.g. snapshot_missing_exception.
+type ElasticsearchError struct {
+	StatusCode int
+	Method     string
+	Path       string
+	Type       string
+	Reason     string
+	Body       string
+}
+
+func (e *ElasticsearchError) Error() string {
+	if e.Type == "" {
+		return fmt.Sprintf("elasticsearch %s %s: status %d: %s", e.Method, e.Path, e.StatusCode, e.Body)
+	}
+	return fmt.Sprintf("elasticsearch %s %s: status %d: %s: %s", e.Method, e.Path, e.StatusCode, e.Type, e.Reason)
+}
+
+// IsElasticsearchNotFound reports whether err is a 404 from Elasticsearch, such as a
+// missing snapshot, repository or index.
+func IsElasticsearchNotFound(err error) bool {
+	var esErr *ElasticsearchError
+	return errors.As(err, &esErr) && esErr.StatusCode == http.StatusNotFound
+}
+
+type ElasticsearchShards struct {
+	Total      int `json:"total"`
+	Failed     int `json:"failed"`
+	Successful int `json:"successful"`
+}
+
+type SnapshotShardFailure struct {
+	Index   string `json:"index"`
+	ShardId int    `json:"shard_id"`
+	Reason  string `json:"reason"`
+	Status  string `json:"status"`
+}
+
+type SnapshotInfo struct {
+	Snapshot          string                 `json:"snapshot"`
+	UUID              string                 `json:"uuid"`
+	Repository        string                 `json:"repository"`
+	Indices           []string               `json:"indices"`
+	State             string                 `json:"state"`
+	StartTimeInMillis int64                  `json:"start_time_in_millis"`
+	EndTimeInMillis   int64                  `json:"end_time_in_millis"`
+	Failures          []SnapshotShardFailure `json:"failures"`
+	Shards            ElasticsearchShards    `json:"shards"`
+}
+
+type RestoreInfo struct {
+	Snapshot string              `json:"snapshot"`
+	Indices  []string            `json:"indices"`
+	Shards   ElasticsearchShards `json:"shards"`
+}
+
+// S3RepositorySettings are the settings of an "s3" snapshot repository. Client names
+// the s3.client.<name>.* credentials in the Elasticsearch keystore.
+type S3RepositorySettings struct {
+	Bucket   string `json:"bucket"`
+	Client   string `json:"client"`
+	BasePath string `json:"base_path"`
+}
+
+// PutS3Repository registers (or updates) an S3 snapshot repository.
+func (es *Elasticsearch) PutS3Repository(repository string, settings S3RepositorySettings) error {
+	payload := map[string]interface{}{"type": "s3", "settings": settings}
+	var res struct {
+		Acknowledged bool `json:"acknowledged"`
+	}
+	if err := es.do(http.MethodPut, "/_snapshot/"+url.PathEscape(repository), payload, &res, elasticsearchRequestTimeout); err != nil {
+		return err
+	}
+	if !res.Acknowledged {
+		return fmt.Errorf("elasticsearch: registering repository %s was not acknowledged", repository)
+	}
+	return nil
+}
+
+// DeleteRepository unregisters a snapshot repository; the snapshots in the bucket are kept.
+func (es *Elasticsearch) DeleteRepository(repository string) error {
+	return es.do(http.MethodDelete, "/_snapshot/"+url.PathEscape(repository), nil, nil, elasticsearchRequestTimeout)
+}
+
+// CreateSnapshot snapshots all indices including the global state and waits for it
+// to finish. It returns an error when the snapshot is not SUCCESS or any shard failed.
+func (es *Elasticsearch) CreateSnapshot(repository, snapshot string) (*SnapshotInfo, error) {
+	var res struct {
+		Snapshot SnapshotInfo `json:"snapshot"`
+	}
+	path := fmt.Sprintf("/_snapshot/%s/%s?wait_for_completion=true", url.PathEscape(repository), url.PathEscape(snapshot))
+	if err := es.do(http.MethodPut, path, map[string]bool{"include_global_state": true}, &res, elasticsearchSnapshotTimeout); err != nil {
+		return nil, err
+	}
+	info := &res.Snapshot
+	if info.State != "SUCCESS" || info.Shards.Failed > 0 {
+		return info, fmt.Errorf("elasticsearch: snapshot %s is %s with %d of %d shards failed", snapshot, info.State, info.Shards.Failed, info.Shards.Total)
+	}
+	return info, nil
+}
+
+// DeleteSnapshot deletes a snapshot. A missing snapshot is reported as an error
+// satisfying IsElasticsearchNotFound.
+func (es *Elasticsearch) DeleteSnapshot(repository, snapshot string) error {
+	path := fmt.Sprintf("/_snapshot/%s/%s", url.PathEscape(repository), url.PathEscape(snapshot))
+	return es.do(http.MethodDelete, path, nil, nil, elasticsearchRequestTimeout)
+}
+
+// VerifyRepository runs the repository verification (_verify), which has every node
+// write to and read from the repository, and returns the names of the nodes that
+// verified it.
+func (es *Elasticsearch) VerifyRepository(repository string) ([]string, error) {
+	var res struct {
+		Nodes map[string]struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	}
+	if err := es.do(http.MethodPost, fmt.Sprintf("/_snapshot/%s/_verify", url.PathEscape(repository)), nil, &res, elasticsearchRequestTimeout); err != nil {
+		return nil, err
+	}
+	nodes := make([]string, 0, len(res.Nodes))
+	for _, n := range res.Nodes {
+		nodes = append(nodes, n.Name)
+	}
+	return nodes, nil
+}
+
+// StartSnapshot starts a snapshot of all indices including the global state without
+// waiting for it; see SnapshotManager.Wait.
+func (es *Elasticsearch) StartSnapshot(repository, snapshot string) error {
+	var res struct {
+		Accepted bool `json:"accepted"`
+	}
+	path := fmt.Sprintf("/_snapshot/%s/%s", url.PathEscape(repository), url.PathEscape(snapshot))
+	if err := es.do(http.MethodPut, path, map[string]bool{"include_global_state": true}, &res, elasticsearchRequestTimeout); err != nil {
+		return err
+	}
+	if !res.Accepted {
+		return fmt.Errorf("elasticsearch: snapshot %s was not accepted", snapshot)
+	}
+	return nil
+}
+
+// Snapshot returns one snapshot. A missing snapshot is reported as an error
+// satisfying IsElasticsearchNotFound.
+func (es *Elasticsearch) Snapshot(repository, snapshot string) (*SnapshotInfo, error) {
+	var res struct {
+		Snapshots []SnapshotInfo `json:"snapshots"`
+	}
+	path := fmt.Sprintf("/_snapshot/%s/%s", url.PathEscape(repository), url.PathEscape(snapshot))
+	if err := es.do(http.MethodGet, path, nil, &res, elasticsearchRequestTimeout); err != nil {
+		return nil, err
+	}
+	if len(res.Snapshots) != 1 {
+		return nil, fmt.Errorf("elasticsearch: GET %s returned %d snapshots, want 1", path, len(res.Snapshots))
+	}
+	return &res.Snapshots[0], nil
+}
+
+type SnapshotShardsStats struct {
+	Initializing int `json:"initializing"`
+	Started      int `json:"started"`
+	Finalizing   int `json:"finalizing"`
+	Done         int `json:"done"`
+	Failed       int `json:"failed"`
+	Total        int `json:"total"`
+}
+
+type SnapshotIndexStatus struct {
+	ShardsStats SnapshotShardsStats `json:"shards_stats"`
+}
+
+type SnapshotStatus struct {
+	Snapshot    string                         `json:"snapshot"`
+	State       string                         `json:"state"`
+	ShardsStats SnapshotShardsStats            `json:"shards_stats"`
+	Indices     map[string]SnapshotIndexStatus `json:"indices"`
+}
+
+// SnapshotStatus returns the detailed, per-index shard status of a snapshot.
+func (es *Elasticsearch) SnapshotStatus(repository, snapshot string) (*SnapshotStatus, error) {
+	var res struct {
+		Snapshots []SnapshotStatus `json:"snapshots"`
+	}
+	path := fmt.Sprintf("/_snapshot/%s/%s/_status", url.PathEscape(repository), url.PathEscape(snapshot))
+	if err := es.do(http.MethodGet, path, nil, &res, elasticsearchRequestTimeout); err != nil {
+		return nil, err
+	}
+	if len(res.Snapshots) != 1 {
+		return nil, fmt.Errorf("elasticsearch: GET %s returned %d snapshots, want 1", path, len(res.Snapshots))
+	}
+	return &res.Snapshots[0], nil
+}
+
+// Snapshots lists all snapshots in a repository.
+func (es *Elasticsearch) Snapshots(repository string) ([]SnapshotInfo, error) {
+	var res struct {
+		Snapshots []SnapshotInfo `json:"snapshots"`
+	}
+	if err := es.do(http.MethodGet, fmt.Sprintf("/_snapshot/%s/_all", url.PathEscape(repository)), nil, &res, elasticsearchRequestTimeout); err != nil {
+		return nil, err
+	}
+	return res.Snapshots, nil
+}
+
+// RestoreSnapshot restores all indices and the global state of a snapshot and waits
+// for it to finish. It returns an error when any shard failed to restore.
+func (es *Elasticsearch) RestoreSnapshot(repository, snapshot string) (*RestoreInfo, error) {
+	var res struct {
+		Snapshot RestoreInfo `json:"snapshot"`
+	}
+	path := fmt.Sprintf("/_snapshot/%s/%s/_restore?wait_for_completion=true", url.PathEscape(repository), url.PathEscape(snapshot))
+	if err := es.do(http.MethodPost, path, map[string]bool{"include_global_state": true}, &res, elasticsearchSnapshotTimeout); err != nil {
+		return nil, err
+	}
+	info := &res.Snapshot
+	if info.Shards.Failed > 0 {
+		return info, fmt.Errorf("elasticsearch: restoring %s failed for %d of %d shards", snapshot, info.Shards.Failed, info.Shards.Total)
+	}
+	return info, nil
+}
+
+// ClusterHealth returns the cluster health.
+func (es *Elasticsearch) ClusterHealth() (*ElasticsearchClusterHealth, error) {
+	var health ElasticsearchClusterHealth
+	if err := es.do(http.MethodGet, "/_cluster/health", nil, &health, elasticsearchRequestTimeout); err != nil {
+		return nil, err
+	}
+	return &health, nil
+}
+
+// Count returns the number of documents in the indices matching indexPattern.
+func (es *Elasticsearch) Count(indexPattern string) (int64, error) {
+	var res struct {
+		Count int64 `json:"count"`
+	}
+	if err := es.do(http.MethodGet, "/"+url.PathEscape(indexPattern)+"/_count", nil, &res, elasticsearchRequestTimeout); err != nil {
+		return 0, err
+	}
+	return res.Count, nil
+}
+
+// do sends payload as JSON and decodes a 2xx response into out (if not nil). Any
+// other status is returned as an *ElasticsearchError.
+func (es *Elasticsearch) do(method, path string, payload, out interface{}, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var bodyReader io.Reader
+	if payload != nil {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		bodyReader = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("http://%s%s", es.endpoint, path), bodyReader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", es.auth)
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := es.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("elasticsearch %s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("elasticsearch %s %s: reading response: %w", method, path, err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		esErr := &ElasticsearchError{StatusCode: resp.StatusCode, Method: method, Path: path, Body: strings.TrimSpace(string(body))}
+		var errBody struct {
+			Error struct {
+				Type   string `json:"type"`
+				Reason string `json:"reason"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(body, &errBody) == nil {
+			esErr.Type = errBody.Error.Type
+			esErr.Reason = errBody.Error.Reason
+		}
+		return esErr
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("elasticsearch %s %s: decoding response: %w", method, path, err)
+	}
+	return nil
+}
@@ -0,0 +1,385 @@
+package kubectlHelpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/k8s"
+)
+
+// Cluster change states reported in lastChange/pendingChange of GET /actuator/cluster.
+const (
+	ClusterChangeInitialized = "INITIALIZED"
+	ClusterChangeInProgress  = "IN_PROGRESS"
+	ClusterChangeCompleted   = "COMPLETED"
+	ClusterChangeFailed      = "FAILED"
+	ClusterChangeCancelled   = "CANCELLED"
+)
+
+// Exporter phases of a partition reported by GET /actuator/partitions.
+const (
+	ExporterPhaseExporting  = "EXPORTING"
+	ExporterPhasePaused     = "PAUSED"
+	ExporterPhaseSoftPaused = "SOFT_PAUSED"
+)
+
+// GatewayManagement is a typed client for the Zeebe gateway management API
+// (/actuator on port 9600) of one region. Every call opens its own short-lived
+// port-forward (see gatewayManagementRequest); mutating calls are retried on
+// connection errors and transient 5xx responses (see gatewayManagementMutate).
+type GatewayManagement struct {
+	kubectlOptions *k8s.KubectlOptions
+	// MaxRetries and Backoff bound the retries of a mutating call.
+	MaxRetries int
+	Backoff    time.Duration
+}
+
+// NewGatewayManagement returns a client for the gateway in the namespace of
+// kubectlOptions that retries mutating calls 8 times, 15s apart.
+func NewGatewayManagement(kubectlOptions *k8s.KubectlOptions) *GatewayManagement {
+	return &GatewayManagement{kubectlOptions: kubectlOptions, MaxRetries: 8, Backoff: 15 * time.Second}
+}
+
+// GatewayManagementError is an unexpected status from the gateway management API.
+// Title and Detail come from the problem-details body, when there is one.
+type GatewayManagementError struct {
+	StatusCode int
+	Method     string
+	Path       string
+	Title      string
+	Detail     string
+	Body       string
+}
+
+func (e *GatewayManagementError) Error() string {
+	if e.Title == "" && e.Detail == "" {
+		return fmt.Sprintf("gateway %s %s: status %d: %s", e.Method, e.Path, e.StatusCode, e.Body)
+	}
+	return fmt.Sprintf("gateway %s %s: status %d: %s: %s", e.Method, e.Path, e.StatusCode, e.Title, e.Detail)
+}
+
+type ExporterConfig struct {
+	Id    string `json:"id"`
+	State string `json:"state"`
+}
+
+type TopologyPartition struct {
+	Id       int    `json:"id"`
+	State    string `json:"state"`
+	Priority int    `json:"priority"`
+	Config   struct {
+		Exporting struct {
+			Exporters []ExporterConfig `json:"exporters"`
+		} `json:"exporting"`
+	} `json:"config"`
+}
+
+type TopologyBroker struct {
+	Id            int                 `json:"id"`
+	State         string              `json:"state"`
+	Version       int64               `json:"version"`
+	LastUpdatedAt string              `json:"lastUpdatedAt"`
+	Partitions    []TopologyPartition `json:"partitions"`
+}
+
+// TopologyOperation is one step of a cluster change, e.g. BROKER_ADD,
+// PARTITION_JOIN, PARTITION_LEAVE, PARTITION_BOOTSTRAP or PARTITION_FORCE_RECONFIGURE.
+type TopologyOperation struct {
+	Operation   string `json:"operation"`
+	BrokerId    int    `json:"brokerId"`
+	PartitionId int    `json:"partitionId"`
+	Priority    int    `json:"priority"`
+	Brokers     []int  `json:"brokers"`
+	ExporterId  string `json:"exporterId"`
+}
+
+type CompletedClusterChange struct {
+	Id          int64  `json:"id"`
+	Status      string `json:"status"`
+	StartedAt   string `json:"startedAt"`
+	CompletedAt string `json:"completedAt"`
+}
+
+type PendingClusterChange struct {
+	Id        int64               `json:"id"`
+	Status    string              `json:"status"`
+	Completed []TopologyOperation `json:"completed"`
+	Pending   []TopologyOperation `json:"pending"`
+}
+
+// ClusterTopology is the response of GET /actuator/cluster.
+type ClusterTopology struct {
+	Version       int64                   `json:"version"`
+	Brokers       []TopologyBroker        `json:"brokers"`
+	LastChange    *CompletedClusterChange `json:"lastChange"`
+	PendingChange *PendingClusterChange   `json:"pendingChange"`
+}
+
+// ClusterChangePlan is the response to a cluster change request: the change ID to
+// wait on, the planned operations and the topology they lead to.
+type ClusterChangePlan struct {
+	ChangeId         int64               `json:"changeId"`
+	CurrentTopology  []TopologyBroker    `json:"currentTopology"`
+	PlannedChanges   []TopologyOperation `json:"plannedChanges"`
+	ExpectedTopology []TopologyBroker    `json:"expectedTopology"`
+}
+
+// Operations returns the planned operations of the given type.
+func (p *ClusterChangePlan) Operations(operation string) []TopologyOperation {
+	var ops []TopologyOperation
+	for _, op := range p.PlannedChanges {
+		if op.Operation == operation {
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+type BrokersPatch struct {
+	Add    []int `json:"add,omitempty"`
+	Remove []int `json:"remove,omitempty"`
+}
+
+type PartitionsPatch struct {
+	Count             int `json:"count,omitempty"`
+	ReplicationFactor int `json:"replicationFactor,omitempty"`
+}
+
+// ClusterPatch is the body of PATCH /actuator/cluster.
+type ClusterPatch struct {
+	Brokers    *BrokersPatch    `json:"brokers,omitempty"`
+	Partitions *PartitionsPatch `json:"partitions,omitempty"`
+}
+
+// ClusterPatchOptions are the query parameters of PATCH /actuator/cluster. DryRun
+// returns the plan without applying it; Force removes brokers without waiting for
+// them, as after losing a region.
+type ClusterPatchOptions struct {
+	DryRun bool
+	Force  bool
+}
+
+// PartitionStatus is one partition in the response of GET /actuator/partitions.
+type PartitionStatus struct {
+	Role          string `json:"role"`
+	ExporterPhase string `json:"exporterPhase"`
+}
+
+type ExporterStatus struct {
+	ExporterId string `json:"exporterId"`
+	Status     string `json:"status"`
+}
+
+// Topology returns the cluster topology with the last and the pending change.
+func (g *GatewayManagement) Topology(t *testing.T) (*ClusterTopology, error) {
+	t.Helper()
+
+	var topology ClusterTopology
+	if err := g.get(t, "/actuator/cluster", &topology); err != nil {
+		return nil, err
+	}
+	return &topology, nil
+}
+
+// PatchCluster requests a broker and/or partition scaling change and returns its plan.
+func (g *GatewayManagement) PatchCluster(t *testing.T, patch ClusterPatch, opts ClusterPatchOptions) (*ClusterChangePlan, error) {
+	t.Helper()
+
+	query := url.Values{}
+	if opts.DryRun {
+		query.Set("dryRun", "true")
+	}
+	if opts.Force {
+		query.Set("force", "true")
+	}
+	path := "/actuator/cluster"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	var plan ClusterChangePlan
+	if err := g.mutate(t, "PATCH", path, patch, &plan); err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// WaitForChange polls the topology until the change with changeID is COMPLETED and
+// returns the topology at that point. It fails fast once the change is FAILED or
+// CANCELLED, or once a later change has replaced it and its outcome can no longer be
+// read. Failed polls (e.g. a tunnel dropped by a restarting broker) are retried;
+// betweenPolls, if not nil, runs after every poll that did not finish the wait.
+func (g *GatewayManagement) WaitForChange(t *testing.T, changeID int64, maxRetries int, interval time.Duration, betweenPolls func()) (*ClusterTopology, error) {
+	t.Helper()
+
+	var lastErr error
+	for i := 0; i < maxRetries; i++ {
+		topology, err := g.Topology(t)
+		switch {
+		case err != nil:
+			lastErr = err
+			t.Logf("[GATEWAY] cluster status request failed (attempt %d/%d): %v", i+1, maxRetries, err)
+		case topology.PendingChange != nil && topology.PendingChange.Id == changeID:
+			pending := topology.PendingChange
+			lastErr = fmt.Errorf("change %d is %s with %d operations pending", changeID, pending.Status, len(pending.Pending))
+			t.Logf("[GATEWAY] change %d is %s: %d operations completed, %d pending (attempt %d/%d)", changeID, pending.Status, len(pending.Completed), len(pending.Pending), i+1, maxRetries)
+		case topology.LastChange != nil && topology.LastChange.Id == changeID:
+			switch topology.LastChange.Status {
+			case ClusterChangeCompleted:
+				return topology, nil
+			case ClusterChangeFailed, ClusterChangeCancelled:
+				return topology, fmt.Errorf("change %d is %s", changeID, topology.LastChange.Status)
+			}
+			lastErr = fmt.Errorf("change %d is %s", changeID, topology.LastChange.Status)
+		case topology.LastChange != nil && topology.LastChange.Id > changeID:
+			// A later change only starts once this one is done, but the topology
+			// no longer records whether it completed, failed or was cancelled.
+			return topology, fmt.Errorf("change %d was superseded by change %d (%s); its outcome is unknown",
+				changeID, topology.LastChange.Id, topology.LastChange.Status)
+		default:
+			lastErr = fmt.Errorf("change %d has not started yet", changeID)
+			t.Logf("[GATEWAY] change %d has not started yet (attempt %d/%d)", changeID, i+1, maxRetries)
+		}
+		if betweenPolls != nil {
+			betweenPolls()
+		}
+		time.Sleep(interval)
+	}
+	return nil, fmt.Errorf("change %d did not complete after %d attempts: %w", changeID, maxRetries, lastErr)
+}
+
+// PauseExporting pauses exporting on all partitions. A soft pause keeps exporting
+// records to the exporters but stops acknowledging them, so they are exported
+// again after resuming.
+func (g *GatewayManagement) PauseExporting(t *testing.T, soft bool) error {
+	t.Helper()
+
+	path := "/actuator/exporting/pause"
+	if soft {
+		path += "?soft=true"
+	}
+	return g.mutate(t, "POST", path, nil, nil)
+}
+
+// Partitions returns the partitions of the broker that answers, keyed by partition ID.
+func (g *GatewayManagement) Partitions(t *testing.T) (map[string]PartitionStatus, error) {
+	t.Helper()
+
+	var partitions map[string]PartitionStatus
+	if err := g.get(t, "/actuator/partitions", &partitions); err != nil {
+		return nil, err
+	}
+	return partitions, nil
+}
+
+// ExportingPaused reports whether exporting is paused, hard or soft. Pausing applies
+// to all partitions at once, so the partitions of the broker that answers suffice.
+func (g *GatewayManagement) ExportingPaused(t *testing.T) (bool, error) {
+	t.Helper()
+
+	partitions, err := g.Partitions(t)
+	if err != nil {
+		return false, err
+	}
+	for _, p := range partitions {
+		if p.ExporterPhase == ExporterPhasePaused || p.ExporterPhase == ExporterPhaseSoftPaused {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ResumeExporting resumes exporting on all partitions.
+func (g *GatewayManagement) ResumeExporting(t *testing.T) error {
+	t.Helper()
+	return g.mutate(t, "POST", "/actuator/exporting/resume", nil, nil)
+}
+
+// Exporters returns the configured exporters and whether they are enabled.
+func (g *GatewayManagement) Exporters(t *testing.T) ([]ExporterStatus, error) {
+	t.Helper()
+
+	var exporters []ExporterStatus
+	if err := g.get(t, "/actuator/exporters", &exporters); err != nil {
+		return nil, err
+	}
+	return exporters, nil
+}
+
+// DisableExporter disables an exporter on all partitions.
+func (g *GatewayManagement) DisableExporter(t *testing.T, exporterID string) (*ClusterChangePlan, error) {
+	t.Helper()
+
+	var plan ClusterChangePlan
+	if err := g.mutate(t, "POST", fmt.Sprintf("/actuator/exporters/%s/disable", url.PathEscape(exporterID)), nil, &plan); err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// EnableExporter enables an exporter on all partitions. With initializeFrom set it
+// starts from the position of that exporter instead of its own last position.
+func (g *GatewayManagement) EnableExporter(t *testing.T, exporterID, initializeFrom string) (*ClusterChangePlan, error) {
+	t.Helper()
+
+	var payload interface{}
+	if initializeFrom != "" {
+		payload = map[string]string{"initializeFrom": initializeFrom}
+	}
+	var plan ClusterChangePlan
+	if err := g.mutate(t, "POST", fmt.Sprintf("/actuator/exporters/%s/enable", url.PathEscape(exporterID)), payload, &plan); err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func (g *GatewayManagement) get(t *testing.T, path string, out interface{}) error {
+	t.Helper()
+
+	status, body, err := gatewayManagementRequest(t, g.kubectlOptions, "GET", path, nil)
+	return decodeGatewayResponse("GET", path, status, body, err, out)
+}
+
+// mutate sends payload (if not nil) as JSON with retries and decodes a 2xx
+// response into out (if not nil).
+func (g *GatewayManagement) mutate(t *testing.T, method, path string, payload, out interface{}) error {
+	t.Helper()
+
+	var b []byte
+	if payload != nil {
+		var err error
+		if b, err = json.Marshal(payload); err != nil {
+			return err
+		}
+	}
+	status, body, err := gatewayManagementMutate(t, g.kubectlOptions, method, path, b, g.MaxRetries, g.Backoff)
+	return decodeGatewayResponse(method, path, status, body, err, out)
+}
+
+func decodeGatewayResponse(method, path string, status int, body string, err error, out interface{}) error {
+	if err != nil {
+		return fmt.Errorf("gateway %s %s: %w", method, path, err)
+	}
+	if status < 200 || status > 299 {
+		gwErr := &GatewayManagementError{StatusCode: status, Method: method, Path: path, Body: strings.TrimSpace(body)}
+		var problem struct {
+			Title  string `json:"title"`
+			Detail string `json:"detail"`
+		}
+		if json.Unmarshal([]byte(body), &problem) == nil {
+			gwErr.Title = problem.Title
+			gwErr.Detail = problem.Detail
+		}
+		return gwErr
+	}
+	if out == nil || body == "" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(body), out); err != nil {
+		return fmt.Errorf("gateway %s %s: decoding response: %w", method, path, err)
+	}
+	return nil
+}
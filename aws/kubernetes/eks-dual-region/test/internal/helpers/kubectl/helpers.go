@@ -48,16 +48,16 @@ func basicAuthDemoHeader() string {
 }
 
 var (
-	// ElasticsearchPodName is the ECK-managed Elasticsearch pod used for exec operations (backup, restore, health checks).
-	// Override via ELASTICSEARCH_POD_NAME env var.
-	ElasticsearchPodName = helpers.GetEnv("ELASTICSEARCH_POD_NAME", "elasticsearch-es-masters-0")
-
 	// ElasticsearchServiceName is the ECK-managed Elasticsearch headless service name used when rewriting
 	// cross-region exporter URLs in camunda-values.yml placeholders. Must match the service name used by
 	// generate_zeebe_helm_values.sh (which also reads ELASTICSEARCH_SERVICE_NAME).
 	// Uses the headless service (not ClusterIP) so DNS returns pod IPs that are routable cross-cluster.
 	// Override via ELASTICSEARCH_SERVICE_NAME env var.
 	ElasticsearchServiceName = helpers.GetEnv("ELASTICSEARCH_SERVICE_NAME", "elasticsearch-es-masters")
+
+	// ElasticsearchHTTPServiceName is the ECK-managed ClusterIP service the Elasticsearch client
+	// port-forwards to (see NewElasticsearch). Override via ELASTICSEARCH_HTTP_SERVICE_NAME env var.
+	ElasticsearchHTTPServiceName = helpers.GetEnv("ELASTICSEARCH_HTTP_SERVICE_NAME", "elasticsearch-es-http")
 )
 
 // getElasticsearchPassword retrieves the elastic user password from the ECK-generated secret.
@@ -130,9 +130,15 @@ type ClusterInfo struct {
 }
 
 type ElasticsearchClusterHealth struct {
-	ClusterName string `json:"cluster_name"`
-	Status      string `json:"status"`
-	TimedOut    bool   `json:"timed_out"`
+	ClusterName         string  `json:"cluster_name"`
+	Status              string  `json:"status"`
+	TimedOut            bool    `json:"timed_out"`
+	NumberOfNodes       int     `json:"number_of_nodes"`
+	ActiveShards        int     `json:"active_shards"`
+	RelocatingShards    int     `json:"relocating_shards"`
+	InitializingShards  int     `json:"initializing_shards"`
+	UnassignedShards    int     `json:"unassigned_shards"`
+	ActiveShardsPercent float64 `json:"active_shards_percent_as_number"`
 }
 
 // NewServiceTunnelWithRetry establishes a port-forward tunnel to a Kubernetes Service with retry logic.
@@ -517,107 +523,77 @@ func RunSensitiveKubectlCommand(t *testing.T, kubectlOptions *k8s.KubectlOptions
 func ConfigureElasticBackup(t *testing.T, cluster helpers.Cluster, backupBucket, inputVersion string) {
 	t.Logf("[ELASTICSEARCH] Configuring Elasticsearch backup for cluster %s", cluster.ClusterName)
 
-	esPassword := getElasticsearchPassword(t, &cluster.KubectlNamespace)
+	es := NewElasticsearch(t, &cluster.KubectlNamespace)
+	defer es.Close()
 
 	// Replace dots with dashes in the version string.
 	version := strings.ReplaceAll(inputVersion, ".", "-")
 
-	output, err := k8s.RunKubectlAndGetOutputE(t, &cluster.KubectlNamespace, "exec", ElasticsearchPodName, "--",
-		"curl", "-u", fmt.Sprintf("elastic:%s", esPassword), "-XPUT", "http://localhost:9200/_snapshot/camunda_backup",
-		"-H", "Content-Type: application/json",
-		"-d", fmt.Sprintf("{\"type\": \"s3\", \"settings\": {\"bucket\": \"%s\", \"client\": \"camunda\", \"base_path\": \"%s/%s-backups\"}}",
-			backupBucket, backupBucket, version))
-
-	if err != nil {
-		t.Fatalf("[ELASTICSEARCH] Error: %s", err)
-		return
-	}
-
-	if !strings.Contains(output, "acknowledged") {
-		t.Fatalf("[ELASTICSEARCH] Error: %s", output)
-		return
+	settings := S3RepositorySettings{
+		Bucket:   backupBucket,
+		Client:   "camunda",
+		BasePath: fmt.Sprintf("%s/%s-backups", backupBucket, version),
 	}
-
-	require.Contains(t, output, "acknowledged")
-	t.Logf("[ELASTICSEARCH] Success: %s", output)
+	err := es.PutS3Repository(ElasticBackupRepository, settings)
+	require.NoError(t, err, "[ELASTICSEARCH] registering repository %s", ElasticBackupRepository)
+	t.Logf("[ELASTICSEARCH] Registered repository %s at s3://%s/%s", ElasticBackupRepository, settings.Bucket, settings.BasePath)
 }
 
 func CreateElasticBackup(t *testing.T, cluster helpers.Cluster, backupName string) {
 	t.Logf("[ELASTICSEARCH BACKUP] Creating Elasticsearch backup for cluster %s", cluster.ClusterName)
 
-	esPassword := getElasticsearchPassword(t, &cluster.KubectlNamespace)
-
-	// Delete any pre-existing snapshot with the same name to avoid snapshot_name_already_in_use_exception
-	k8s.RunKubectlAndGetOutputE(t, &cluster.KubectlNamespace, "exec", ElasticsearchPodName, "--", "curl", "-u", fmt.Sprintf("elastic:%s", esPassword), "-X", "DELETE", fmt.Sprintf("localhost:9200/_snapshot/camunda_backup/%s", backupName))
-
-	output, err := k8s.RunKubectlAndGetOutputE(t, &cluster.KubectlNamespace, "exec", ElasticsearchPodName, "--", "curl", "-u", fmt.Sprintf("elastic:%s", esPassword), "-X", "PUT", fmt.Sprintf("localhost:9200/_snapshot/camunda_backup/%s?wait_for_completion=true", backupName), "-H", "Content-Type: application/json", "-d", `{"include_global_state":true}`)
-	if err != nil {
-		t.Fatalf("[ELASTICSEARCH BACKUP] %s", err)
-		return
-	}
+	es := NewElasticsearch(t, &cluster.KubectlNamespace)
+	defer es.Close()
 
-	require.Contains(t, output, "\"failed\":0")
-	t.Logf("[ELASTICSEARCH BACKUP] Created backup: %s", output)
+	info, err := NewSnapshotManager(es, ElasticBackupRepository).Create(t, backupName)
+	require.NoError(t, err, "[ELASTICSEARCH BACKUP] creating snapshot %s", backupName)
+	t.Logf("[ELASTICSEARCH BACKUP] Created backup %s: %d indices, %d/%d shards", info.Snapshot, len(info.Indices), info.Shards.Successful, info.Shards.Total)
 }
 
-func CheckThatElasticBackupIsPresent(t *testing.T, cluster helpers.Cluster, backupName, backupBucket, remoteChartVersion string) {
+// CheckThatElasticBackupIsPresent verifies the backup repository of the cluster and
+// waits until backupName shows up in it as a complete snapshot. The repository of
+// the secondary region points at the same bucket as the primary one, so this is how
+// the secondary learns about a snapshot taken in the primary region.
+func CheckThatElasticBackupIsPresent(t *testing.T, cluster helpers.Cluster, backupName string) {
 	t.Logf("[ELASTICSEARCH BACKUP] Checking that Elasticsearch backup is present for cluster %s", cluster.ClusterName)
 
-	output := ""
-	var err error
-
-	for i := 0; i < 3; i++ {
-		output, err = getAllElasticBackups(t, cluster)
-		if err == nil && output != "" {
-			break
-		}
-		removeElasticBackup(t, cluster)
-		time.Sleep(5 * time.Second)
-		ConfigureElasticBackup(t, cluster, backupBucket, remoteChartVersion)
-		time.Sleep(5 * time.Second)
-	}
-
-	require.Contains(t, output, backupName)
-	t.Logf("[ELASTICSEARCH BACKUP] Backup present: %s", output)
-}
-
-func removeElasticBackup(t *testing.T, cluster helpers.Cluster) {
-	t.Logf("[ELASTICSEARCH BACKUP] Backup not found, removing backup store to recreate %s", cluster.ClusterName)
+	es := NewElasticsearch(t, &cluster.KubectlNamespace)
+	defer es.Close()
 
-	esPassword := getElasticsearchPassword(t, &cluster.KubectlNamespace)
+	manager := NewSnapshotManager(es, ElasticBackupRepository)
+	manager.Timeout = 5 * time.Minute
+	require.NoError(t, manager.VerifyRepository(t), "[ELASTICSEARCH BACKUP] repository %s is not usable", ElasticBackupRepository)
 
-	k8s.RunKubectl(t, &cluster.KubectlNamespace, "exec", ElasticsearchPodName, "--", "curl", "-u", fmt.Sprintf("elastic:%s", esPassword), "-XDELETE", "localhost:9200/_snapshot/camunda_backup")
+	info, err := manager.Wait(t, backupName)
+	require.NoError(t, err, "[ELASTICSEARCH BACKUP] snapshot %s is not usable", backupName)
+	t.Logf("[ELASTICSEARCH BACKUP] Backup present: %s (%s, %d indices)", info.Snapshot, info.State, len(info.Indices))
 }
 
-func getAllElasticBackups(t *testing.T, cluster helpers.Cluster) (string, error) {
-	esPassword := getElasticsearchPassword(t, &cluster.KubectlNamespace)
+// PruneElasticBackups deletes old snapshots from the backup repository of the cluster
+// according to retention, so the shared bucket does not grow across nightly runs.
+func PruneElasticBackups(t *testing.T, cluster helpers.Cluster, retention SnapshotRetention) {
+	t.Logf("[ELASTICSEARCH BACKUP] Pruning Elasticsearch backups for cluster %s (max age %s, max count %d)", cluster.ClusterName, retention.MaxAge, retention.MaxCount)
 
-	output, err := k8s.RunKubectlAndGetOutputE(t, &cluster.KubectlNamespace, "exec", ElasticsearchPodName, "--", "curl", "-u", fmt.Sprintf("elastic:%s", esPassword), "-XGET", "localhost:9200/_snapshot/camunda_backup/_all")
-	if err != nil {
-		t.Fatalf("[ELASTICSEARCH BACKUP] %s", err)
-		return "", err
-	}
+	es := NewElasticsearch(t, &cluster.KubectlNamespace)
+	defer es.Close()
 
-	return output, nil
+	deleted, err := NewSnapshotManager(es, ElasticBackupRepository).Prune(t, retention)
+	require.NoError(t, err, "[ELASTICSEARCH BACKUP] pruning snapshots")
+	t.Logf("[ELASTICSEARCH BACKUP] Pruned %d snapshots: %v", len(deleted), deleted)
 }
 
 func RestoreElasticBackup(t *testing.T, cluster helpers.Cluster, backupName string) {
 	t.Logf("[ELASTICSEARCH BACKUP] Restoring Elasticsearch backup for cluster %s", cluster.ClusterName)
 
-	esPassword := getElasticsearchPassword(t, &cluster.KubectlNamespace)
-
-	output, err := k8s.RunKubectlAndGetOutputE(t, &cluster.KubectlNamespace, "exec", ElasticsearchPodName, "--", "curl", "-u", fmt.Sprintf("elastic:%s", esPassword), "-XPOST", fmt.Sprintf("localhost:9200/_snapshot/camunda_backup/%s/_restore?wait_for_completion=true", backupName), "-H", "Content-Type: application/json", "-d", `{"include_global_state":true}`)
-	if err != nil {
-		t.Fatalf("[ELASTICSEARCH BACKUP] %s", err)
-		return
-	}
-
-	require.Contains(t, output, "\"failed\":0")
-	t.Logf("[ELASTICSEARCH BACKUP] Restored backup: %s", output)
+	es := NewElasticsearch(t, &cluster.KubectlNamespace)
+	defer es.Close()
 
+	info, err := es.RestoreSnapshot(ElasticBackupRepository, backupName)
+	require.NoError(t, err, "[ELASTICSEARCH BACKUP] restoring snapshot %s", backupName)
+	t.Logf("[ELASTICSEARCH BACKUP] Restored backup %s: %d indices, %d/%d shards", info.Snapshot, len(info.Indices), info.Shards.Successful, info.Shards.Total)
 }
 
-func InstallUpgradeC8Helm(t *testing.T, kubectlOptions *k8s.KubectlOptions, remoteChartVersion, remoteChartName, remoteChartSource, namespace0, namespace1 string, valuesYamlFiles []string, region int, setValues, setStringValues map[string]string) {
+func InstallUpgradeC8Helm(t *testing.T, kubectlOptions *k8s.KubectlOptions, remoteChartVersion, remoteChartName, remoteChartSource, namespace0, namespace1, backupBucket string, valuesYamlFiles []string, region int, setValues, setStringValues map[string]string) {
 
 	if !helpers.IsTeleportEnabled() {
 		// Set environment variables for the script
@@ -668,6 +644,7 @@ func InstallUpgradeC8Helm(t *testing.T, kubectlOptions *k8s.KubectlOptions, remo
 	modifiedContent := strings.Replace(fileContent, "PLACEHOLDER", initialContact, -1)
 	modifiedContent = strings.Replace(modifiedContent, fmt.Sprintf("http://%s.camunda-primary.svc.cluster.local:9200", ElasticsearchServiceName), elastic0, -1)
 	modifiedContent = strings.Replace(modifiedContent, fmt.Sprintf("http://%s.camunda-secondary.svc.cluster.local:9200", ElasticsearchServiceName), elastic1, -1)
+	modifiedContent = strings.Replace(modifiedContent, ZeebeBackupBucketPlaceholder, backupBucket, -1)
 
 	// Write the modified content back to the file
 	err = os.WriteFile(filePath, []byte(modifiedContent), 0644)
@@ -1259,15 +1236,12 @@ func VerifyExporterStatus(t *testing.T, cluster helpers.Cluster) {
 	t.Helper()
 	t.Logf("[EXPORTER STATUS] Checking exporter status for %s", cluster.ClusterName)
 
-	endpoint, closeFn := NewServiceTunnelWithRetry(t, &cluster.KubectlNamespace, "camunda-zeebe-gateway", 0, 9600, 5, 15*time.Second)
-	defer closeFn()
-
 	// Check individual exporter status
-	res, body := helpers.HttpRequest(t, "GET", fmt.Sprintf("http://%s/actuator/exporters", endpoint), nil)
-	require.NotNil(t, res, "[EXPORTER STATUS] Failed to get exporter status - HTTP request returned nil")
-	t.Logf("[EXPORTER STATUS] Exporters: %s", body)
-	require.Equal(t, 200, res.StatusCode)
-	require.Contains(t, body, "\"status\":\"ENABLED\"", "Expected at least one enabled exporter")
+	exporters, err := NewGatewayManagement(&cluster.KubectlNamespace).Exporters(t)
+	require.NoError(t, err, "[EXPORTER STATUS] Failed to get exporter status")
+	t.Logf("[EXPORTER STATUS] Exporters: %+v", exporters)
+	require.Contains(t, exporters, ExporterStatus{ExporterId: "camundaregion0", Status: "ENABLED"})
+	require.Contains(t, exporters, ExporterStatus{ExporterId: "camundaregion1", Status: "ENABLED"})
 }
 
 // CheckElasticsearchProcessInstanceCount queries ES directly for the number of process instance documents.
@@ -1276,75 +1250,38 @@ func CheckElasticsearchProcessInstanceCount(t *testing.T, cluster helpers.Cluste
 	t.Helper()
 	t.Logf("[ES DEBUG] Checking process instance count in ES for %s", cluster.ClusterName)
 
-	esPassword := getElasticsearchPassword(t, &cluster.KubectlNamespace)
-
-	output, err := k8s.RunKubectlAndGetOutputE(
-		t,
-		&cluster.KubectlNamespace,
-		"exec",
-		ElasticsearchPodName,
-		"-c",
-		"elasticsearch",
-		"--",
-		"curl",
-		"-u", fmt.Sprintf("elastic:%s", esPassword),
-		"-s",
-		"localhost:9200/*process*/_count",
-	)
+	es := NewElasticsearch(t, &cluster.KubectlNamespace)
+	defer es.Close()
+
+	count, err := es.Count("*process*")
 	if err != nil {
 		t.Logf("[ES DEBUG] Failed to query ES: %v", err)
 		return
 	}
-	t.Logf("[ES DEBUG] ES process instance count: %s", output)
+	t.Logf("[ES DEBUG] ES process instance count: %d", count)
 }
 
 func CheckElasticsearchClusterHealth(t *testing.T, cluster helpers.Cluster) {
 	t.Logf("[ELASTICSEARCH HEALTH] Checking cluster health for %s", cluster.ClusterName)
 
-	esPassword := getElasticsearchPassword(t, &cluster.KubectlNamespace)
+	es := NewElasticsearch(t, &cluster.KubectlNamespace)
+	defer es.Close()
 
-	var output string
+	var health *ElasticsearchClusterHealth
 	var err error
 
 	// Retry up to 10 times with 15 second intervals to allow for cluster stabilization
 	for i := 0; i < 10; i++ {
-		output, err = k8s.RunKubectlAndGetOutputE(
-			t,
-			&cluster.KubectlNamespace,
-			"exec",
-			ElasticsearchPodName,
-			"-c",
-			"elasticsearch",
-			"--",
-			"curl",
-			"-u", fmt.Sprintf("elastic:%s", esPassword),
-			"-s",
-			"localhost:9200/_cluster/health",
-		)
-
+		health, err = es.ClusterHealth()
 		if err != nil {
-			t.Logf("[ELASTICSEARCH HEALTH] Attempt %d/10: Error executing command: %v", i+1, err)
+			t.Logf("[ELASTICSEARCH HEALTH] Attempt %d/10: %v", i+1, err)
 			if i < 9 {
 				time.Sleep(15 * time.Second)
-				continue
 			}
-			t.Fatalf("[ELASTICSEARCH HEALTH] Failed to get cluster health after 10 attempts: %v", err)
-			return
-		}
-
-		// Parse the JSON response
-		var health ElasticsearchClusterHealth
-		if err := json.Unmarshal([]byte(output), &health); err != nil {
-			t.Logf("[ELASTICSEARCH HEALTH] Attempt %d/10: Failed to parse response: %v", i+1, err)
-			if i < 9 {
-				time.Sleep(15 * time.Second)
-				continue
-			}
-			t.Fatalf("[ELASTICSEARCH HEALTH] Failed to parse health response after 10 attempts: %v", err)
-			return
+			continue
 		}
 
-		t.Logf("[ELASTICSEARCH HEALTH] Attempt %d/10: Status = %s", i+1, health.Status)
+		t.Logf("[ELASTICSEARCH HEALTH] Attempt %d/10: Status = %s (nodes=%d, unassigned shards=%d)", i+1, health.Status, health.NumberOfNodes, health.UnassignedShards)
 
 		// Check if status is green (case-insensitive)
 		if strings.ToLower(health.Status) == "green" {
@@ -1359,7 +1296,11 @@ func CheckElasticsearchClusterHealth(t *testing.T, cluster helpers.Cluster) {
 		}
 	}
 
-	t.Fatalf("[ELASTICSEARCH HEALTH] Cluster did not reach green status after 10 attempts. Last output: %s", output)
+	if health == nil {
+		t.Fatalf("[ELASTICSEARCH HEALTH] Failed to get cluster health after 10 attempts: %v", err)
+		return
+	}
+	t.Fatalf("[ELASTICSEARCH HEALTH] Cluster did not reach green status after 10 attempts. Last status: %s (unassigned shards=%d, last error: %v)", health.Status, health.UnassignedShards, err)
 }
 
 // WaitForGatewayAuthReady polls the Zeebe gateway /v2/topology endpoint with basic auth
@@ -1678,13 +1619,13 @@ func fetchTopologyConvergence(t *testing.T, kubectlOptions *k8s.KubectlOptions,
 	return len(topology.Brokers) == expectedBrokers && primary == perRegion && secondary == perRegion && unhealthy == 0, summary
 }
 
-// GatewayManagementRequest issues a request against the Zeebe gateway management
+// gatewayManagementRequest issues a request against the Zeebe gateway management
 // API (port 9600), establishing a fresh, short-lived port-forward for the call.
 // A long-lived tunnel breaks when the broker pod it targets restarts — which
 // happens during partition redistribution on a broker-scaling change — so a
 // per-call tunnel (which re-selects a currently-ready pod) plus a non-fatal error
 // return lets callers retry instead of failing on a dropped connection.
-func GatewayManagementRequest(t *testing.T, kubectlOptions *k8s.KubectlOptions, method, path string, payload []byte) (int, string, error) {
+func gatewayManagementRequest(t *testing.T, kubectlOptions *k8s.KubectlOptions, method, path string, payload []byte) (int, string, error) {
 	t.Helper()
 
 	endpoint, closeFn := NewServiceTunnelWithRetry(t, kubectlOptions, "camunda-zeebe-gateway", 0, 9600, 8, 15*time.Second)
@@ -1716,7 +1657,7 @@ func GatewayManagementRequest(t *testing.T, kubectlOptions *k8s.KubectlOptions,
 	return resp.StatusCode, string(b), nil
 }
 
-// GatewayManagementMutate issues a mutating gateway management request (a broker
+// gatewayManagementMutate issues a mutating gateway management request (a broker
 // scaling PATCH or an exporter enable/disable POST) and retries it on connection
 // errors and transient 5xx responses. While partitions are being redistributed
 // the gateway can briefly reject a mutating request with a 500 (the change is not
@@ -1725,14 +1666,14 @@ func GatewayManagementRequest(t *testing.T, kubectlOptions *k8s.KubectlOptions,
 // request is accepted (202), a definitive non-transient (<500) status is returned
 // — which the caller then asserts on — or the retry budget is exhausted. It
 // returns the last status, body and error observed.
-func GatewayManagementMutate(t *testing.T, kubectlOptions *k8s.KubectlOptions, method, path string, payload []byte, maxRetries int, backoff time.Duration) (int, string, error) {
+func gatewayManagementMutate(t *testing.T, kubectlOptions *k8s.KubectlOptions, method, path string, payload []byte, maxRetries int, backoff time.Duration) (int, string, error) {
 	t.Helper()
 
 	var status int
 	var body string
 	var err error
 	for i := 0; i < maxRetries; i++ {
-		status, body, err = GatewayManagementRequest(t, kubectlOptions, method, path, payload)
+		status, body, err = gatewayManagementRequest(t, kubectlOptions, method, path, payload)
 		if err == nil && status < 500 {
 			// 202 (accepted) or a definitive client-side status: stop retrying and
 			// let the caller assert on the result.
@@ -0,0 +1,216 @@
+package kubectlHelpers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Snapshot states reported by GET _snapshot/<repository>/<snapshot>.
+const (
+	SnapshotInProgress   = "IN_PROGRESS"
+	SnapshotSuccess      = "SUCCESS"
+	SnapshotPartial      = "PARTIAL"
+	SnapshotFailed       = "FAILED"
+	SnapshotIncompatible = "INCOMPATIBLE"
+)
+
+// SnapshotManager manages the lifecycle of the snapshots in one repository of one
+// region: it creates snapshots after verifying the repository, waits for them to
+// reach a final state, checks the shards of every index and prunes old snapshots.
+type SnapshotManager struct {
+	es         *Elasticsearch
+	Repository string
+	// PollInterval is the delay between two status polls in Wait.
+	PollInterval time.Duration
+	// Timeout bounds Wait, including the time a snapshot takes to show up in the
+	// repository listing of another region.
+	Timeout time.Duration
+}
+
+// NewSnapshotManager returns a SnapshotManager for repository that polls every 10s
+// for up to 30 minutes.
+func NewSnapshotManager(es *Elasticsearch, repository string) *SnapshotManager {
+	return &SnapshotManager{es: es, Repository: repository, PollInterval: 10 * time.Second, Timeout: elasticsearchSnapshotTimeout}
+}
+
+// SnapshotRetention selects the snapshots Prune deletes: those older than MaxAge and
+// those beyond the newest MaxCount survivors. A zero value disables the respective
+// rule. Snapshots named in Keep, snapshots still in progress and the newest finished
+// snapshot are never deleted, so a repository is never pruned empty; they take up
+// survivor slots like any other snapshot.
+type SnapshotRetention struct {
+	MaxAge   time.Duration
+	MaxCount int
+	Keep     []string
+}
+
+// VerifyRepository checks that every node can read and write the repository, so a
+// missing bucket or broken S3 credentials fail before a snapshot is attempted.
+func (m *SnapshotManager) VerifyRepository(t *testing.T) error {
+	t.Helper()
+
+	nodes, err := m.es.VerifyRepository(m.Repository)
+	if err != nil {
+		return fmt.Errorf("verifying repository %s: %w", m.Repository, err)
+	}
+	if len(nodes) == 0 {
+		return fmt.Errorf("verifying repository %s: no node verified it", m.Repository)
+	}
+	t.Logf("[SNAPSHOT] Repository %s verified by %s", m.Repository, strings.Join(nodes, ", "))
+	return nil
+}
+
+// Create verifies the repository, replaces any snapshot with the same name and
+// waits for the new snapshot to succeed (see Wait).
+func (m *SnapshotManager) Create(t *testing.T, snapshot string) (*SnapshotInfo, error) {
+	t.Helper()
+
+	if err := m.VerifyRepository(t); err != nil {
+		return nil, err
+	}
+	// Avoid snapshot_name_already_in_use_exception on reruns with the same name.
+	if err := m.es.DeleteSnapshot(m.Repository, snapshot); err != nil && !IsElasticsearchNotFound(err) {
+		return nil, fmt.Errorf("deleting previous snapshot %s: %w", snapshot, err)
+	}
+	if err := m.es.StartSnapshot(m.Repository, snapshot); err != nil {
+		return nil, fmt.Errorf("starting snapshot %s: %w", snapshot, err)
+	}
+	t.Logf("[SNAPSHOT] Started snapshot %s in %s", snapshot, m.Repository)
+	return m.Wait(t, snapshot)
+}
+
+// Wait polls the snapshot until it is SUCCESS, PARTIAL, FAILED or INCOMPATIBLE and
+// then checks the shard counts of every index. A snapshot that does not exist yet is
+// polled like one in progress. Anything but a SUCCESS snapshot whose shards are all
+// done is returned as an error, together with the last snapshot info seen.
+func (m *SnapshotManager) Wait(t *testing.T, snapshot string) (*SnapshotInfo, error) {
+	t.Helper()
+
+	deadline := time.Now().Add(m.Timeout)
+	var info *SnapshotInfo
+	for {
+		var err error
+		info, err = m.es.Snapshot(m.Repository, snapshot)
+		switch {
+		case err != nil:
+			t.Logf("[SNAPSHOT] Snapshot %s not available yet: %v", snapshot, err)
+		case info.State == SnapshotInProgress:
+			t.Logf("[SNAPSHOT] Snapshot %s is %s", snapshot, info.State)
+		default:
+			return info, m.checkSnapshot(t, info)
+		}
+		if time.Now().After(deadline) {
+			if err != nil {
+				return nil, fmt.Errorf("snapshot %s did not complete within %s: %w", snapshot, m.Timeout, err)
+			}
+			return info, fmt.Errorf("snapshot %s did not complete within %s: still %s", snapshot, m.Timeout, info.State)
+		}
+		time.Sleep(m.PollInterval)
+	}
+}
+
+// checkSnapshot fails a snapshot in a final state that is not SUCCESS, and verifies
+// that every index has all of its shards done.
+func (m *SnapshotManager) checkSnapshot(t *testing.T, info *SnapshotInfo) error {
+	t.Helper()
+
+	if info.State != SnapshotSuccess {
+		var failures []string
+		for _, f := range info.Failures {
+			failures = append(failures, fmt.Sprintf("%s[%d]: %s", f.Index, f.ShardId, f.Reason))
+		}
+		return fmt.Errorf("snapshot %s is %s (%d of %d shards failed): %s", info.Snapshot, info.State, info.Shards.Failed, info.Shards.Total, strings.Join(failures, "; "))
+	}
+
+	status, err := m.es.SnapshotStatus(m.Repository, info.Snapshot)
+	if err != nil {
+		return fmt.Errorf("reading status of snapshot %s: %w", info.Snapshot, err)
+	}
+	var incomplete []string
+	for _, index := range info.Indices {
+		s, ok := status.Indices[index]
+		if !ok {
+			incomplete = append(incomplete, index+": missing from status")
+			continue
+		}
+		stats := s.ShardsStats
+		if stats.Total == 0 || stats.Failed > 0 || stats.Done != stats.Total {
+			incomplete = append(incomplete, fmt.Sprintf("%s: %d/%d shards done, %d failed", index, stats.Done, stats.Total, stats.Failed))
+		}
+	}
+	if len(incomplete) > 0 {
+		sort.Strings(incomplete)
+		return fmt.Errorf("snapshot %s has incomplete indices: %s", info.Snapshot, strings.Join(incomplete, "; "))
+	}
+	t.Logf("[SNAPSHOT] Snapshot %s is %s: %d indices, %d/%d shards done", info.Snapshot, info.State, len(info.Indices), status.ShardsStats.Done, status.ShardsStats.Total)
+	return nil
+}
+
+// List returns the snapshots of the repository, oldest first.
+func (m *SnapshotManager) List() ([]SnapshotInfo, error) {
+	snapshots, err := m.es.Snapshots(m.Repository)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].StartTimeInMillis < snapshots[j].StartTimeInMillis
+	})
+	return snapshots, nil
+}
+
+// Prune deletes the snapshots selected by retention and returns their names.
+func (m *SnapshotManager) Prune(t *testing.T, retention SnapshotRetention) ([]string, error) {
+	t.Helper()
+
+	snapshots, err := m.List()
+	if err != nil {
+		return nil, fmt.Errorf("listing snapshots in %s: %w", m.Repository, err)
+	}
+	var deleted []string
+	for _, name := range selectSnapshotsToPrune(snapshots, retention, time.Now()) {
+		if err := m.es.DeleteSnapshot(m.Repository, name); err != nil && !IsElasticsearchNotFound(err) {
+			return deleted, fmt.Errorf("deleting snapshot %s: %w", name, err)
+		}
+		t.Logf("[SNAPSHOT] Pruned snapshot %s from %s", name, m.Repository)
+		deleted = append(deleted, name)
+	}
+	return deleted, nil
+}
+
+// selectSnapshotsToPrune returns the names of the snapshots (sorted oldest first)
+// that retention deletes at now.
+func selectSnapshotsToPrune(snapshots []SnapshotInfo, retention SnapshotRetention, now time.Time) []string {
+	keep := map[string]bool{}
+	for _, name := range retention.Keep {
+		keep[name] = true
+	}
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		if snapshots[i].State != SnapshotInProgress {
+			keep[snapshots[i].Snapshot] = true
+			break
+		}
+	}
+
+	// Walk newest first: every snapshot that survives, kept ones included, takes
+	// one of the MaxCount slots, and a candidate goes once they are all taken.
+	var names []string
+	survivors := 0
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		s := snapshots[i]
+		if keep[s.Snapshot] || s.State == SnapshotInProgress {
+			survivors++
+			continue
+		}
+		tooOld := retention.MaxAge > 0 && now.Sub(time.UnixMilli(s.StartTimeInMillis)) > retention.MaxAge
+		tooMany := retention.MaxCount > 0 && survivors >= retention.MaxCount
+		if tooOld || tooMany {
+			names = append([]string{s.Snapshot}, names...)
+			continue
+		}
+		survivors++
+	}
+	return names
+}
@@ -0,0 +1,111 @@
+package kubectlHelpers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectSnapshotsToPrune(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	snapshot := func(name string, age time.Duration, state string) SnapshotInfo {
+		return SnapshotInfo{Snapshot: name, State: state, StartTimeInMillis: now.Add(-age).UnixMilli()}
+	}
+	// Oldest first, as SnapshotManager.List returns them.
+	finished := []SnapshotInfo{
+		snapshot("s1", 10*day, SnapshotSuccess),
+		snapshot("s2", 8*day, SnapshotPartial),
+		snapshot("s3", 2*day, SnapshotSuccess),
+		snapshot("s4", day, SnapshotFailed),
+		snapshot("s5", time.Hour, SnapshotSuccess),
+	}
+
+	for _, tc := range []struct {
+		name      string
+		snapshots []SnapshotInfo
+		retention SnapshotRetention
+		want      []string
+	}{
+		{
+			name:      "no rules",
+			snapshots: finished,
+		},
+		{
+			name:      "by count",
+			snapshots: finished,
+			retention: SnapshotRetention{MaxCount: 3},
+			want:      []string{"s1", "s2"},
+		},
+		{
+			name:      "count not exceeded",
+			snapshots: finished,
+			retention: SnapshotRetention{MaxCount: 5},
+		},
+		{
+			name:      "by age",
+			snapshots: finished,
+			retention: SnapshotRetention{MaxAge: 7 * day},
+			want:      []string{"s1", "s2"},
+		},
+		{
+			name:      "count and age combined",
+			snapshots: finished,
+			retention: SnapshotRetention{MaxAge: 7 * day, MaxCount: 2},
+			want:      []string{"s1", "s2", "s3"},
+		},
+		{
+			name:      "kept snapshots count but survive",
+			snapshots: finished,
+			retention: SnapshotRetention{MaxCount: 3, Keep: []string{"s1"}},
+			want:      []string{"s2"},
+		},
+		{
+			name:      "an old kept snapshot does not evict newer ones",
+			snapshots: finished,
+			retention: SnapshotRetention{MaxCount: 2, Keep: []string{"s1"}},
+			want:      []string{"s2", "s3"},
+		},
+		{
+			name:      "kept snapshots fill the count",
+			snapshots: finished,
+			retention: SnapshotRetention{MaxCount: 2, Keep: []string{"s1", "s4"}},
+			want:      []string{"s2", "s3"},
+		},
+		{
+			name:      "newest survives the age rule",
+			snapshots: finished,
+			retention: SnapshotRetention{MaxAge: time.Minute},
+			want:      []string{"s1", "s2", "s3", "s4"},
+		},
+		{
+			name:      "newest survives a count of one",
+			snapshots: finished,
+			retention: SnapshotRetention{MaxCount: 1},
+			want:      []string{"s1", "s2", "s3", "s4"},
+		},
+		{
+			name: "newest finished survives behind one in progress",
+			snapshots: []SnapshotInfo{
+				snapshot("s1", 3*day, SnapshotSuccess),
+				snapshot("s2", 2*day, SnapshotSuccess),
+				snapshot("s3", time.Minute, SnapshotInProgress),
+			},
+			retention: SnapshotRetention{MaxAge: time.Hour, MaxCount: 1},
+			want:      []string{"s1"},
+		},
+		{
+			name: "in progress is never pruned",
+			snapshots: []SnapshotInfo{
+				snapshot("s1", 10*day, SnapshotInProgress),
+				snapshot("s2", 9*day, SnapshotSuccess),
+			},
+			retention: SnapshotRetention{MaxAge: day, MaxCount: 1},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, selectSnapshotsToPrune(tc.snapshots, tc.retention, now))
+		})
+	}
+}
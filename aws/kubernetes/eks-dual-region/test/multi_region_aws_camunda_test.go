@@ -3,6 +3,7 @@ package test
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -45,6 +46,15 @@ var (
 	backupBucket    = helpers.GetEnv("BACKUP_BUCKET", fmt.Sprintf("%s-elastic-backup", clusterName))                        // allows supplying backup bucket name via GHA
 	awsProfile      = helpers.GetEnv("AWS_PROFILE", "infraex")
 
+	// Snapshots beyond the newest BACKUP_RETENTION_COUNT or older than BACKUP_RETENTION_MAX_AGE are pruned before a new backup
+	backupRetentionCount  = helpers.GetEnv("BACKUP_RETENTION_COUNT", "5")
+	backupRetentionMaxAge = helpers.GetEnv("BACKUP_RETENTION_MAX_AGE", "168h")
+	// The Zeebe part of the coordinated backup uses the S3 backup store of camunda-values.yml; the backup fails when it is missing
+	zeebeBackupEnabled = helpers.GetEnv("ZEEBE_BACKUP_ENABLED", "true")
+
+	// coordinatedBackup is taken during failback and restored into the recreated region
+	coordinatedBackup *kubectlHelpers.CoordinatedBackup
+
 	primary   helpers.Cluster
 	secondary helpers.Cluster
 
@@ -148,7 +158,8 @@ func TestAWSDualRegFailback_8_6_plus(t *testing.T) {
 		{"TestCheckC8RunningProperly", checkC8RunningProperly},
 		{"TestStopZeebeExporters", stopZeebeExporters},
 		{"TestCreateElasticBackupRepoPrimary", createElasticBackupRepoPrimary},
-		{"TestCreateElasticBackupPrimary", createElasticBackupPrimary},
+		{"TestPruneElasticBackupsPrimary", pruneElasticBackupsPrimary},
+		{"TestTakeCoordinatedBackup", takeCoordinatedBackup},
 		{"TestCheckThatElasticBackupIsPresentPrimary", checkThatElasticBackupIsPresentPrimary},
 		{"TestCreateElasticBackupRepoSecondary", createElasticBackupRepoSecondary},
 		{"TestCheckThatElasticBackupIsPresentSecondary", checkThatElasticBackupIsPresentSecondary},
@@ -279,9 +290,9 @@ func deployC8Helm(t *testing.T, valuesYamlFiles []string) {
 	}
 
 	// We have to install both at the same time as otherwise zeebe will not become ready
-	kubectlHelpers.InstallUpgradeC8Helm(t, &primary.KubectlNamespace, remoteChartVersion, remoteChartName, remoteChartSource, primaryNamespace, secondaryNamespace, append(valuesYamlFiles, region0ValuesYaml), 0, baseHelmVars, setStringValues)
+	kubectlHelpers.InstallUpgradeC8Helm(t, &primary.KubectlNamespace, remoteChartVersion, remoteChartName, remoteChartSource, primaryNamespace, secondaryNamespace, backupBucket, append(valuesYamlFiles, region0ValuesYaml), 0, baseHelmVars, setStringValues)
 
-	kubectlHelpers.InstallUpgradeC8Helm(t, &secondary.KubectlNamespace, remoteChartVersion, remoteChartName, remoteChartSource, primaryNamespace, secondaryNamespace, append(valuesYamlFiles, region1ValuesYaml), 1, baseHelmVars, setStringValues)
+	kubectlHelpers.InstallUpgradeC8Helm(t, &secondary.KubectlNamespace, remoteChartVersion, remoteChartName, remoteChartSource, primaryNamespace, secondaryNamespace, backupBucket, append(valuesYamlFiles, region1ValuesYaml), 1, baseHelmVars, setStringValues)
 
 	// Check that all deployments and Statefulsets are available
 	// Terratest has no direct function for Statefulsets, therefore defaulting to pods directly
@@ -401,16 +412,42 @@ func createElasticBackupRepoPrimary(t *testing.T) {
 	kubectlHelpers.ConfigureElasticBackup(t, primary, backupBucket, remoteChartVersion)
 }
 
-func createElasticBackupPrimary(t *testing.T) {
-	t.Log("[ELASTICSEARCH BACKUP] Creating Elasticsearch Backup 🚀")
+func pruneElasticBackupsPrimary(t *testing.T) {
+	t.Log("[ELASTICSEARCH BACKUP] Pruning old Elasticsearch Backups 🧹")
+
+	maxCount, err := strconv.Atoi(backupRetentionCount)
+	require.NoError(t, err, "invalid BACKUP_RETENTION_COUNT")
+	maxAge, err := time.ParseDuration(backupRetentionMaxAge)
+	require.NoError(t, err, "invalid BACKUP_RETENTION_MAX_AGE")
+
+	kubectlHelpers.PruneElasticBackups(t, primary, kubectlHelpers.SnapshotRetention{MaxAge: maxAge, MaxCount: maxCount})
+}
+
+// takeCoordinatedBackup backs up Zeebe and the Elasticsearch of the primary region,
+// the only one holding data during failback, under one backup ID. Exporting stays
+// paused until the secondary region is restored and its exporter re-enabled.
+func takeCoordinatedBackup(t *testing.T) {
+	t.Log("[COORDINATED BACKUP] Creating Camunda Backup 🚀")
 
-	kubectlHelpers.CreateElasticBackup(t, primary, backupName)
+	withZeebe, err := strconv.ParseBool(zeebeBackupEnabled)
+	require.NoError(t, err, "invalid ZEEBE_BACKUP_ENABLED")
+
+	coordinatedBackup = kubectlHelpers.TakeCoordinatedBackup(t, kubectlHelpers.CoordinatedBackupOptions{
+		// Zeebe wants backup IDs to increase; a timestamp does that across runs.
+		ID:                  time.Now().UnixMilli(),
+		Gateway:             primary,
+		Regions:             []helpers.Cluster{primary},
+		SnapshotPrefix:      backupName,
+		Zeebe:               withZeebe,
+		KeepExportingPaused: true,
+	})
 }
 
 func checkThatElasticBackupIsPresentPrimary(t *testing.T) {
 	t.Log("[ELASTICSEARCH BACKUP] Checking if Elasticsearch Backup is present 🚀")
 
-	kubectlHelpers.CheckThatElasticBackupIsPresent(t, primary, backupName, backupBucket, remoteChartVersion)
+	require.NotNil(t, coordinatedBackup, "no coordinated backup was taken")
+	kubectlHelpers.CheckThatElasticBackupIsPresent(t, primary, coordinatedBackup.Snapshots[primary.Region])
 }
 
 func createElasticBackupRepoSecondary(t *testing.T) {
@@ -422,13 +459,15 @@ func createElasticBackupRepoSecondary(t *testing.T) {
 func checkThatElasticBackupIsPresentSecondary(t *testing.T) {
 	t.Log("[ELASTICSEARCH BACKUP] Checking if Elasticsearch Backup is present 🚀")
 
-	kubectlHelpers.CheckThatElasticBackupIsPresent(t, secondary, backupName, backupBucket, remoteChartVersion)
+	require.NotNil(t, coordinatedBackup, "no coordinated backup was taken")
+	kubectlHelpers.CheckThatElasticBackupIsPresent(t, secondary, coordinatedBackup.Snapshots[primary.Region])
 }
 
 func restoreElasticBackupSecondary(t *testing.T) {
 	t.Log("[ELASTICSEARCH BACKUP] Restoring Elasticsearch Backup 🚀")
 
-	kubectlHelpers.RestoreElasticBackup(t, secondary, backupName)
+	require.NotNil(t, coordinatedBackup, "no coordinated backup was taken")
+	kubectlHelpers.RestoreCoordinatedBackup(t, coordinatedBackup, primary, secondary)
 }
 
 func checkElasticsearchClusterHealth(t *testing.T) {
@@ -540,7 +579,7 @@ func redeployWithoutOperateTasklist(t *testing.T, cluster helpers.Cluster, disab
 		valuesYamlFiles = append(valuesYamlFiles, region1ValuesYaml)
 	}
 
-	kubectlHelpers.InstallUpgradeC8Helm(t, &cluster.KubectlNamespace, remoteChartVersion, remoteChartName, remoteChartSource, primaryNamespace, secondaryNamespace, valuesYamlFiles, region, helpers.CombineMaps(baseHelmVars, setValues), setStringValues)
+	kubectlHelpers.InstallUpgradeC8Helm(t, &cluster.KubectlNamespace, remoteChartVersion, remoteChartName, remoteChartSource, primaryNamespace, secondaryNamespace, backupBucket, valuesYamlFiles, region, helpers.CombineMaps(baseHelmVars, setValues), setStringValues)
 
 	// Wait for ECK-managed Elasticsearch to be ready
 	k8s.RunKubectl(t, &cluster.KubectlNamespace, "wait", "--for=jsonpath={.status.phase}=Ready", "--timeout="+timeout, "elasticsearch/elasticsearch")
@@ -574,63 +613,25 @@ func redeployWithoutOperateTasklist(t *testing.T, cluster helpers.Cluster, disab
 func stopZeebeExporters(t *testing.T) {
 	t.Log("[ZEEBE EXPORTERS] Stopping Zeebe Exporters 🚀")
 
-	var output string
-	var err error
-	paused := false
-
 	// Partition distribution may take a while and results in a 500 error, and the
 	// gateway can be briefly unreachable while brokers restart, so retry on both
-	// connection errors and non-2xx responses instead of failing on the first one.
-	for i := 0; i < 10; i++ {
-		output, err = k8s.RunKubectlAndGetOutputE(t, &primary.KubectlNamespace, "exec", kubectlHelpers.ElasticsearchPodName, "--", "curl", "-i", "--max-time", "15", "camunda-zeebe-gateway:9600/actuator/exporting/pause", "-XPOST")
-		if err == nil && strings.Contains(output, "HTTP/1.1 20") {
-			t.Log("[ZEEBE EXPORTERS] Output contains a 20x response")
-			paused = true
-			break
-		}
-		if err != nil {
-			t.Logf("[ZEEBE EXPORTERS] Pause request failed (attempt %d/10), retrying: %v", i+1, err)
-		} else {
-			t.Log("[ZEEBE EXPORTERS] Pausing exporters failed, retrying...")
-		}
-		time.Sleep(30 * time.Second)
-	}
-
-	// `curl -i` exits 0 even on a 4xx/5xx response, so a nil err alone does not imply
-	// success; assert on the actual 20x outcome, surfacing the last err and response.
-	require.True(t, paused, "[ZEEBE EXPORTERS] failed to pause exporters within retries (last err: %v, last response: %s)", err, output)
-	t.Logf("[ZEEBE EXPORTERS] Paused exporters: %s", output)
+	// connection errors and 5xx responses instead of failing on the first one.
+	gateway := kubectlHelpers.NewGatewayManagement(&primary.KubectlNamespace)
+	gateway.MaxRetries, gateway.Backoff = 10, 30*time.Second
+	require.NoError(t, gateway.PauseExporting(t, false), "[ZEEBE EXPORTERS] failed to pause exporters")
+	t.Log("[ZEEBE EXPORTERS] Paused exporters")
 }
 
 func startZeebeExporters(t *testing.T) {
 	t.Log("[ZEEBE EXPORTERS] Starting Zeebe Exporters 🚀")
 
-	var output string
-	var err error
-	resumed := false
-
 	// Partition distribution may take a while and results in a 500 error, and the
 	// gateway can be briefly unreachable while brokers restart, so retry on both
-	// connection errors and non-2xx responses instead of failing on the first one.
-	for i := 0; i < 10; i++ {
-		output, err = k8s.RunKubectlAndGetOutputE(t, &primary.KubectlNamespace, "exec", kubectlHelpers.ElasticsearchPodName, "--", "curl", "-i", "--max-time", "15", "camunda-zeebe-gateway:9600/actuator/exporting/resume", "-XPOST")
-		if err == nil && strings.Contains(output, "HTTP/1.1 20") {
-			t.Log("[ZEEBE EXPORTERS] Output contains a 20x response")
-			resumed = true
-			break
-		}
-		if err != nil {
-			t.Logf("[ZEEBE EXPORTERS] Resume request failed (attempt %d/10), retrying: %v", i+1, err)
-		} else {
-			t.Log("[ZEEBE EXPORTERS] Resuming exporters failed, retrying...")
-		}
-		time.Sleep(30 * time.Second)
-	}
-
-	// `curl -i` exits 0 even on a 4xx/5xx response, so a nil err alone does not imply
-	// success; assert on the actual 20x outcome, surfacing the last err and response.
-	require.True(t, resumed, "[ZEEBE EXPORTERS] failed to resume exporters within retries (last err: %v, last response: %s)", err, output)
-	t.Logf("[ZEEBE EXPORTERS] Resumed exporters: %s", output)
+	// connection errors and 5xx responses instead of failing on the first one.
+	gateway := kubectlHelpers.NewGatewayManagement(&primary.KubectlNamespace)
+	gateway.MaxRetries, gateway.Backoff = 10, 30*time.Second
+	require.NoError(t, gateway.ResumeExporting(t), "[ZEEBE EXPORTERS] failed to resume exporters")
+	t.Log("[ZEEBE EXPORTERS] Resumed exporters")
 }
 
 func checkTheMath(t *testing.T) {
@@ -659,175 +660,110 @@ func checkTheMathFailover_8_6_plus(t *testing.T) {
 	require.True(t, helpers.IsEven(kubectlHelpers.GetZeebeBrokerId(t, &primary.KubectlNamespace, "camunda-zeebe-3")))
 }
 
+// selfHealBetweenPolls returns a callback for GatewayManagement.WaitForChange that
+// self-heals a broker hanging on the clusterset-DNS race (camunda/camunda#55038) so an
+// in-flight cluster change can finish, healing at most one region per poll.
+func selfHealBetweenPolls(t *testing.T) func() {
+	notReadySince := map[string]time.Time{}
+	brokerRestarts := 0
+	return func() {
+		if kubectlHelpers.SelfHealStuckBrokers(t, &secondary.KubectlNamespace, "camunda-zeebe", notReadySince, &brokerRestarts, 90*time.Second, 6) == 0 {
+			kubectlHelpers.SelfHealStuckBrokers(t, &primary.KubectlNamespace, "camunda-zeebe", notReadySince, &brokerRestarts, 90*time.Second, 6)
+		}
+	}
+}
+
 func removeSecondaryBrokers(t *testing.T) {
 	t.Log("[FAILOVER] Removing secondary brokers 🚀")
 
 	// Redistribute to remaining brokers. Each request uses its own short-lived
-	// port-forward (see GatewayManagementRequest) so a broker restarting during
+	// port-forward (see GatewayManagement) so a broker restarting during
 	// redistribution cannot break a long-lived tunnel.
-	status, body, err := kubectlHelpers.GatewayManagementMutate(t, &primary.KubectlNamespace, "PATCH", "/actuator/cluster?force=true", []byte(`{"brokers":{"remove":[1,3,5,7]}}`), 8, 15*time.Second)
+	gateway := kubectlHelpers.NewGatewayManagement(&primary.KubectlNamespace)
+	plan, err := gateway.PatchCluster(t, kubectlHelpers.ClusterPatch{Brokers: &kubectlHelpers.BrokersPatch{Remove: []int{1, 3, 5, 7}}}, kubectlHelpers.ClusterPatchOptions{Force: true})
 	require.NoError(t, err, "[FAILOVER] failed to request broker removal")
-	require.Equal(t, 202, status)
-	require.NotEmpty(t, body)
-	require.Contains(t, body, "plannedChanges")
-	require.Contains(t, body, "PARTITION_FORCE_RECONFIGURE")
+	require.NotEmpty(t, plan.Operations("PARTITION_FORCE_RECONFIGURE"), "[FAILOVER] expected forced partition reconfiguration in %+v", plan.PlannedChanges)
+	t.Logf("[FAILOVER] Broker removal planned as change %d with %d operations", plan.ChangeId, len(plan.PlannedChanges))
+
+	// Wait for the removal of obsolete brokers, tolerating transient connection
+	// drops while brokers restart and self-healing any broker that hangs on the
+	// clusterset-DNS race (camunda/camunda#55038) so the change can finish.
+	topology, err := gateway.WaitForChange(t, plan.ChangeId, 20, 15*time.Second, selfHealBetweenPolls(t))
+	require.NoError(t, err, "[FAILOVER] broker removal did not complete within the retry budget")
+	require.Nil(t, topology.PendingChange)
+	for _, b := range topology.Brokers {
+		require.True(t, helpers.IsEven(b.Id), "[FAILOVER] secondary broker %d is still part of the cluster", b.Id)
+	}
+}
 
-	t.Log("[FAILOVER] Give the system some time to redistribute the partitions")
-	time.Sleep(5 * time.Second)
+// waitForExporter waits for the exporter change to complete and checks the status
+// of both exporters afterwards.
+func waitForExporter(t *testing.T, gateway *kubectlHelpers.GatewayManagement, changeID int64, maxRetries int, region1Status string) {
+	t.Helper()
 
-	// Check that the removal of obsolete brokers was completed, tolerating transient
-	// connection drops while brokers restart and self-healing any broker that hangs
-	// on the clusterset-DNS race (camunda/camunda#55038) so the change can finish.
-	var lastBody string
-	completed := false
-	notReadySince := map[string]time.Time{}
-	brokerRestarts := 0
-	for i := 0; i < 20; i++ {
-		status, lastBody, err = kubectlHelpers.GatewayManagementRequest(t, &primary.KubectlNamespace, "GET", "/actuator/cluster", nil)
-		if err == nil && status == 200 && !strings.Contains(lastBody, "pendingChange") {
-			completed = true
-			break
-		}
-		if err != nil {
-			t.Logf("[FAILOVER] cluster status request failed (attempt %d/20), retrying: %v", i+1, err)
-		} else {
-			t.Log("[FAILOVER] Broker removal not yet completed, retrying...")
-		}
-		if kubectlHelpers.SelfHealStuckBrokers(t, &secondary.KubectlNamespace, "camunda-zeebe", notReadySince, &brokerRestarts, 90*time.Second, 6) == 0 {
-			kubectlHelpers.SelfHealStuckBrokers(t, &primary.KubectlNamespace, "camunda-zeebe", notReadySince, &brokerRestarts, 90*time.Second, 6)
-		}
-		time.Sleep(15 * time.Second)
-	}
+	// Tolerate transient connection drops and self-heal any broker that hangs on the
+	// clusterset-DNS race (camunda/camunda#55038) so the exporter change can finish.
+	_, err := gateway.WaitForChange(t, changeID, maxRetries, 15*time.Second, selfHealBetweenPolls(t))
+	require.NoError(t, err, "exporter change %d did not complete within the retry budget", changeID)
 
-	require.True(t, completed, "[FAILOVER] broker removal did not complete within the retry budget")
-	require.Contains(t, lastBody, "COMPLETED")
-	require.NotContains(t, lastBody, "pendingChange")
-	require.NotContains(t, lastBody, "PARTITION_FORCE_RECONFIGURE")
+	exporters, err := gateway.Exporters(t)
+	require.NoError(t, err)
+	require.Contains(t, exporters, kubectlHelpers.ExporterStatus{ExporterId: "camundaregion0", Status: "ENABLED"})
+	require.Contains(t, exporters, kubectlHelpers.ExporterStatus{ExporterId: "camundaregion1", Status: region1Status})
 }
 
 func disableElasticExportersToSecondary(t *testing.T) {
 	t.Log("[FAILOVER] Disabling Elasticsearch Exporters to secondary 🚀")
 
-	status, body, err := kubectlHelpers.GatewayManagementMutate(t, &primary.KubectlNamespace, "POST", "/actuator/exporters/camundaregion1/disable", nil, 8, 15*time.Second)
+	gateway := kubectlHelpers.NewGatewayManagement(&primary.KubectlNamespace)
+	plan, err := gateway.DisableExporter(t, "camundaregion1")
 	require.NoError(t, err, "[FAILOVER] failed to request exporter disable")
-	require.Equal(t, 202, status)
-	require.NotEmpty(t, body)
-	require.Contains(t, body, "DISABLED")
-	require.Contains(t, body, "PARTITION_DISABLE_EXPORTER")
-
-	// Check that the exporter was disabled, tolerating transient connection drops and
-	// self-healing any broker that hangs on the clusterset-DNS race
-	// (camunda/camunda#55038) so the exporter change can finish.
-	var lastBody string
-	disabled := false
-	notReadySince := map[string]time.Time{}
-	brokerRestarts := 0
-	for i := 0; i < 20; i++ {
-		status, lastBody, err = kubectlHelpers.GatewayManagementRequest(t, &primary.KubectlNamespace, "GET", "/actuator/exporters", nil)
-		if err == nil && status == 200 && strings.Contains(lastBody, "{\"exporterId\":\"camundaregion1\",\"status\":\"DISABLED\"}") {
-			disabled = true
-			break
-		}
-		if err != nil {
-			t.Logf("[FAILOVER] exporters status request failed (attempt %d/20), retrying: %v", i+1, err)
-		} else {
-			t.Log("[FAILOVER] Exporter not yet disabled, retrying...")
-		}
-		if kubectlHelpers.SelfHealStuckBrokers(t, &secondary.KubectlNamespace, "camunda-zeebe", notReadySince, &brokerRestarts, 90*time.Second, 6) == 0 {
-			kubectlHelpers.SelfHealStuckBrokers(t, &primary.KubectlNamespace, "camunda-zeebe", notReadySince, &brokerRestarts, 90*time.Second, 6)
-		}
-		time.Sleep(15 * time.Second)
-	}
+	require.NotEmpty(t, plan.Operations("PARTITION_DISABLE_EXPORTER"), "[FAILOVER] expected exporter disable operations in %+v", plan.PlannedChanges)
+	t.Logf("[FAILOVER] Exporter disable planned as change %d", plan.ChangeId)
 
-	require.True(t, disabled, "[FAILOVER] exporter was not disabled within the retry budget")
-	require.Contains(t, lastBody, "{\"exporterId\":\"camundaregion0\",\"status\":\"ENABLED\"}")
-	require.Contains(t, lastBody, "{\"exporterId\":\"camundaregion1\",\"status\":\"DISABLED\"}")
+	waitForExporter(t, gateway, plan.ChangeId, 20, "DISABLED")
 }
 
 func enableElasticExportersToSecondary(t *testing.T) {
 	t.Log("[FAILBACK] Enabling Elasticsearch Exporters to secondary 🚀")
 
-	status, body, err := kubectlHelpers.GatewayManagementMutate(t, &primary.KubectlNamespace, "POST", "/actuator/exporters/camundaregion1/enable", []byte(`{"initializeFrom":"camundaregion0"}`), 8, 15*time.Second)
+	gateway := kubectlHelpers.NewGatewayManagement(&primary.KubectlNamespace)
+	plan, err := gateway.EnableExporter(t, "camundaregion1", "camundaregion0")
 	require.NoError(t, err, "[FAILBACK] failed to request exporter enable")
-	require.Equal(t, 202, status)
-	require.NotEmpty(t, body)
-	require.Contains(t, body, "ENABLED")
-	require.Contains(t, body, "PARTITION_ENABLE_EXPORTER")
-
-	// Check that the exporter was enabled. It can take a while, and brokers may
-	// restart, so tolerate transient connection drops and self-heal any broker that
-	// hangs on the clusterset-DNS race (camunda/camunda#55038).
-	var lastBody string
-	enabled := false
-	notReadySince := map[string]time.Time{}
-	brokerRestarts := 0
-	for i := 0; i < 60; i++ {
-		status, lastBody, err = kubectlHelpers.GatewayManagementRequest(t, &primary.KubectlNamespace, "GET", "/actuator/exporters", nil)
-		if err == nil && status == 200 && strings.Contains(lastBody, "{\"exporterId\":\"camundaregion1\",\"status\":\"ENABLED\"}") {
-			enabled = true
-			break
-		}
-		if err != nil {
-			t.Logf("[FAILBACK] exporters status request failed (attempt %d/60), retrying: %v", i+1, err)
-		} else {
-			t.Log("[FAILBACK] Exporter not yet enabled, retrying...")
-		}
-		if kubectlHelpers.SelfHealStuckBrokers(t, &secondary.KubectlNamespace, "camunda-zeebe", notReadySince, &brokerRestarts, 90*time.Second, 6) == 0 {
-			kubectlHelpers.SelfHealStuckBrokers(t, &primary.KubectlNamespace, "camunda-zeebe", notReadySince, &brokerRestarts, 90*time.Second, 6)
-		}
-		time.Sleep(15 * time.Second)
-	}
+	require.NotEmpty(t, plan.Operations("PARTITION_ENABLE_EXPORTER"), "[FAILBACK] expected exporter enable operations in %+v", plan.PlannedChanges)
+	t.Logf("[FAILBACK] Exporter enable planned as change %d", plan.ChangeId)
 
-	require.True(t, enabled, "[FAILBACK] exporter was not enabled within the retry budget")
-	require.Contains(t, lastBody, "{\"exporterId\":\"camundaregion0\",\"status\":\"ENABLED\"}")
-	require.Contains(t, lastBody, "{\"exporterId\":\"camundaregion1\",\"status\":\"ENABLED\"}")
+	// Enabling can take a while, and brokers may restart.
+	waitForExporter(t, gateway, plan.ChangeId, 60, "ENABLED")
 }
 
 func addSecondaryBrokers(t *testing.T) {
 	t.Log("[FAILBACK] Adding secondary brokers 🚀")
 
-	// Request the scaling change and poll for completion. Each request uses its
-	// own short-lived port-forward (see GatewayManagementRequest), because a broker
-	// pod restarting during partition redistribution tears down a long-lived tunnel
+	// Request the scaling change and wait for it. Each request uses its own
+	// short-lived port-forward (see GatewayManagement), because a broker pod
+	// restarting during partition redistribution tears down a long-lived tunnel
 	// and previously failed this step with an EOF on the status poll.
-	status, body, err := kubectlHelpers.GatewayManagementMutate(t, &primary.KubectlNamespace, "PATCH", "/actuator/cluster", []byte(`{"brokers":{"add":[1,3,5,7]},"partitions":{"replicationFactor":4}}`), 8, 15*time.Second)
+	gateway := kubectlHelpers.NewGatewayManagement(&primary.KubectlNamespace)
+	patch := kubectlHelpers.ClusterPatch{
+		Brokers:    &kubectlHelpers.BrokersPatch{Add: []int{1, 3, 5, 7}},
+		Partitions: &kubectlHelpers.PartitionsPatch{ReplicationFactor: 4},
+	}
+	plan, err := gateway.PatchCluster(t, patch, kubectlHelpers.ClusterPatchOptions{})
 	require.NoError(t, err, "[FAILBACK] failed to request broker addition")
-	require.Equal(t, 202, status)
-	require.NotEmpty(t, body)
-	require.Contains(t, body, "\"id\":8,\"state\":\"ACTIVE\"")
-
-	// Check that the addition of new brokers was completed. This can take a while,
-	// and brokers restart during redistribution, so tolerate transient connection
-	// drops and retry instead of failing on the first dropped request. A broker can
-	// also hang on the cross-region clusterset-DNS race while restarting
+	require.Len(t, plan.ExpectedTopology, 8, "[FAILBACK] expected 8 brokers after the change")
+	t.Logf("[FAILBACK] Broker addition planned as change %d with %d operations", plan.ChangeId, len(plan.PlannedChanges))
+
+	// Wait for the addition of the new brokers. This can take a while, and brokers
+	// restart during redistribution, so tolerate transient connection drops. A
+	// broker can also hang on the cross-region clusterset-DNS race while restarting
 	// (camunda/camunda#55038), which stalls the partition redistribution forever; so
 	// self-heal any broker left Running-but-not-Ready on either region so the
 	// StatefulSet recreates it through the DNS-gate init container and the scaling
 	// can finish.
-	var lastBody string
-	completed := false
-	notReadySince := map[string]time.Time{}
-	brokerRestarts := 0
-	for i := 0; i < 60; i++ {
-		status, lastBody, err = kubectlHelpers.GatewayManagementRequest(t, &primary.KubectlNamespace, "GET", "/actuator/cluster", nil)
-		if err == nil && status == 200 && !strings.Contains(lastBody, "pendingChange") {
-			completed = true
-			break
-		}
-		if err != nil {
-			t.Logf("[FAILBACK] cluster status request failed (attempt %d/60), retrying: %v", i+1, err)
-		} else {
-			t.Log("[FAILBACK] Broker addition not yet completed, retrying...")
-		}
-		if kubectlHelpers.SelfHealStuckBrokers(t, &secondary.KubectlNamespace, "camunda-zeebe", notReadySince, &brokerRestarts, 90*time.Second, 6) == 0 {
-			kubectlHelpers.SelfHealStuckBrokers(t, &primary.KubectlNamespace, "camunda-zeebe", notReadySince, &brokerRestarts, 90*time.Second, 6)
-		}
-		time.Sleep(15 * time.Second)
-	}
-
-	require.True(t, completed, "[FAILBACK] broker addition did not complete within the retry budget")
-	require.Contains(t, lastBody, "COMPLETED")
-	require.NotContains(t, lastBody, "pendingChange")
+	topology, err := gateway.WaitForChange(t, plan.ChangeId, 60, 15*time.Second, selfHealBetweenPolls(t))
+	require.NoError(t, err, "[FAILBACK] broker addition did not complete within the retry budget")
+	require.Nil(t, topology.PendingChange)
 
 	// Check that the new brokers have become ready, now that they're integrated in the zeebe cluster again
 	k8s.RunKubectl(t, &secondary.KubectlNamespace, "rollout", "status", "--watch", "--timeout=300s", "statefulset/camunda-zeebe")
@@ -1,7 +1,6 @@
 package test
 
 import (
-	"encoding/json"
 	"fmt"
 	"strings"
 	"testing"
@@ -129,7 +128,7 @@ func verifyClusterTopology(t *testing.T, clusterSizeExpected, partitionCountExpe
 // This approach is used when kubectl scale permissions are not available
 func scaleUpBrokerStatefulSets(t *testing.T, replicasPerRegion int) {
 	t.Helper()
-	totalClusterSize := replicasPerRegion * 2 // Total brokers across both regions
+	totalClusterSize := replicasPerRegion * kubectlHelpers.RegionCount
 	t.Logf("[SCALING] Scaling up Zeebe StatefulSets to %d replicas per region (%d total) via kubectl 🚀", replicasPerRegion, totalClusterSize)
 
 	replicasArg := fmt.Sprintf("--replicas=%d", replicasPerRegion)
@@ -180,48 +179,26 @@ func waitForPodRunning(t *testing.T, kubectlOptions *k8s.KubectlOptions, podName
 	}
 }
 
-// waitForScalingComplete polls the cluster status until scaling is complete
+// scalingChangeID is the change ID of the last scaling change requested by
+// patchClusterTopology, which waitForScalingComplete waits on.
+var scalingChangeID int64
+
+// waitForScalingComplete polls the cluster status until the last scaling change is complete
 // operationName is used for logging, maxRetries controls the timeout (each retry waits 15 seconds)
 func waitForScalingComplete(t *testing.T, operationName string, maxRetries int) {
 	t.Helper()
-	t.Logf("[SCALING] Waiting for %s to complete 🕐", operationName)
+	t.Logf("[SCALING] Waiting for %s (change %d) to complete 🕐", operationName, scalingChangeID)
+	require.NotZero(t, scalingChangeID, "[SCALING] no scaling change was requested")
 
-	// Poll the gateway cluster topology until the scaling change is no longer pending,
+	// Poll the gateway cluster topology until the scaling change is completed,
 	// tolerating transient connection drops and self-healing any broker that hangs on
 	// the cross-region clusterset-DNS race during a restart (camunda/camunda#55038),
 	// which would otherwise stall the redistribution forever. Each request uses its own
-	// short-lived kubectl port-forward (see GatewayManagementRequest).
-	notReadySince := map[string]time.Time{}
-	brokerRestarts := 0
-	completed := false
-	var lastBody string
-	for i := 0; i < maxRetries; i++ {
-		status, body, err := kubectlHelpers.GatewayManagementRequest(t, &primary.KubectlNamespace, "GET", "/actuator/cluster", nil)
-		if err == nil && status == 200 {
-			lastBody = body
-			// Check if there's no pending change
-			if !strings.Contains(body, "pendingChange") {
-				t.Logf("[SCALING] %s completed successfully", operationName)
-				require.Contains(t, body, "COMPLETED", "Expected lastChange status to be COMPLETED")
-				completed = true
-				break
-			}
-			t.Logf("[SCALING] %s in progress... (attempt %d/%d)", operationName, i+1, maxRetries)
-		} else if err != nil {
-			t.Logf("[SCALING] %s status request failed (attempt %d/%d), retrying: %v", operationName, i+1, maxRetries, err)
-		} else {
-			t.Logf("[SCALING] %s unexpected status %d (attempt %d/%d), retrying", operationName, status, i+1, maxRetries)
-		}
-
-		// Self-heal a broker left Running-but-not-Ready on the clusterset-DNS race so
-		// the scaling change can progress; heal at most one region per iteration.
-		if kubectlHelpers.SelfHealStuckBrokers(t, &secondary.KubectlNamespace, "camunda-zeebe", notReadySince, &brokerRestarts, 90*time.Second, 6) == 0 {
-			kubectlHelpers.SelfHealStuckBrokers(t, &primary.KubectlNamespace, "camunda-zeebe", notReadySince, &brokerRestarts, 90*time.Second, 6)
-		}
-		time.Sleep(15 * time.Second)
-	}
-
-	require.True(t, completed, "[SCALING] %s did not complete within the expected time (last status body: %s)", operationName, lastBody)
+	// short-lived kubectl port-forward (see GatewayManagement).
+	gateway := kubectlHelpers.NewGatewayManagement(&primary.KubectlNamespace)
+	topology, err := gateway.WaitForChange(t, scalingChangeID, maxRetries, 15*time.Second, selfHealBetweenPolls(t))
+	require.NoError(t, err, "[SCALING] %s did not complete within the expected time", operationName)
+	t.Logf("[SCALING] %s completed successfully (topology version %d)", operationName, topology.Version)
 }
 
 // addNewBrokersToCluster sends API request to add new brokers to the cluster
@@ -229,12 +206,10 @@ func addNewBrokersToCluster(t *testing.T, brokersToAdd []int) {
 	t.Helper()
 	t.Logf("[SCALING] Adding new brokers %v to the cluster via API 🚀", brokersToAdd)
 
-	payload := map[string]interface{}{
-		"brokers": map[string]interface{}{
-			"add": brokersToAdd,
-		},
+	patch := kubectlHelpers.ClusterPatch{
+		Brokers: &kubectlHelpers.BrokersPatch{Add: brokersToAdd},
 	}
-	patchClusterTopology(t, payload, "broker addition")
+	patchClusterTopology(t, patch, "broker addition")
 }
 
 // scaleUpPartitions sends API request to increase partition count
@@ -242,13 +217,10 @@ func scaleUpPartitions(t *testing.T, partitionCount, replicationFactor int) {
 	t.Helper()
 	t.Logf("[SCALING] Scaling up to %d partitions with replication factor %d 🚀", partitionCount, replicationFactor)
 
-	payload := map[string]interface{}{
-		"partitions": map[string]interface{}{
-			"count":             partitionCount,
-			"replicationFactor": replicationFactor,
-		},
+	patch := kubectlHelpers.ClusterPatch{
+		Partitions: &kubectlHelpers.PartitionsPatch{Count: partitionCount, ReplicationFactor: replicationFactor},
 	}
-	patchClusterTopology(t, payload, "partition scaling")
+	patchClusterTopology(t, patch, "partition scaling")
 }
 
 // scaleUpBrokersAndPartitions sends API request to scale both brokers and partitions
@@ -256,41 +228,55 @@ func scaleUpBrokersAndPartitions(t *testing.T, brokersToAdd []int, partitionCoun
 	t.Helper()
 	t.Logf("[SCALING] Scaling up brokers %v and partitions to %d simultaneously 🚀", brokersToAdd, partitionCount)
 
-	payload := map[string]interface{}{
-		"brokers": map[string]interface{}{
-			"add": brokersToAdd,
-		},
-		"partitions": map[string]interface{}{
-			"count":             partitionCount,
-			"replicationFactor": replicationFactor,
-		},
+	patch := kubectlHelpers.ClusterPatch{
+		Brokers:    &kubectlHelpers.BrokersPatch{Add: brokersToAdd},
+		Partitions: &kubectlHelpers.PartitionsPatch{Count: partitionCount, ReplicationFactor: replicationFactor},
 	}
-	patchClusterTopology(t, payload, "combined broker and partition scaling")
+	patchClusterTopology(t, patch, "combined broker and partition scaling")
 }
 
 // patchClusterTopology sends a PATCH request to the Zeebe gateway cluster actuator endpoint
-// It performs a dry run first, then executes the actual scaling operation
-func patchClusterTopology(t *testing.T, payload map[string]interface{}, operationName string) {
+// It previews the change with a dry run first, then executes the actual scaling operation
+// and records the change ID for waitForScalingComplete
+func patchClusterTopology(t *testing.T, patch kubectlHelpers.ClusterPatch, operationName string) {
 	t.Helper()
 
-	payloadBytes, err := json.Marshal(payload)
-	require.NoError(t, err, "Failed to marshal payload")
+	gateway := kubectlHelpers.NewGatewayManagement(&primary.KubectlNamespace)
+	previewClusterChange(t, gateway, patch, operationName)
 
 	// Issue the scaling change through the gateway, retrying on transient 5xx (the
 	// gateway can briefly reject a mutating request while partitions redistribute).
-	// The request uses a short-lived kubectl port-forward (see GatewayManagementMutate).
+	// The request uses a short-lived kubectl port-forward (see GatewayManagement).
 	t.Logf("[SCALING] Executing %s", operationName)
-	status, body, err := kubectlHelpers.GatewayManagementMutate(t, &primary.KubectlNamespace, "PATCH", "/actuator/cluster", payloadBytes, 8, 15*time.Second)
+	plan, err := gateway.PatchCluster(t, patch, kubectlHelpers.ClusterPatchOptions{})
 	require.NoError(t, err, "Failed to request %s", operationName)
-	require.Equal(t, 202, status, "Expected 202 Accepted status")
-	require.NotEmpty(t, body)
-	require.Contains(t, body, "plannedChanges")
-	require.Contains(t, body, "changeId")
-
-	// Parse and log the change ID
-	var response map[string]interface{}
-	err = json.Unmarshal([]byte(body), &response)
-	require.NoError(t, err)
-	changeId := response["changeId"]
-	t.Logf("[SCALING] %s initiated with changeId: %v", operationName, changeId)
+	require.NotEmpty(t, plan.PlannedChanges, "Expected planned changes for %s", operationName)
+
+	scalingChangeID = plan.ChangeId
+	t.Logf("[SCALING] %s initiated with changeId: %d (%s)", operationName, plan.ChangeId, plan.Summary())
+}
+
+// previewClusterChange requests the change as a dry run and checks the plan before
+// anything is applied: it must do something, reach the requested partition count,
+// and keep the replicas of every partition balanced across both regions. A bad
+// broker list then fails in seconds instead of after waiting for the scaling.
+func previewClusterChange(t *testing.T, gateway *kubectlHelpers.GatewayManagement, patch kubectlHelpers.ClusterPatch, operationName string) {
+	t.Helper()
+
+	t.Logf("[SCALING] Previewing %s (dry run)", operationName)
+	plan, err := gateway.PatchCluster(t, patch, kubectlHelpers.ClusterPatchOptions{DryRun: true})
+	require.NoError(t, err, "Failed to preview %s", operationName)
+	summary := plan.Summary()
+	t.Logf("[SCALING] %s plan: %s", operationName, summary)
+	require.NotEmpty(t, plan.PlannedChanges, "Expected planned changes for %s", operationName)
+
+	if patch.Brokers != nil {
+		require.ElementsMatch(t, patch.Brokers.Add, summary.BrokerAdds, "Planned broker additions for %s", operationName)
+	}
+	replicas := kubectlHelpers.PartitionReplicas(plan.ExpectedTopology)
+	if patch.Partitions != nil && patch.Partitions.Count > 0 {
+		require.Len(t, replicas, patch.Partitions.Count, "Expected partition count after %s", operationName)
+	}
+	t.Logf("[SCALING] %s planned brokers per partition: %v", operationName, replicas)
+	require.NoError(t, kubectlHelpers.CheckRegionBalance(plan.ExpectedTopology, kubectlHelpers.RegionCount), "Plan for %s is not region-balanced", operationName)
 }
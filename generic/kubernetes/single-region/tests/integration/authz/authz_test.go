@@ -0,0 +1,41 @@
+// Package authz checks what credentials allow, not only that they can be
+// obtained: it creates users, groups, roles and authorizations through the
+// orchestration cluster v2 admin APIs and verifies, with one client per
+// user, that permitted calls succeed and forbidden ones return 403.
+//
+// The suite's own credentials must be an admin. The test users log in with
+// basic auth, so the package only runs with TEST_AUTH_MODE=basic, and the
+// cluster must have authorizations enabled.
+package authz
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/camunda"
+	"github.com/camunda/camunda-deployment-references/tests/integration/config"
+	"github.com/camunda/camunda-deployment-references/tests/integration/helpers"
+)
+
+var (
+	cfg    *config.Config
+	client *helpers.Client
+	api    *camunda.Client
+)
+
+func TestMain(m *testing.M) { helpers.RunSuite(m, helpers.Suite{Name: "authz", Setup: setupSuite}) }
+
+func setupSuite(env *helpers.SuiteEnv) (func(), error) {
+	cfg, client = env.Config, env.Client
+	api = camunda.New(client, cfg.ZeebeGatewayURL)
+	return nil, nil
+}
+
+// randomSuffix returns a short random hex string that keeps IDs of
+// concurrent or aborted runs apart.
+func randomSuffix() string {
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,160 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/camunda"
+	"github.com/camunda/camunda-deployment-references/tests/integration/camunda/fixtures"
+	"github.com/camunda/camunda-deployment-references/tests/integration/helpers"
+)
+
+// principal is a user created for one test, with a client that
+// authenticates as that user.
+type principal struct {
+	username string
+	api      *camunda.Client
+}
+
+// createUser creates a user named after role and the run suffix, deletes it
+// when t ends and waits until it can authenticate.
+func createUser(t *testing.T, role, suffix string) principal {
+	t.Helper()
+	username := fmt.Sprintf("it-%s-%s", role, suffix)
+	password := randomSuffix() + randomSuffix()
+	_, err := api.CreateUser(t.Context(), camunda.User{Username: username, Name: username, Email: username + "@example.com", Password: password})
+	require.NoError(t, err, "creating user %s", username)
+	t.Cleanup(func() { helpers.LogCleanup(t, "user "+username, api.DeleteUser(context.Background(), username)) })
+
+	userCfg := *cfg
+	userCfg.BasicUser, userCfg.BasicPass = username, password
+	p := principal{username: username, api: camunda.New(helpers.NewClient(&userCfg), cfg.ZeebeGatewayURL)}
+
+	// Topology needs authentication but no authorization, so it tells an
+	// unknown user (401) from a known one.
+	err = helpers.PropagationRetry().Do(t.Context(), t, func() error {
+		_, err := p.api.Topology(t.Context())
+		return err
+	})
+	require.NoError(t, err, "user %s should be able to authenticate", username)
+	return p
+}
+
+// assertForbidden checks err is a 403, the answer for an authenticated
+// caller lacking a permission (as opposed to 401 for bad credentials).
+func assertForbidden(t *testing.T, err error, msgAndArgs ...interface{}) {
+	t.Helper()
+	if !assert.Error(t, err, msgAndArgs...) {
+		t.Log("the call succeeded; are authorizations enabled on the cluster?")
+		return
+	}
+	assert.Equal(t, http.StatusForbidden, helpers.StatusCode(err), "expected 403, got %v", err)
+}
+
+// TestRoleBasedAccess grants permissions three ways and checks each is
+// enforced:
+//
+//   - deployer: a role with CREATE on all RESOURCEs, assigned to the user;
+//   - reader: a group with READ_PROCESS_DEFINITION and
+//     READ_PROCESS_INSTANCE on the test process, the user a member;
+//   - nobody: a user without any authorization.
+//
+// Only the deployer may deploy, and only the reader may read the process
+// instance the admin starts.
+func TestRoleBasedAccess(t *testing.T) {
+	helpers.Track(t)
+	if cfg.AuthMode != "basic" {
+		helpers.Skipf(t, "authorization tests log in as created users with basic auth; auth mode is %s", cfg.AuthMode)
+	}
+	suffix := randomSuffix()
+	processID := "it-authz-" + suffix
+
+	deployer := createUser(t, "deployer", suffix)
+	reader := createUser(t, "reader", suffix)
+	nobody := createUser(t, "nobody", suffix)
+
+	roleID := "it-deployers-" + suffix
+	_, err := api.CreateRole(t.Context(), camunda.Role{RoleID: roleID, Name: roleID})
+	require.NoError(t, err, "creating role")
+	t.Cleanup(func() { helpers.LogCleanup(t, "role "+roleID, api.DeleteRole(context.Background(), roleID)) })
+	grant(t, camunda.Authorization{
+		OwnerID:         roleID,
+		OwnerType:       camunda.OwnerRole,
+		ResourceID:      "*",
+		ResourceType:    camunda.ResourceTypeResource,
+		PermissionTypes: []string{camunda.PermissionCreate},
+	})
+	require.NoError(t, api.AssignRoleToUser(t.Context(), roleID, deployer.username), "assigning role")
+
+	groupID := "it-readers-" + suffix
+	_, err = api.CreateGroup(t.Context(), camunda.Group{GroupID: groupID, Name: groupID})
+	require.NoError(t, err, "creating group")
+	t.Cleanup(func() { helpers.LogCleanup(t, "group "+groupID, api.DeleteGroup(context.Background(), groupID)) })
+	grant(t, camunda.Authorization{
+		OwnerID:         groupID,
+		OwnerType:       camunda.OwnerGroup,
+		ResourceID:      processID,
+		ResourceType:    camunda.ResourceTypeProcessDefinition,
+		PermissionTypes: []string{camunda.PermissionReadProcessDefinition, camunda.PermissionReadProcessInstance},
+	})
+	require.NoError(t, api.AssignUserToGroup(t.Context(), groupID, reader.username), "assigning group")
+
+	resource := camunda.Resource{Name: "authz.bpmn", Content: []byte(fixtures.BasicProcessBPMN(processID))}
+
+	t.Run("DeployPermitted", func(t *testing.T) {
+		err := helpers.PropagationRetry().Do(t.Context(), t, func() error {
+			_, err := deployer.api.Deploy(t.Context(), "", resource)
+			return err
+		})
+		require.NoError(t, err, "%s holds CREATE on RESOURCE through role %s", deployer.username, roleID)
+	})
+
+	for _, p := range []principal{reader, nobody} {
+		t.Run("DeployForbidden/"+p.username, func(t *testing.T) {
+			_, err := p.api.Deploy(t.Context(), "", resource)
+			assertForbidden(t, err, "%s has no CREATE on RESOURCE", p.username)
+		})
+	}
+
+	created, err := api.CreateProcessInstance(t.Context(), camunda.CreateProcessInstanceRequest{ProcessDefinitionID: processID})
+	require.NoError(t, err, "admin should start %s", processID)
+	instanceKey := created.ProcessInstanceKey
+	err = helpers.PropagationRetry().Do(t.Context(), t, func() error {
+		_, err := api.GetProcessInstance(t.Context(), instanceKey)
+		return err
+	})
+	require.NoError(t, err, "instance %s should be exported to secondary storage", instanceKey)
+
+	t.Run("ReadInstancePermitted", func(t *testing.T) {
+		var pi *camunda.ProcessInstance
+		err := helpers.PropagationRetry().Do(t.Context(), t, func() error {
+			var err error
+			pi, err = reader.api.GetProcessInstance(t.Context(), instanceKey)
+			return err
+		})
+		require.NoError(t, err, "%s holds READ_PROCESS_INSTANCE through group %s", reader.username, groupID)
+		assert.Equal(t, processID, pi.ProcessDefinitionID)
+	})
+
+	for _, p := range []principal{deployer, nobody} {
+		t.Run("ReadInstanceForbidden/"+p.username, func(t *testing.T) {
+			_, err := p.api.GetProcessInstance(t.Context(), instanceKey)
+			assertForbidden(t, err, "%s has no READ_PROCESS_INSTANCE on %s", p.username, processID)
+		})
+	}
+}
+
+// grant creates a and deletes it when t ends.
+func grant(t *testing.T, a camunda.Authorization) {
+	t.Helper()
+	key, err := api.CreateAuthorization(t.Context(), a)
+	require.NoError(t, err, "creating authorization for %s %s", a.OwnerType, a.OwnerID)
+	t.Cleanup(func() {
+		helpers.LogCleanup(t, "authorization "+key, api.DeleteAuthorization(context.Background(), key))
+	})
+}
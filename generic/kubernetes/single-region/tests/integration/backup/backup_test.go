@@ -0,0 +1,43 @@
+// Package backup exercises the documented backup procedure against a
+// single-region deployment and proves the secondary-storage snapshot it
+// produces can be restored:
+//
+//  1. soft-pause exporting (/actuator/exporting/pause?soft=true);
+//  2. snapshot the Camunda indices into TEST_BACKUP_REPOSITORY;
+//  3. take a Zeebe backup with the same ID (/actuator/backups) and wait for
+//     it to complete;
+//  4. resume exporting;
+//  5. hard-pause exporting, delete the Camunda indices, restore them from
+//     the snapshot, resume exporting and check that the process instances
+//     created before the backup are back.
+//
+// Restoring the brokers from the Zeebe backup needs the pods stopped and
+// their volumes emptied, which an API-driven test cannot do, so the Zeebe
+// backup is only verified to be COMPLETED on every partition.
+//
+// The test deletes secondary-storage data, so it only runs with
+// TEST_BACKUP_ENABLED=true against a disposable cluster whose snapshot
+// repository and Zeebe backup store are configured.
+package backup
+
+import (
+	"testing"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/camunda"
+	"github.com/camunda/camunda-deployment-references/tests/integration/config"
+	"github.com/camunda/camunda-deployment-references/tests/integration/helpers"
+)
+
+var (
+	cfg    *config.Config
+	client *helpers.Client
+	api    *camunda.Client
+)
+
+func TestMain(m *testing.M) { helpers.RunSuite(m, helpers.Suite{Name: "backup", Setup: setupSuite}) }
+
+func setupSuite(env *helpers.SuiteEnv) (func(), error) {
+	cfg, client = env.Config, env.Client
+	api = camunda.New(client, cfg.ZeebeGatewayURL)
+	return nil, nil
+}
@@ -0,0 +1,245 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/camunda"
+	"github.com/camunda/camunda-deployment-references/tests/integration/camunda/fixtures"
+	"github.com/camunda/camunda-deployment-references/tests/integration/helpers"
+)
+
+const backupProcessID = "integration-test-backup"
+
+// camundaIndexPatterns are the index families the orchestration cluster
+// exports to; together they are the secondary-storage part of a backup.
+var camundaIndexPatterns = []string{"camunda-*", "operate-*", "tasklist-*"}
+
+func indexPatterns() []string {
+	out := make([]string, 0, len(camundaIndexPatterns))
+	for _, p := range camundaIndexPatterns {
+		if cfg.IndexPrefix != "" {
+			p = cfg.IndexPrefix + "-" + p
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// TestBackupRestore backs up the cluster, deletes the Camunda indices,
+// restores them from the snapshot and checks that the process instances
+// started before the backup are searchable again. See the package doc for
+// the sequence and what is not restored.
+func TestBackupRestore(t *testing.T) {
+	helpers.Track(t)
+	if !cfg.BackupEnabled {
+		helpers.Skip(t, "backup/restore deletes secondary-storage data; set TEST_BACKUP_ENABLED=true to run it")
+	}
+	if !cfg.Authenticated() {
+		helpers.Skip(t, "backup/restore requires authentication to seed process instances")
+	}
+	snapshots, err := helpers.NewSnapshots(cfg)
+	if errors.Is(err, helpers.ErrNoSnapshots) {
+		helpers.Skipf(t, "%v", err)
+	}
+	require.NoError(t, err)
+
+	definitionKey, instances := seedInstances(t)
+	patterns := indexPatterns()
+	indices, err := snapshots.Indices(t.Context(), strings.Join(patterns, ","))
+	require.NoError(t, err, "listing Camunda indices")
+	require.NotEmpty(t, indices, "no index matches %s (check TEST_INDEX_PREFIX)", strings.Join(patterns, ", "))
+
+	// Zeebe wants backup IDs to increase; a timestamp does that across runs.
+	backupID := time.Now().UnixMilli()
+	snapshotName := fmt.Sprintf("camunda-integration-test-%d", backupID)
+
+	backedUp := t.Run("Backup", func(t *testing.T) {
+		pauseExporting(t, true)
+
+		ctx, cancel := context.WithTimeout(t.Context(), cfg.BackupTimeout)
+		defer cancel()
+		info, err := snapshots.Create(ctx, cfg.BackupRepository, snapshotName, patterns)
+		require.NoError(t, err, "snapshot %s into repository %s", snapshotName, cfg.BackupRepository)
+		t.Cleanup(func() {
+			if err := snapshots.Delete(context.Background(), cfg.BackupRepository, snapshotName); err != nil {
+				t.Logf("cleanup: deleting snapshot %s: %v", snapshotName, err)
+			}
+		})
+		t.Logf("snapshot %s: %d indices, %d shards", snapshotName, len(info.Indices), info.Shards.Successful)
+
+		require.NoError(t, client.TakeBackup(ctx, cfg.OrchestrationURL, backupID), "scheduling Zeebe backup %d", backupID)
+		t.Cleanup(func() {
+			if err := client.DeleteBackup(context.Background(), cfg.OrchestrationURL, backupID); err != nil {
+				t.Logf("cleanup: deleting Zeebe backup %d: %v", backupID, err)
+			}
+		})
+		var backup *helpers.Backup
+		err = helpers.DeadlineRetry(cfg.BackupTimeout, 5*time.Second).Do(ctx, t, func() error {
+			b, err := client.GetBackup(ctx, cfg.OrchestrationURL, backupID)
+			if err != nil {
+				return err
+			}
+			switch b.State {
+			case helpers.BackupCompleted:
+				backup = b
+				return nil
+			case helpers.BackupFailed, helpers.BackupIncomplete:
+				return helpers.Permanent(fmt.Errorf("backup %d is %s: %s", backupID, b.State, b.FailureReason))
+			}
+			return fmt.Errorf("backup %d is %s", backupID, b.State)
+		})
+		require.NoError(t, err, "Zeebe backup %d should complete", backupID)
+		for _, p := range backup.Details {
+			assert.Equal(t, helpers.BackupCompleted, p.State, "partition %d: %s", p.PartitionID, p.FailureReason)
+		}
+
+		require.NoError(t, client.ResumeExporting(t.Context(), cfg.OrchestrationURL), "resuming exporting")
+	})
+	// Never delete data without a backup to restore it from.
+	require.True(t, backedUp, "backup failed; not deleting any data")
+
+	restored := t.Run("DeleteAndRestore", func(t *testing.T) {
+		// A hard pause keeps the exporter from recreating indices between
+		// the delete and the restore; a soft pause would keep exporting.
+		// What it holds back is exported after resuming.
+		pauseExporting(t, false)
+
+		ctx, cancel := context.WithTimeout(t.Context(), cfg.BackupTimeout)
+		defer cancel()
+		current, err := snapshots.Indices(ctx, strings.Join(patterns, ","))
+		require.NoError(t, err)
+		require.NoError(t, snapshots.DeleteIndices(ctx, current), "deleting %d Camunda indices", len(current))
+		t.Logf("deleted %d indices", len(current))
+
+		_, err = api.GetProcessInstance(ctx, firstKey(instances))
+		assert.Error(t, err, "instances must be gone once their indices are deleted")
+
+		info, err := snapshots.Restore(ctx, cfg.BackupRepository, snapshotName, patterns)
+		require.NoError(t, err, "restoring snapshot %s", snapshotName)
+		assert.Subset(t, info.Indices, indices, "every index present at backup time should be restored")
+		t.Logf("restored %d indices, %d shards", len(info.Indices), info.Shards.Successful)
+
+		require.NoError(t, client.ResumeExporting(t.Context(), cfg.OrchestrationURL), "resuming exporting")
+	})
+	require.True(t, restored, "restore failed")
+
+	t.Run("InstancesRestored", func(t *testing.T) {
+		err := helpers.DeadlineRetry(2*time.Minute, 5*time.Second).Do(t.Context(), t, func() error {
+			found, err := searchInstances(t.Context(), definitionKey)
+			if err != nil {
+				return err
+			}
+			var missing []string
+			for key := range instances {
+				state, ok := found[key]
+				if !ok {
+					missing = append(missing, key)
+					continue
+				}
+				if state != camunda.StateActive {
+					return helpers.Permanent(fmt.Errorf("instance %s is %s after restore, want %s", key, state, camunda.StateActive))
+				}
+			}
+			if len(missing) > 0 {
+				return fmt.Errorf("%d of %d instances not searchable after restore: %s", len(missing), len(instances), strings.Join(missing, ", "))
+			}
+			return nil
+		})
+		require.NoError(t, err, "instances created before the backup should be back")
+	})
+}
+
+// pauseExporting pauses exporting (softly or not, see
+// helpers.Client.PauseExporting) and resumes it when t ends, so a failed
+// step never leaves the cluster paused.
+func pauseExporting(t *testing.T, soft bool) {
+	t.Helper()
+	require.NoError(t, client.PauseExporting(t.Context(), cfg.OrchestrationURL, soft), "pausing exporting")
+	t.Cleanup(func() {
+		if err := client.ResumeExporting(context.Background(), cfg.OrchestrationURL); err != nil {
+			t.Logf("cleanup: resuming exporting: %v", err)
+		}
+	})
+}
+
+// seedInstances deploys a process whose service task nobody works on,
+// starts TEST_E2E_INSTANCES instances that therefore stay ACTIVE, and waits
+// until all of them are in secondary storage, so the snapshot contains
+// them. The instances are canceled when t ends.
+func seedInstances(t *testing.T) (definitionKey string, instances map[string]bool) {
+	t.Helper()
+	jobType := fmt.Sprintf("integration-test-backup-%d", time.Now().UnixNano())
+	resource := camunda.Resource{Name: "backup.bpmn", Content: []byte(fixtures.ServiceTaskBPMN(backupProcessID, jobType))}
+	err := helpers.ConstantRetry(5, cfg.RetryDelay).Do(t.Context(), t, func() error {
+		deployment, err := api.Deploy(t.Context(), "", resource)
+		if err != nil {
+			return err
+		}
+		processes := deployment.Processes()
+		if len(processes) != 1 {
+			return helpers.Permanent(fmt.Errorf("deployment contains %d processes, want 1", len(processes)))
+		}
+		definitionKey = processes[0].ProcessDefinitionKey
+		return nil
+	})
+	require.NoError(t, err, "deploy should succeed")
+
+	instances = map[string]bool{}
+	for i := 0; i < cfg.E2EInstances; i++ {
+		created, err := api.CreateProcessInstance(t.Context(), camunda.CreateProcessInstanceRequest{ProcessDefinitionKey: definitionKey})
+		require.NoError(t, err, "starting instance %d should succeed", i)
+		instances[created.ProcessInstanceKey] = true
+	}
+	t.Cleanup(func() {
+		for key := range instances {
+			if err := api.CancelProcessInstance(context.Background(), key); err != nil {
+				t.Logf("cleanup: canceling instance %s: %v", key, err)
+			}
+		}
+	})
+
+	err = helpers.DeadlineRetry(cfg.E2EMaxLatency, 2*time.Second).Do(t.Context(), t, func() error {
+		found, err := searchInstances(t.Context(), definitionKey)
+		if err != nil {
+			return err
+		}
+		if len(found) < len(instances) {
+			return fmt.Errorf("%d of %d instances in secondary storage", len(found), len(instances))
+		}
+		return nil
+	})
+	require.NoError(t, err, "seeded instances should be exported before the backup")
+	t.Logf("seeded %d instances of %s (key %s)", len(instances), backupProcessID, definitionKey)
+	return definitionKey, instances
+}
+
+// searchInstances returns the state of every instance of definitionKey in
+// secondary storage, by instance key.
+func searchInstances(ctx context.Context, definitionKey string) (map[string]string, error) {
+	items, err := camunda.Collect(ctx, 100, func(ctx context.Context, p camunda.Page) (*camunda.SearchResult[camunda.ProcessInstance], error) {
+		return api.SearchProcessInstances(ctx, camunda.ProcessInstanceFilter{ProcessDefinitionKey: definitionKey}, p)
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]string, len(items))
+	for _, pi := range items {
+		out[pi.ProcessInstanceKey] = pi.State
+	}
+	return out, nil
+}
+
+func firstKey(m map[string]bool) string {
+	for k := range m {
+		return k
+	}
+	return ""
+}
@@ -0,0 +1,228 @@
+package camunda
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Tenant is a tenant of the orchestration cluster.
+type Tenant struct {
+	TenantKey   string `json:"tenantKey,omitempty"`
+	TenantID    string `json:"tenantId"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// TenantFilter narrows a tenant search. Zero fields are omitted.
+type TenantFilter struct {
+	TenantID string `json:"tenantId,omitempty"`
+	Name     string `json:"name,omitempty"`
+}
+
+// CreateTenant creates t. TenantKey is ignored on input.
+func (c *Client) CreateTenant(ctx context.Context, t Tenant) (*Tenant, error) {
+	t.TenantKey = ""
+	var out Tenant
+	if err := c.call(ctx, http.MethodPost, "/v2/tenants", t, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetTenant returns the tenant with the given ID.
+func (c *Client) GetTenant(ctx context.Context, tenantID string) (*Tenant, error) {
+	var out Tenant
+	if err := c.call(ctx, http.MethodGet, "/v2/tenants/"+url.PathEscape(tenantID), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteTenant deletes the tenant with the given ID.
+func (c *Client) DeleteTenant(ctx context.Context, tenantID string) error {
+	return c.call(ctx, http.MethodDelete, "/v2/tenants/"+url.PathEscape(tenantID), nil, nil)
+}
+
+// SearchTenants returns one page of matching tenants.
+func (c *Client) SearchTenants(ctx context.Context, filter TenantFilter, page Page) (*SearchResult[Tenant], error) {
+	return search[Tenant](ctx, c, "/v2/tenants/search", filter, page)
+}
+
+// AssignUserToTenant makes username a member of tenantID.
+func (c *Client) AssignUserToTenant(ctx context.Context, tenantID, username string) error {
+	return c.call(ctx, http.MethodPut, tenantMemberPath(tenantID, "users", username), struct{}{}, nil)
+}
+
+// UnassignUserFromTenant removes username from tenantID.
+func (c *Client) UnassignUserFromTenant(ctx context.Context, tenantID, username string) error {
+	return c.call(ctx, http.MethodDelete, tenantMemberPath(tenantID, "users", username), nil, nil)
+}
+
+// AssignClientToTenant makes the OIDC client clientID a member of tenantID,
+// so M2M tokens for that client can act on the tenant.
+func (c *Client) AssignClientToTenant(ctx context.Context, tenantID, clientID string) error {
+	return c.call(ctx, http.MethodPut, tenantMemberPath(tenantID, "clients", clientID), struct{}{}, nil)
+}
+
+// UnassignClientFromTenant removes the OIDC client clientID from tenantID.
+func (c *Client) UnassignClientFromTenant(ctx context.Context, tenantID, clientID string) error {
+	return c.call(ctx, http.MethodDelete, tenantMemberPath(tenantID, "clients", clientID), nil, nil)
+}
+
+func tenantMemberPath(tenantID, kind, member string) string {
+	return "/v2/tenants/" + url.PathEscape(tenantID) + "/" + kind + "/" + url.PathEscape(member)
+}
+
+// User is a user managed by the orchestration cluster's built-in identity.
+type User struct {
+	Username string `json:"username"`
+	Name     string `json:"name,omitempty"`
+	Email    string `json:"email,omitempty"`
+	// Password is only sent on create and never returned.
+	Password string `json:"password,omitempty"`
+}
+
+// UserFilter narrows a user search. Zero fields are omitted.
+type UserFilter struct {
+	Username string `json:"username,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Email    string `json:"email,omitempty"`
+}
+
+// CreateUser creates u.
+func (c *Client) CreateUser(ctx context.Context, u User) (*User, error) {
+	var out User
+	if err := c.call(ctx, http.MethodPost, "/v2/users", u, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetUser returns the user with the given username.
+func (c *Client) GetUser(ctx context.Context, username string) (*User, error) {
+	var out User
+	if err := c.call(ctx, http.MethodGet, "/v2/users/"+url.PathEscape(username), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteUser deletes the user with the given username.
+func (c *Client) DeleteUser(ctx context.Context, username string) error {
+	return c.call(ctx, http.MethodDelete, "/v2/users/"+url.PathEscape(username), nil, nil)
+}
+
+// SearchUsers returns one page of matching users.
+func (c *Client) SearchUsers(ctx context.Context, filter UserFilter, page Page) (*SearchResult[User], error) {
+	return search[User](ctx, c, "/v2/users/search", filter, page)
+}
+
+// Group is a group of users (and clients) in the orchestration cluster's
+// built-in identity.
+type Group struct {
+	GroupID     string `json:"groupId"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// CreateGroup creates g.
+func (c *Client) CreateGroup(ctx context.Context, g Group) (*Group, error) {
+	var out Group
+	if err := c.call(ctx, http.MethodPost, "/v2/groups", g, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteGroup deletes the group with the given ID.
+func (c *Client) DeleteGroup(ctx context.Context, groupID string) error {
+	return c.call(ctx, http.MethodDelete, "/v2/groups/"+url.PathEscape(groupID), nil, nil)
+}
+
+// AssignUserToGroup makes username a member of groupID.
+func (c *Client) AssignUserToGroup(ctx context.Context, groupID, username string) error {
+	return c.call(ctx, http.MethodPut, "/v2/groups/"+url.PathEscape(groupID)+"/users/"+url.PathEscape(username), struct{}{}, nil)
+}
+
+// Role bundles authorizations that can be granted to users, groups and
+// clients.
+type Role struct {
+	RoleID      string `json:"roleId"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// CreateRole creates r.
+func (c *Client) CreateRole(ctx context.Context, r Role) (*Role, error) {
+	var out Role
+	if err := c.call(ctx, http.MethodPost, "/v2/roles", r, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteRole deletes the role with the given ID.
+func (c *Client) DeleteRole(ctx context.Context, roleID string) error {
+	return c.call(ctx, http.MethodDelete, "/v2/roles/"+url.PathEscape(roleID), nil, nil)
+}
+
+// AssignRoleToUser grants roleID to username.
+func (c *Client) AssignRoleToUser(ctx context.Context, roleID, username string) error {
+	return c.call(ctx, http.MethodPut, "/v2/roles/"+url.PathEscape(roleID)+"/users/"+url.PathEscape(username), struct{}{}, nil)
+}
+
+// AssignRoleToGroup grants roleID to every member of groupID.
+func (c *Client) AssignRoleToGroup(ctx context.Context, roleID, groupID string) error {
+	return c.call(ctx, http.MethodPut, "/v2/roles/"+url.PathEscape(roleID)+"/groups/"+url.PathEscape(groupID), struct{}{}, nil)
+}
+
+// Authorization owner types.
+const (
+	OwnerUser   = "USER"
+	OwnerGroup  = "GROUP"
+	OwnerRole   = "ROLE"
+	OwnerClient = "CLIENT"
+)
+
+// Authorization resource types used by the suite.
+const (
+	ResourceTypeResource          = "RESOURCE"
+	ResourceTypeProcessDefinition = "PROCESS_DEFINITION"
+)
+
+// Permission types used by the suite.
+const (
+	PermissionCreate                = "CREATE"
+	PermissionReadProcessDefinition = "READ_PROCESS_DEFINITION"
+	PermissionReadProcessInstance   = "READ_PROCESS_INSTANCE"
+	PermissionCreateProcessInstance = "CREATE_PROCESS_INSTANCE"
+)
+
+// Authorization grants PermissionTypes on ResourceID ("*" for all resources
+// of ResourceType) to an owner.
+type Authorization struct {
+	AuthorizationKey string   `json:"authorizationKey,omitempty"`
+	OwnerID          string   `json:"ownerId"`
+	OwnerType        string   `json:"ownerType"`
+	ResourceID       string   `json:"resourceId"`
+	ResourceType     string   `json:"resourceType"`
+	PermissionTypes  []string `json:"permissionTypes"`
+}
+
+// CreateAuthorization creates a and returns its key.
+func (c *Client) CreateAuthorization(ctx context.Context, a Authorization) (string, error) {
+	a.AuthorizationKey = ""
+	var out struct {
+		AuthorizationKey string `json:"authorizationKey"`
+	}
+	if err := c.call(ctx, http.MethodPost, "/v2/authorizations", a, &out); err != nil {
+		return "", err
+	}
+	return out.AuthorizationKey, nil
+}
+
+// DeleteAuthorization deletes the authorization with the given key.
+func (c *Client) DeleteAuthorization(ctx context.Context, authorizationKey string) error {
+	return c.call(ctx, http.MethodDelete, "/v2/authorizations/"+url.PathEscape(authorizationKey), nil, nil)
+}
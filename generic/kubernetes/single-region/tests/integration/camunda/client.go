@@ -0,0 +1,263 @@
+// Package camunda is a small typed client for the Camunda 8 orchestration
+// cluster REST API (/v2), plus the Optimize and Web Modeler public APIs
+// (OptimizeClient, WebModelerClient). It sits on top of helpers.Client, so
+// every request uses the suite's configured authentication, TLS and
+// timeouts, and lets tests assert on response fields instead of substrings.
+//
+// Non-2xx responses are returned as *helpers.StatusError, so calls compose
+// with helpers.RetryPolicy: 5xx are retried, 4xx fail immediately.
+package camunda
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/helpers"
+)
+
+// Client calls the /v2 REST API of one orchestration cluster gateway.
+type Client struct {
+	http    *helpers.Client
+	baseURL string
+}
+
+// New returns a Client for the gateway at baseURL (typically
+// cfg.ZeebeGatewayURL), authenticating through hc.
+func New(hc *helpers.Client, baseURL string) *Client {
+	return &Client{http: hc, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// BaseURL returns the gateway URL the client talks to.
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// call sends a JSON request to path and decodes a JSON response into out.
+// in and out may be nil.
+func (c *Client) call(ctx context.Context, method, path string, in, out interface{}) error {
+	u := c.baseURL + path
+	var (
+		resp *http.Response
+		err  error
+	)
+	switch {
+	case in == nil && method == http.MethodGet:
+		resp, err = c.http.GetContext(ctx, u)
+	case in == nil && method == http.MethodDelete:
+		resp, err = c.http.Delete(ctx, u)
+	default:
+		body := "{}"
+		if in != nil {
+			b, err := json.Marshal(in)
+			if err != nil {
+				return fmt.Errorf("encoding %s %s request: %w", method, path, err)
+			}
+			body = string(b)
+		}
+		switch method {
+		case http.MethodPost:
+			resp, err = c.http.PostJSONContext(ctx, u, body)
+		case http.MethodPut:
+			resp, err = c.http.PutJSON(ctx, u, body)
+		case http.MethodPatch:
+			resp, err = c.http.PatchJSON(ctx, u, body)
+		default:
+			return fmt.Errorf("unsupported method %s with a body", method)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return decode(resp, out)
+}
+
+// decode reads resp, returning a *helpers.StatusError for non-2xx statuses
+// and unmarshalling the body into out otherwise.
+func decode(resp *http.Response, out interface{}) error {
+	body, err := helpers.ReadBody(resp)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return helpers.NewStatusError(resp, body)
+	}
+	if out == nil || len(strings.TrimSpace(body)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(body), out); err != nil {
+		return fmt.Errorf("decoding %s %s response: %w", resp.Request.Method, resp.Request.URL.Path, err)
+	}
+	return nil
+}
+
+// Time is a timestamp as returned by the API. Some gateway versions use a
+// "+0000" offset, which time.RFC3339 rejects, so both forms are accepted.
+type Time struct{ time.Time }
+
+var timeLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999Z0700"}
+
+func (t *Time) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(b, []byte("null")) {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if s == "" {
+		return nil
+	}
+	var err error
+	for _, layout := range timeLayouts {
+		var parsed time.Time
+		if parsed, err = time.Parse(layout, s); err == nil {
+			t.Time = parsed
+			return nil
+		}
+	}
+	return fmt.Errorf("parsing time %q: %w", s, err)
+}
+
+func (t Time) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.Format(time.RFC3339Nano))
+}
+
+// Topology is the GET /v2/topology response.
+type Topology struct {
+	Brokers               []Broker `json:"brokers"`
+	ClusterSize           int      `json:"clusterSize"`
+	PartitionsCount       int      `json:"partitionsCount"`
+	ReplicationFactor     int      `json:"replicationFactor"`
+	GatewayVersion        string   `json:"gatewayVersion"`
+	LastCompletedChangeID string   `json:"lastCompletedChangeId,omitempty"`
+}
+
+// Broker is one broker in a Topology.
+type Broker struct {
+	NodeID     int         `json:"nodeId"`
+	Host       string      `json:"host"`
+	Port       int         `json:"port"`
+	Version    string      `json:"version"`
+	Partitions []Partition `json:"partitions"`
+}
+
+// Partition is one partition replica hosted by a Broker.
+type Partition struct {
+	PartitionID int    `json:"partitionId"`
+	Role        string `json:"role"`   // leader, follower, inactive
+	Health      string `json:"health"` // healthy, unhealthy, dead
+}
+
+// Leaders maps each partition ID to the node ID of its leader.
+func (t *Topology) Leaders() map[int]int {
+	leaders := map[int]int{}
+	for _, b := range t.Brokers {
+		for _, p := range b.Partitions {
+			if p.Role == "leader" {
+				leaders[p.PartitionID] = b.NodeID
+			}
+		}
+	}
+	return leaders
+}
+
+// Topology returns the cluster topology as seen by the gateway.
+func (c *Client) Topology(ctx context.Context) (*Topology, error) {
+	var t Topology
+	if err := c.call(ctx, http.MethodGet, "/v2/topology", nil, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// Resource is one file to deploy (BPMN, DMN or form).
+type Resource struct {
+	Name    string
+	Content []byte
+}
+
+// Deployment is the POST /v2/deployments response.
+type Deployment struct {
+	DeploymentKey string               `json:"deploymentKey"`
+	TenantID      string               `json:"tenantId"`
+	Deployments   []DeploymentMetadata `json:"deployments"`
+}
+
+// DeploymentMetadata describes one deployed resource; exactly one field is
+// set depending on the resource type.
+type DeploymentMetadata struct {
+	ProcessDefinition  *DeployedProcess  `json:"processDefinition,omitempty"`
+	DecisionDefinition *DeployedDecision `json:"decisionDefinition,omitempty"`
+	Form               *DeployedForm     `json:"form,omitempty"`
+}
+
+// DeployedProcess is a process definition created by a deployment.
+type DeployedProcess struct {
+	ProcessDefinitionID      string `json:"processDefinitionId"`
+	ProcessDefinitionKey     string `json:"processDefinitionKey"`
+	ProcessDefinitionVersion int    `json:"processDefinitionVersion"`
+	ResourceName             string `json:"resourceName"`
+	TenantID                 string `json:"tenantId"`
+}
+
+// DeployedDecision is a decision definition created by a deployment.
+type DeployedDecision struct {
+	DecisionDefinitionID  string `json:"decisionDefinitionId"`
+	DecisionDefinitionKey string `json:"decisionDefinitionKey"`
+	Version               int    `json:"version"`
+	Name                  string `json:"name"`
+	TenantID              string `json:"tenantId"`
+}
+
+// DeployedForm is a form created by a deployment.
+type DeployedForm struct {
+	FormID       string `json:"formId"`
+	FormKey      string `json:"formKey"`
+	Version      int    `json:"version"`
+	ResourceName string `json:"resourceName"`
+	TenantID     string `json:"tenantId"`
+}
+
+// Processes returns the process definitions in the deployment.
+func (d *Deployment) Processes() []DeployedProcess {
+	var out []DeployedProcess
+	for _, m := range d.Deployments {
+		if m.ProcessDefinition != nil {
+			out = append(out, *m.ProcessDefinition)
+		}
+	}
+	return out
+}
+
+// Deploy uploads resources as one multipart deployment. tenantID may be
+// empty for the default tenant.
+func (c *Client) Deploy(ctx context.Context, tenantID string, resources ...Resource) (*Deployment, error) {
+	if len(resources) == 0 {
+		return nil, fmt.Errorf("deploy: no resources")
+	}
+	files := make([]helpers.FormFile, 0, len(resources))
+	for _, r := range resources {
+		files = append(files, helpers.FormFile{Field: "resources", Filename: r.Name, Content: r.Content})
+	}
+	var fields map[string]string
+	if tenantID != "" {
+		fields = map[string]string{"tenantId": tenantID}
+	}
+	resp, err := c.http.PostMultipart(ctx, c.baseURL+"/v2/deployments", fields, files...)
+	if err != nil {
+		return nil, err
+	}
+	var d Deployment
+	if err := decode(resp, &d); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
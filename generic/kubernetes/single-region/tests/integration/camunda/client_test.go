@@ -0,0 +1,147 @@
+package camunda
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/config"
+	"github.com/camunda/camunda-deployment-references/tests/integration/helpers"
+)
+
+func newTestClient(t *testing.T, h http.Handler) *Client {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	hc := helpers.NewClient(&config.Config{AuthMode: "none", HTTPTimeout: 5 * time.Second})
+	return New(hc, srv.URL+"/")
+}
+
+func TestTopology(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/topology", r.URL.Path)
+		fmt.Fprint(w, `{"brokers":[
+			{"nodeId":0,"host":"b0","port":26501,"version":"8.8.0","partitions":[{"partitionId":1,"role":"leader","health":"healthy"}]},
+			{"nodeId":1,"host":"b1","port":26501,"version":"8.8.0","partitions":[{"partitionId":1,"role":"follower","health":"healthy"},{"partitionId":2,"role":"leader","health":"healthy"}]}
+		],"clusterSize":2,"partitionsCount":2,"replicationFactor":1,"gatewayVersion":"8.8.0"}`)
+	}))
+
+	topo, err := c.Topology(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 2, topo.ClusterSize)
+	assert.Equal(t, "8.8.0", topo.GatewayVersion)
+	assert.Equal(t, map[int]int{1: 0, 2: 1}, topo.Leaders())
+}
+
+func TestDeploy(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/deployments", r.URL.Path)
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		assert.Equal(t, "tenant-a", r.FormValue("tenantId"))
+		fh := r.MultipartForm.File["resources"]
+		require.Len(t, fh, 1)
+		assert.Equal(t, "p.bpmn", fh[0].Filename)
+		fmt.Fprint(w, `{"deploymentKey":"2251799813685249","tenantId":"tenant-a","deployments":[
+			{"processDefinition":{"processDefinitionId":"p","processDefinitionKey":"2251799813685250","processDefinitionVersion":3,"resourceName":"p.bpmn","tenantId":"tenant-a"}}]}`)
+	}))
+
+	d, err := c.Deploy(t.Context(), "tenant-a", Resource{Name: "p.bpmn", Content: []byte("<bpmn/>")})
+	require.NoError(t, err)
+	require.Len(t, d.Processes(), 1)
+	assert.Equal(t, "p", d.Processes()[0].ProcessDefinitionID)
+	assert.Equal(t, 3, d.Processes()[0].ProcessDefinitionVersion)
+}
+
+func TestCollectFollowsCursor(t *testing.T) {
+	requests := 0
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Filter ProcessInstanceFilter `json:"filter"`
+			Page   Page                  `json:"page"`
+		}
+		body, _ := io.ReadAll(r.Body)
+		require.NoError(t, json.Unmarshal(body, &req))
+		assert.Equal(t, StateCompleted, req.Filter.State)
+		assert.Equal(t, 2, req.Page.Limit)
+		requests++
+
+		switch req.Page.After {
+		case "":
+			fmt.Fprint(w, `{"items":[{"processInstanceKey":"1","state":"COMPLETED","endDate":"2025-01-02T03:04:05.000+0000"},{"processInstanceKey":"2","state":"COMPLETED"}],"page":{"totalItems":3,"endCursor":"c1"}}`)
+		case "c1":
+			fmt.Fprint(w, `{"items":[{"processInstanceKey":"3","state":"COMPLETED","endDate":"2025-01-02T03:04:05Z"}],"page":{"totalItems":3,"endCursor":"c2"}}`)
+		default:
+			t.Errorf("unexpected cursor %q", req.Page.After)
+		}
+	}))
+
+	all, err := Collect(t.Context(), 2, func(ctx context.Context, p Page) (*SearchResult[ProcessInstance], error) {
+		return c.SearchProcessInstances(ctx, ProcessInstanceFilter{State: StateCompleted}, p)
+	})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, 2, requests)
+	assert.Equal(t, 2025, all[0].EndDate.Year())
+	assert.True(t, all[1].EndDate.IsZero())
+	assert.True(t, all[0].EndDate.Equal(all[2].EndDate.Time))
+}
+
+func TestErrorsAreStatusErrors(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"title":"NOT_FOUND","status":404}`)
+	}))
+
+	err := c.DeleteTenant(t.Context(), "missing tenant")
+	require.Error(t, err)
+	var se *helpers.StatusError
+	require.True(t, errors.As(err, &se))
+	assert.Equal(t, http.StatusNotFound, se.StatusCode)
+	assert.Equal(t, "/v2/tenants/missing tenant", se.Path)
+	assert.False(t, helpers.IsRetryable(err))
+}
+
+func TestOptimizeLabelVariablesNotImported(t *testing.T) {
+	o := &OptimizeClient{c: newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/public/variables/labels", r.URL.Path)
+		var body struct {
+			DefinitionKey string `json:"definitionKey"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if body.DefinitionKey != "imported" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))}
+
+	err := o.LabelVariables(t.Context(), "missing", VariableLabel{VariableName: "v", VariableType: "String", VariableLabel: "V"})
+	assert.ErrorIs(t, err, ErrNotImported)
+	assert.NoError(t, o.LabelVariables(t.Context(), "imported"))
+}
+
+func TestWebModelerCreateFile(t *testing.T) {
+	w := &WebModelerClient{c: newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/files", r.URL.Path)
+		var f NewFile
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&f))
+		assert.Equal(t, FileTypeBPMN, f.FileType)
+		fmt.Fprintf(w, `{"metadata":{"id":"f-1","name":%q,"projectId":%q,"type":"BPMN","revision":1,"simplePath":"p/f.bpmn"},"content":"<bpmn/>"}`, f.Name, f.ProjectID)
+	}))}
+
+	f, err := w.CreateFile(t.Context(), NewFile{Name: "f.bpmn", ProjectID: "p-1", Content: "<bpmn/>", FileType: FileTypeBPMN})
+	require.NoError(t, err)
+	assert.Equal(t, "f-1", f.Metadata.ID)
+	assert.Equal(t, "p-1", f.Metadata.ProjectID)
+	assert.Equal(t, 1, f.Metadata.Revision)
+}
@@ -0,0 +1,135 @@
+// Package fixtures holds the BPMN processes the suites deploy, so every suite
+// exercises the same, known-valid models.
+package fixtures
+
+import "fmt"
+
+// BasicProcessBPMN returns a minimal BPMN with start -> end. Used to validate
+// the deployment endpoint without exercising any worker.
+func BasicProcessBPMN(processID string) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<bpmn:definitions xmlns:bpmn="http://www.omg.org/spec/BPMN/20100524/MODEL"
+                  id="Definitions_1"
+                  targetNamespace="http://bpmn.io/schema/bpmn">
+  <bpmn:process id="%s" name="Integration Test" isExecutable="true">
+    <bpmn:startEvent id="start">
+      <bpmn:outgoing>toEnd</bpmn:outgoing>
+    </bpmn:startEvent>
+    <bpmn:endEvent id="end">
+      <bpmn:incoming>toEnd</bpmn:incoming>
+    </bpmn:endEvent>
+    <bpmn:sequenceFlow id="toEnd" sourceRef="start" targetRef="end"/>
+  </bpmn:process>
+</bpmn:definitions>`, processID)
+}
+
+// InboundConnectorBPMN returns a BPMN that declares an HTTP-webhook inbound
+// connector start event. The engine validates the connector type at deploy
+// time, so this exercises the deployment validation path covered by the
+// venom "TEST - Deploy Inbound Connector Process" step.
+func InboundConnectorBPMN(processID string) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<bpmn:definitions xmlns:bpmn="http://www.omg.org/spec/BPMN/20100524/MODEL"
+                  xmlns:zeebe="http://camunda.org/schema/zeebe/1.0"
+                  id="Definitions_inbound"
+                  targetNamespace="http://bpmn.io/schema/bpmn">
+  <bpmn:process id="%s" name="Integration Test Inbound Connector" isExecutable="true">
+    <bpmn:startEvent id="webhook-start" name="webhook">
+      <bpmn:extensionElements>
+        <zeebe:properties>
+          <zeebe:property name="inbound.type" value="io.camunda:webhook:1" />
+          <zeebe:property name="inbound.method" value="POST" />
+          <zeebe:property name="inbound.context" value="integration-test-webhook" />
+          <zeebe:property name="inbound.shouldValidateHmac" value="disabled" />
+        </zeebe:properties>
+      </bpmn:extensionElements>
+      <bpmn:outgoing>toEnd</bpmn:outgoing>
+    </bpmn:startEvent>
+    <bpmn:endEvent id="end">
+      <bpmn:incoming>toEnd</bpmn:incoming>
+    </bpmn:endEvent>
+    <bpmn:sequenceFlow id="toEnd" sourceRef="webhook-start" targetRef="end"/>
+  </bpmn:process>
+</bpmn:definitions>`, processID)
+}
+
+// ServiceTaskBPMN returns a BPMN with start -> service task -> end. The task
+// creates a job of jobType that the test completes through the REST job API,
+// so an instance only reaches COMPLETED when a worker has handled it.
+func ServiceTaskBPMN(processID, jobType string) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<bpmn:definitions xmlns:bpmn="http://www.omg.org/spec/BPMN/20100524/MODEL"
+                  xmlns:zeebe="http://camunda.org/schema/zeebe/1.0"
+                  id="Definitions_service_task"
+                  targetNamespace="http://bpmn.io/schema/bpmn">
+  <bpmn:process id="%s" name="Integration Test Service Task" isExecutable="true">
+    <bpmn:startEvent id="start">
+      <bpmn:outgoing>toTask</bpmn:outgoing>
+    </bpmn:startEvent>
+    <bpmn:serviceTask id="work" name="work">
+      <bpmn:extensionElements>
+        <zeebe:taskDefinition type="%s" retries="3" />
+      </bpmn:extensionElements>
+      <bpmn:incoming>toTask</bpmn:incoming>
+      <bpmn:outgoing>toEnd</bpmn:outgoing>
+    </bpmn:serviceTask>
+    <bpmn:endEvent id="end">
+      <bpmn:incoming>toEnd</bpmn:incoming>
+    </bpmn:endEvent>
+    <bpmn:sequenceFlow id="toTask" sourceRef="start" targetRef="work"/>
+    <bpmn:sequenceFlow id="toEnd" sourceRef="work" targetRef="end"/>
+  </bpmn:process>
+</bpmn:definitions>`, processID, jobType)
+}
+
+// WebhookRESTBPMN returns a BPMN started by an HTTP-webhook inbound connector
+// at webhookContext, followed by a REST outbound connector task. The webhook
+// body supplies the target ("callbackURL") and the JSON body to send
+// ("payload"), so one deployment serves any mock address. Mirrors
+// c8-multi-region-dummy-connector-flow.bpmn from the EKS dual-region suite.
+func WebhookRESTBPMN(processID, webhookContext string) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<bpmn:definitions xmlns:bpmn="http://www.omg.org/spec/BPMN/20100524/MODEL"
+                  xmlns:zeebe="http://camunda.org/schema/zeebe/1.0"
+                  id="Definitions_webhook_rest"
+                  targetNamespace="http://bpmn.io/schema/bpmn">
+  <bpmn:process id="%s" name="Integration Test Webhook to REST" isExecutable="true">
+    <bpmn:startEvent id="webhook-start" name="webhook">
+      <bpmn:extensionElements>
+        <zeebe:properties>
+          <zeebe:property name="inbound.type" value="io.camunda:webhook:1" />
+          <zeebe:property name="inbound.method" value="post" />
+          <zeebe:property name="inbound.context" value="%s" />
+          <zeebe:property name="inbound.shouldValidateHmac" value="disabled" />
+          <zeebe:property name="inbound.auth.type" value="NONE" />
+          <zeebe:property name="resultExpression" value="={callbackURL: request.body.callbackURL, payload: request.body.payload}" />
+        </zeebe:properties>
+      </bpmn:extensionElements>
+      <bpmn:outgoing>toRest</bpmn:outgoing>
+    </bpmn:startEvent>
+    <bpmn:serviceTask id="rest-call" name="call mock">
+      <bpmn:extensionElements>
+        <zeebe:taskDefinition type="io.camunda:http-json:1" retries="3" />
+        <zeebe:ioMapping>
+          <zeebe:input source="noAuth" target="authentication.type" />
+          <zeebe:input source="POST" target="method" />
+          <zeebe:input source="=callbackURL" target="url" />
+          <zeebe:input source="=payload" target="body" />
+          <zeebe:input source="=20" target="connectionTimeoutInSeconds" />
+          <zeebe:input source="=20" target="readTimeoutInSeconds" />
+        </zeebe:ioMapping>
+        <zeebe:taskHeaders>
+          <zeebe:header key="retryBackoff" value="PT5S" />
+        </zeebe:taskHeaders>
+      </bpmn:extensionElements>
+      <bpmn:incoming>toRest</bpmn:incoming>
+      <bpmn:outgoing>toEnd</bpmn:outgoing>
+    </bpmn:serviceTask>
+    <bpmn:endEvent id="end">
+      <bpmn:incoming>toEnd</bpmn:incoming>
+    </bpmn:endEvent>
+    <bpmn:sequenceFlow id="toRest" sourceRef="webhook-start" targetRef="rest-call"/>
+    <bpmn:sequenceFlow id="toEnd" sourceRef="rest-call" targetRef="end"/>
+  </bpmn:process>
+</bpmn:definitions>`, processID, webhookContext)
+}
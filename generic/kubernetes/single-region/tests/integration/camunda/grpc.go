@@ -0,0 +1,322 @@
+package camunda
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/helpers"
+)
+
+// GRPCClient makes unary calls to the Zeebe gateway gRPC API over the
+// suite's helpers.Client, so calls carry the same credentials (OIDC bearer
+// or basic) as REST requests.
+//
+// The module stays free of grpc-go and generated Zeebe stubs: a unary gRPC
+// call is one HTTP/2 POST with a length-prefixed protobuf body and the status
+// in the trailers, and the two messages needed here are small enough to
+// encode and decode by hand.
+type GRPCClient struct {
+	http    *helpers.Client
+	baseURL string
+}
+
+// NewGRPC returns a GRPCClient for the gateway at address (host[:port], as
+// in CAMUNDA_DOMAIN_GRPC). Port 443 and TLS are assumed when no port or
+// scheme is given; an explicit http:// address is rejected because the
+// client does not speak cleartext HTTP/2.
+func NewGRPC(hc *helpers.Client, address string) (*GRPCClient, error) {
+	switch {
+	case strings.HasPrefix(address, "http://"):
+		return nil, fmt.Errorf("gRPC over cleartext HTTP/2 is not supported: %s", address)
+	case !strings.HasPrefix(address, "https://"):
+		address = "https://" + address
+	}
+	return &GRPCClient{http: hc, baseURL: strings.TrimRight(address, "/")}, nil
+}
+
+// GRPCStatusError is a non-OK gRPC status, e.g. 16 UNAUTHENTICATED.
+type GRPCStatusError struct {
+	Method  string
+	Code    int
+	Message string
+}
+
+var grpcCodeNames = map[int]string{
+	1: "CANCELLED", 2: "UNKNOWN", 3: "INVALID_ARGUMENT", 4: "DEADLINE_EXCEEDED",
+	5: "NOT_FOUND", 7: "PERMISSION_DENIED", 8: "RESOURCE_EXHAUSTED",
+	12: "UNIMPLEMENTED", 13: "INTERNAL", 14: "UNAVAILABLE", 16: "UNAUTHENTICATED",
+}
+
+func (e *GRPCStatusError) Error() string {
+	name := grpcCodeNames[e.Code]
+	if name == "" {
+		name = strconv.Itoa(e.Code)
+	}
+	return fmt.Sprintf("%s: gRPC status %s: %s", e.Method, name, e.Message)
+}
+
+// GRPC status codes tests branch on.
+const (
+	GRPCUnimplemented   = 12
+	GRPCUnavailable     = 14
+	GRPCUnauthenticated = 16
+)
+
+// invoke performs one unary call and returns the response message bytes.
+func (g *GRPCClient) invoke(ctx context.Context, method string, msg []byte) ([]byte, error) {
+	frame := make([]byte, 5+len(msg))
+	binary.BigEndian.PutUint32(frame[1:5], uint32(len(msg)))
+	copy(frame[5:], msg)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.baseURL+method, bytes.NewReader(frame))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("TE", "trailers")
+
+	resp, err := g.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.ProtoMajor != 2 {
+		return nil, fmt.Errorf("%s: gRPC requires HTTP/2 but the server answered over %s (check the ingress negotiates ALPN h2)", method, resp.Proto)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, helpers.NewStatusError(resp, string(body))
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%s: reading response: %w", method, err)
+	}
+
+	// Trailers-only responses (immediate errors) carry the status in the
+	// headers; otherwise it arrives in the trailers after the body.
+	status := resp.Trailer.Get("Grpc-Status")
+	message := resp.Trailer.Get("Grpc-Message")
+	if status == "" {
+		status = resp.Header.Get("Grpc-Status")
+		message = resp.Header.Get("Grpc-Message")
+	}
+	if status == "" {
+		return nil, fmt.Errorf("%s: response has no grpc-status", method)
+	}
+	if code, _ := strconv.Atoi(status); code != 0 {
+		return nil, &GRPCStatusError{Method: method, Code: code, Message: message}
+	}
+
+	if len(body) < 5 {
+		return nil, fmt.Errorf("%s: short gRPC frame (%d bytes)", method, len(body))
+	}
+	if body[0] != 0 {
+		return nil, fmt.Errorf("%s: compressed gRPC responses are not supported", method)
+	}
+	n := binary.BigEndian.Uint32(body[1:5])
+	if int(n) != len(body)-5 {
+		return nil, fmt.Errorf("%s: gRPC frame length %d does not match body (%d bytes)", method, n, len(body)-5)
+	}
+	return body[5:], nil
+}
+
+// Topology calls gateway_protocol.Gateway/Topology and returns the result in
+// the same shape as the REST Topology, with roles and health lower-cased
+// ("leader", "healthy") so the two can be compared directly.
+func (g *GRPCClient) Topology(ctx context.Context) (*Topology, error) {
+	msg, err := g.invoke(ctx, "/gateway_protocol.Gateway/Topology", nil)
+	if err != nil {
+		return nil, err
+	}
+	return decodeTopology(msg)
+}
+
+// Health serving statuses (grpc.health.v1.HealthCheckResponse.ServingStatus).
+const (
+	HealthUnknown        = "UNKNOWN"
+	HealthServing        = "SERVING"
+	HealthNotServing     = "NOT_SERVING"
+	HealthServiceUnknown = "SERVICE_UNKNOWN"
+)
+
+var healthStatuses = []string{HealthUnknown, HealthServing, HealthNotServing, HealthServiceUnknown}
+
+// Health calls grpc.health.v1.Health/Check for service ("" is the server as
+// a whole) and returns its serving status.
+func (g *GRPCClient) Health(ctx context.Context, service string) (string, error) {
+	var req []byte
+	if service != "" {
+		req = appendBytesField(nil, 1, []byte(service))
+	}
+	msg, err := g.invoke(ctx, "/grpc.health.v1.Health/Check", req)
+	if err != nil {
+		return "", err
+	}
+	fields, err := readFields(msg)
+	if err != nil {
+		return "", fmt.Errorf("decoding HealthCheckResponse: %w", err)
+	}
+	status := 0
+	for _, f := range fields {
+		if f.num == 1 {
+			status = int(f.varint)
+		}
+	}
+	if status < len(healthStatuses) {
+		return healthStatuses[status], nil
+	}
+	return strconv.Itoa(status), nil
+}
+
+var (
+	partitionRoles  = []string{"leader", "follower", "inactive"}
+	partitionHealth = []string{"healthy", "unhealthy", "dead"}
+)
+
+// decodeTopology decodes a gateway_protocol.TopologyResponse.
+func decodeTopology(msg []byte) (*Topology, error) {
+	fields, err := readFields(msg)
+	if err != nil {
+		return nil, fmt.Errorf("decoding TopologyResponse: %w", err)
+	}
+	t := &Topology{}
+	for _, f := range fields {
+		switch f.num {
+		case 1:
+			b, err := decodeBroker(f.bytes)
+			if err != nil {
+				return nil, err
+			}
+			t.Brokers = append(t.Brokers, b)
+		case 2:
+			t.ClusterSize = int(f.varint)
+		case 3:
+			t.PartitionsCount = int(f.varint)
+		case 4:
+			t.ReplicationFactor = int(f.varint)
+		case 5:
+			t.GatewayVersion = string(f.bytes)
+		}
+	}
+	return t, nil
+}
+
+func decodeBroker(msg []byte) (Broker, error) {
+	fields, err := readFields(msg)
+	if err != nil {
+		return Broker{}, fmt.Errorf("decoding BrokerInfo: %w", err)
+	}
+	var b Broker
+	for _, f := range fields {
+		switch f.num {
+		case 1:
+			b.NodeID = int(f.varint)
+		case 2:
+			b.Host = string(f.bytes)
+		case 3:
+			b.Port = int(f.varint)
+		case 4:
+			p, err := decodePartition(f.bytes)
+			if err != nil {
+				return Broker{}, err
+			}
+			b.Partitions = append(b.Partitions, p)
+		case 5:
+			b.Version = string(f.bytes)
+		}
+	}
+	return b, nil
+}
+
+func decodePartition(msg []byte) (Partition, error) {
+	fields, err := readFields(msg)
+	if err != nil {
+		return Partition{}, fmt.Errorf("decoding Partition: %w", err)
+	}
+	// Enum zero values (LEADER, HEALTHY) are omitted on the wire.
+	p := Partition{Role: partitionRoles[0], Health: partitionHealth[0]}
+	for _, f := range fields {
+		switch f.num {
+		case 1:
+			p.PartitionID = int(f.varint)
+		case 2:
+			p.Role = enumName(partitionRoles, f.varint)
+		case 3:
+			p.Health = enumName(partitionHealth, f.varint)
+		}
+	}
+	return p, nil
+}
+
+func enumName(names []string, v uint64) string {
+	if v < uint64(len(names)) {
+		return names[v]
+	}
+	return strconv.FormatUint(v, 10)
+}
+
+// pbField is one decoded protobuf field: varint for wire type 0, bytes for
+// wire type 2. Fixed-width fields are skipped (none of the messages used
+// here have them).
+type pbField struct {
+	num    int
+	varint uint64
+	bytes  []byte
+}
+
+var errTruncated = errors.New("truncated protobuf message")
+
+func readFields(b []byte) ([]pbField, error) {
+	var out []pbField
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			return nil, errTruncated
+		}
+		b = b[n:]
+		f := pbField{num: int(key >> 3)}
+		switch key & 7 {
+		case 0:
+			v, n := binary.Uvarint(b)
+			if n <= 0 {
+				return nil, errTruncated
+			}
+			f.varint, b = v, b[n:]
+		case 1:
+			if len(b) < 8 {
+				return nil, errTruncated
+			}
+			b = b[8:]
+			continue
+		case 2:
+			l, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < l {
+				return nil, errTruncated
+			}
+			f.bytes, b = b[n:n+int(l)], b[n+int(l):]
+		case 5:
+			if len(b) < 4 {
+				return nil, errTruncated
+			}
+			b = b[4:]
+			continue
+		default:
+			return nil, fmt.Errorf("unsupported protobuf wire type %d", key&7)
+		}
+		out = append(out, f)
+	}
+	return out, nil
+}
+
+func appendBytesField(b []byte, num int, v []byte) []byte {
+	b = binary.AppendUvarint(b, uint64(num)<<3|2)
+	b = binary.AppendUvarint(b, uint64(len(v)))
+	return append(b, v...)
+}
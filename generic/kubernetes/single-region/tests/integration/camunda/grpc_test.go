@@ -0,0 +1,134 @@
+package camunda
+
+import (
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/config"
+	"github.com/camunda/camunda-deployment-references/tests/integration/helpers"
+)
+
+func appendVarintField(b []byte, num int, v uint64) []byte {
+	b = binary.AppendUvarint(b, uint64(num)<<3)
+	return binary.AppendUvarint(b, v)
+}
+
+// newGRPCServer serves handler over TLS with HTTP/2 and returns a
+// GRPCClient trusting it.
+func newGRPCServer(t *testing.T, cfg *config.Config, handler http.HandlerFunc) *GRPCClient {
+	t.Helper()
+	srv := httptest.NewUnstartedServer(handler)
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	caPath := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0o600))
+	cfg.TLSCACert = caPath
+	cfg.HTTPTimeout = 5 * time.Second
+
+	g, err := NewGRPC(helpers.NewClient(cfg), strings.TrimPrefix(srv.URL, "https://"))
+	require.NoError(t, err)
+	return g
+}
+
+func writeGRPC(w http.ResponseWriter, msg []byte, status, message string) {
+	w.Header().Set("Content-Type", "application/grpc")
+	w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+	frame := make([]byte, 5+len(msg))
+	binary.BigEndian.PutUint32(frame[1:5], uint32(len(msg)))
+	copy(frame[5:], msg)
+	_, _ = w.Write(frame)
+	w.Header().Set("Grpc-Status", status)
+	w.Header().Set("Grpc-Message", message)
+}
+
+func TestGRPCTopology(t *testing.T) {
+	var partition1, partition2, broker []byte
+	partition1 = appendVarintField(partition1, 1, 1) // leader, healthy: zero values omitted
+	partition2 = appendVarintField(partition2, 1, 2)
+	partition2 = appendVarintField(partition2, 2, 1) // follower
+	partition2 = appendVarintField(partition2, 3, 1) // unhealthy
+	broker = appendVarintField(broker, 1, 2)
+	broker = appendBytesField(broker, 2, []byte("camunda-zeebe-2"))
+	broker = appendVarintField(broker, 3, 26501)
+	broker = appendBytesField(broker, 4, partition1)
+	broker = appendBytesField(broker, 4, partition2)
+	broker = appendBytesField(broker, 5, []byte("8.8.0"))
+	var resp []byte
+	resp = appendBytesField(resp, 1, broker)
+	resp = appendVarintField(resp, 2, 3)
+	resp = appendVarintField(resp, 3, 2)
+	resp = appendVarintField(resp, 4, 3)
+	resp = appendBytesField(resp, 5, []byte("8.8.0"))
+
+	g := newGRPCServer(t, &config.Config{AuthMode: "bearer", BearerToken: "tok"}, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, 2, r.ProtoMajor)
+		assert.Equal(t, "/gateway_protocol.Gateway/Topology", r.URL.Path)
+		assert.Equal(t, "application/grpc", r.Header.Get("Content-Type"))
+		assert.Equal(t, "Bearer tok", r.Header.Get("Authorization"))
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, []byte{0, 0, 0, 0, 0}, body)
+		writeGRPC(w, resp, "0", "")
+	})
+
+	topo, err := g.Topology(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 3, topo.ClusterSize)
+	assert.Equal(t, 2, topo.PartitionsCount)
+	assert.Equal(t, 3, topo.ReplicationFactor)
+	assert.Equal(t, "8.8.0", topo.GatewayVersion)
+	require.Len(t, topo.Brokers, 1)
+	assert.Equal(t, Broker{
+		NodeID:  2,
+		Host:    "camunda-zeebe-2",
+		Port:    26501,
+		Version: "8.8.0",
+		Partitions: []Partition{
+			{PartitionID: 1, Role: "leader", Health: "healthy"},
+			{PartitionID: 2, Role: "follower", Health: "unhealthy"},
+		},
+	}, topo.Brokers[0])
+}
+
+func TestGRPCHealthAndStatusErrors(t *testing.T) {
+	g := newGRPCServer(t, &config.Config{AuthMode: "none"}, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		fields, err := readFields(body[5:])
+		require.NoError(t, err)
+		switch {
+		case len(fields) == 0:
+			writeGRPC(w, appendVarintField(nil, 1, 1), "0", "")
+		case string(fields[0].bytes) == "gateway_protocol.Gateway":
+			writeGRPC(w, nil, "16", "Expected bearer token")
+		default:
+			writeGRPC(w, appendVarintField(nil, 1, 3), "0", "")
+		}
+	})
+
+	status, err := g.Health(t.Context(), "")
+	require.NoError(t, err)
+	assert.Equal(t, HealthServing, status)
+
+	status, err = g.Health(t.Context(), "other")
+	require.NoError(t, err)
+	assert.Equal(t, HealthServiceUnknown, status)
+
+	_, err = g.Health(t.Context(), "gateway_protocol.Gateway")
+	var se *GRPCStatusError
+	require.True(t, errors.As(err, &se))
+	assert.Equal(t, GRPCUnauthenticated, se.Code)
+	assert.Contains(t, err.Error(), "UNAUTHENTICATED: Expected bearer token")
+}
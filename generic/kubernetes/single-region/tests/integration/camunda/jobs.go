@@ -0,0 +1,117 @@
+package camunda
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// CreateProcessInstanceRequest starts a process instance. Set either
+// ProcessDefinitionID (latest version unless Version is set) or
+// ProcessDefinitionKey.
+type CreateProcessInstanceRequest struct {
+	ProcessDefinitionID      string                 `json:"processDefinitionId,omitempty"`
+	ProcessDefinitionVersion int                    `json:"processDefinitionVersion,omitempty"`
+	ProcessDefinitionKey     string                 `json:"processDefinitionKey,omitempty"`
+	Variables                map[string]interface{} `json:"variables,omitempty"`
+	TenantID                 string                 `json:"tenantId,omitempty"`
+}
+
+// CreatedProcessInstance is the POST /v2/process-instances response.
+type CreatedProcessInstance struct {
+	ProcessInstanceKey       string `json:"processInstanceKey"`
+	ProcessDefinitionID      string `json:"processDefinitionId"`
+	ProcessDefinitionKey     string `json:"processDefinitionKey"`
+	ProcessDefinitionVersion int    `json:"processDefinitionVersion"`
+	TenantID                 string `json:"tenantId"`
+}
+
+// CreateProcessInstance starts one process instance.
+func (c *Client) CreateProcessInstance(ctx context.Context, req CreateProcessInstanceRequest) (*CreatedProcessInstance, error) {
+	var out CreatedProcessInstance
+	if err := c.call(ctx, http.MethodPost, "/v2/process-instances", req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CancelProcessInstance cancels an active process instance.
+func (c *Client) CancelProcessInstance(ctx context.Context, processInstanceKey string) error {
+	return c.call(ctx, http.MethodPost, "/v2/process-instances/"+url.PathEscape(processInstanceKey)+"/cancellation", struct{}{}, nil)
+}
+
+// ActivateJobsRequest is the POST /v2/jobs/activation body. Timeout and
+// RequestTimeout are in milliseconds, as on the wire.
+type ActivateJobsRequest struct {
+	Type              string   `json:"type"`
+	Worker            string   `json:"worker,omitempty"`
+	Timeout           int64    `json:"timeout"`
+	MaxJobsToActivate int      `json:"maxJobsToActivate"`
+	FetchVariable     []string `json:"fetchVariable,omitempty"`
+	RequestTimeout    int64    `json:"requestTimeout,omitempty"`
+	TenantIDs         []string `json:"tenantIds,omitempty"`
+}
+
+// ActivatedJob is a job handed to a worker.
+type ActivatedJob struct {
+	JobKey               string                 `json:"jobKey"`
+	Type                 string                 `json:"type"`
+	ProcessInstanceKey   string                 `json:"processInstanceKey"`
+	ProcessDefinitionID  string                 `json:"processDefinitionId"`
+	ProcessDefinitionKey string                 `json:"processDefinitionKey"`
+	ElementID            string                 `json:"elementId"`
+	ElementInstanceKey   string                 `json:"elementInstanceKey"`
+	CustomHeaders        map[string]string      `json:"customHeaders,omitempty"`
+	Worker               string                 `json:"worker"`
+	Retries              int                    `json:"retries"`
+	Deadline             int64                  `json:"deadline"`
+	Variables            map[string]interface{} `json:"variables"`
+	TenantID             string                 `json:"tenantId"`
+}
+
+// ActivateJobs long-polls for up to req.MaxJobsToActivate jobs of req.Type.
+// An empty result is not an error.
+func (c *Client) ActivateJobs(ctx context.Context, req ActivateJobsRequest) ([]ActivatedJob, error) {
+	var out struct {
+		Jobs []ActivatedJob `json:"jobs"`
+	}
+	if err := c.call(ctx, http.MethodPost, "/v2/jobs/activation", req, &out); err != nil {
+		return nil, err
+	}
+	return out.Jobs, nil
+}
+
+// CompleteJob completes an activated job, merging variables into the
+// process instance.
+func (c *Client) CompleteJob(ctx context.Context, jobKey string, variables map[string]interface{}) error {
+	body := struct {
+		Variables map[string]interface{} `json:"variables,omitempty"`
+	}{variables}
+	return c.call(ctx, http.MethodPost, "/v2/jobs/"+url.PathEscape(jobKey)+"/completion", body, nil)
+}
+
+// PublishMessageRequest is the POST /v2/messages/publication body.
+// TimeToLive is in milliseconds.
+type PublishMessageRequest struct {
+	Name           string                 `json:"name"`
+	CorrelationKey string                 `json:"correlationKey"`
+	TimeToLive     int64                  `json:"timeToLive,omitempty"`
+	MessageID      string                 `json:"messageId,omitempty"`
+	Variables      map[string]interface{} `json:"variables,omitempty"`
+	TenantID       string                 `json:"tenantId,omitempty"`
+}
+
+// PublishedMessage is the POST /v2/messages/publication response.
+type PublishedMessage struct {
+	MessageKey string `json:"messageKey"`
+	TenantID   string `json:"tenantId"`
+}
+
+// PublishMessage publishes a message for correlation.
+func (c *Client) PublishMessage(ctx context.Context, req PublishMessageRequest) (*PublishedMessage, error) {
+	var out PublishedMessage
+	if err := c.call(ctx, http.MethodPost, "/v2/messages/publication", req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
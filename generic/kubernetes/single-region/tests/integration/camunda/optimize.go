@@ -0,0 +1,84 @@
+package camunda
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/helpers"
+)
+
+// OptimizeClient calls the Optimize public API (/api/public). The API only
+// accepts JWT bearer tokens whose audience includes optimize-api, i.e. an
+// M2M token of a client with optimize-api permissions.
+type OptimizeClient struct {
+	c *Client
+}
+
+// NewOptimize returns an OptimizeClient for the Optimize instance at
+// baseURL (cfg.OptimizeURL).
+func NewOptimize(hc *helpers.Client, baseURL string) *OptimizeClient {
+	return &OptimizeClient{c: New(hc, baseURL)}
+}
+
+// ExportedEntity is one report or dashboard definition in an export. Only
+// the identifying fields are decoded; the rest of the definition is kept
+// raw so it could be re-imported.
+type ExportedEntity struct {
+	ID                 string `json:"id"`
+	Name               string `json:"name"`
+	ExportEntityType   string `json:"exportEntityType"`
+	SourceIndexVersion int    `json:"sourceIndexVersion"`
+	CollectionID       string `json:"collectionId,omitempty"`
+}
+
+// ExportReports exports the definitions of the given report IDs. An empty
+// list is valid and returns an empty export.
+func (o *OptimizeClient) ExportReports(ctx context.Context, reportIDs ...string) ([]ExportedEntity, error) {
+	return o.export(ctx, "/api/public/export/report/definition/json", reportIDs)
+}
+
+// ExportDashboards exports the definitions of the given dashboard IDs,
+// including the reports they contain.
+func (o *OptimizeClient) ExportDashboards(ctx context.Context, dashboardIDs ...string) ([]ExportedEntity, error) {
+	return o.export(ctx, "/api/public/export/dashboard/definition/json", dashboardIDs)
+}
+
+func (o *OptimizeClient) export(ctx context.Context, path string, ids []string) ([]ExportedEntity, error) {
+	if ids == nil {
+		ids = []string{}
+	}
+	var out []ExportedEntity
+	if err := o.c.call(ctx, http.MethodPost, path, ids, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// VariableLabel is a display label for a process variable.
+type VariableLabel struct {
+	VariableName  string `json:"variableName"`
+	VariableType  string `json:"variableType"`
+	VariableLabel string `json:"variableLabel"`
+}
+
+// ErrNotImported means Optimize has not imported the process definition
+// yet; its importer catches up with secondary storage periodically.
+var ErrNotImported = errors.New("process definition not imported by Optimize")
+
+// LabelVariables sets variable labels on the process definition with the
+// given BPMN process ID. Optimize only accepts labels for definitions it
+// has imported, so this doubles as an import check: it returns
+// ErrNotImported (wrapping the 404) until the import has happened.
+func (o *OptimizeClient) LabelVariables(ctx context.Context, processDefinitionID string, labels ...VariableLabel) error {
+	body := struct {
+		DefinitionKey string          `json:"definitionKey"`
+		Labels        []VariableLabel `json:"labels"`
+	}{processDefinitionID, labels}
+	err := o.c.call(ctx, http.MethodPost, "/api/public/variables/labels", body, nil)
+	var se *helpers.StatusError
+	if errors.As(err, &se) && se.StatusCode == http.StatusNotFound {
+		return errors.Join(ErrNotImported, err)
+	}
+	return err
+}
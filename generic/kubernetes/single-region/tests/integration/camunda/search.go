@@ -0,0 +1,216 @@
+package camunda
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Page selects a slice of search results. Use either From (offset) or After
+// (the EndCursor of the previous page); Limit defaults to the server's
+// page size.
+type Page struct {
+	Limit int    `json:"limit,omitempty"`
+	From  int    `json:"from,omitempty"`
+	After string `json:"after,omitempty"`
+}
+
+// SearchPage is the paging metadata of a search response.
+type SearchPage struct {
+	TotalItems        int64  `json:"totalItems"`
+	StartCursor       string `json:"startCursor,omitempty"`
+	EndCursor         string `json:"endCursor,omitempty"`
+	HasMoreTotalItems bool   `json:"hasMoreTotalItems,omitempty"`
+}
+
+// SearchResult is one page of a /v2/*/search response.
+type SearchResult[T any] struct {
+	Items []T        `json:"items"`
+	Page  SearchPage `json:"page"`
+}
+
+type searchRequest struct {
+	Filter interface{} `json:"filter"`
+	Page   *Page       `json:"page,omitempty"`
+}
+
+func search[T any](ctx context.Context, c *Client, path string, filter interface{}, page Page) (*SearchResult[T], error) {
+	req := searchRequest{Filter: filter}
+	if page != (Page{}) {
+		req.Page = &page
+	}
+	var r SearchResult[T]
+	if err := c.call(ctx, http.MethodPost, path, req, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// maxCollectPages guards Collect against a server that keeps returning the
+// same cursor.
+const maxCollectPages = 1000
+
+// Collect pages through a search with the given page size, following
+// EndCursor until a short page, an empty cursor or TotalItems is reached:
+//
+//	all, err := camunda.Collect(ctx, 100, func(ctx context.Context, p camunda.Page) (*camunda.SearchResult[camunda.ProcessInstance], error) {
+//		return api.SearchProcessInstances(ctx, filter, p)
+//	})
+func Collect[T any](ctx context.Context, limit int, fetch func(context.Context, Page) (*SearchResult[T], error)) ([]T, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	var all []T
+	page := Page{Limit: limit}
+	for i := 0; i < maxCollectPages; i++ {
+		r, err := fetch(ctx, page)
+		if err != nil {
+			return all, err
+		}
+		all = append(all, r.Items...)
+		if len(r.Items) < limit || r.Page.EndCursor == "" || (r.Page.TotalItems > 0 && int64(len(all)) >= r.Page.TotalItems) {
+			return all, nil
+		}
+		page.After = r.Page.EndCursor
+	}
+	return all, fmt.Errorf("search did not finish after %d pages", maxCollectPages)
+}
+
+// ProcessDefinitionFilter narrows a process definition search. Zero fields
+// are omitted.
+type ProcessDefinitionFilter struct {
+	ProcessDefinitionID  string `json:"processDefinitionId,omitempty"`
+	ProcessDefinitionKey string `json:"processDefinitionKey,omitempty"`
+	Name                 string `json:"name,omitempty"`
+	Version              int    `json:"version,omitempty"`
+	IsLatestVersion      *bool  `json:"isLatestVersion,omitempty"`
+	TenantID             string `json:"tenantId,omitempty"`
+}
+
+// ProcessDefinition is a deployed process definition.
+type ProcessDefinition struct {
+	ProcessDefinitionID  string `json:"processDefinitionId"`
+	ProcessDefinitionKey string `json:"processDefinitionKey"`
+	Name                 string `json:"name"`
+	Version              int    `json:"version"`
+	VersionTag           string `json:"versionTag,omitempty"`
+	ResourceName         string `json:"resourceName"`
+	TenantID             string `json:"tenantId"`
+}
+
+// SearchProcessDefinitions returns one page of matching process definitions.
+func (c *Client) SearchProcessDefinitions(ctx context.Context, filter ProcessDefinitionFilter, page Page) (*SearchResult[ProcessDefinition], error) {
+	return search[ProcessDefinition](ctx, c, "/v2/process-definitions/search", filter, page)
+}
+
+// Process instance states.
+const (
+	StateActive     = "ACTIVE"
+	StateCompleted  = "COMPLETED"
+	StateTerminated = "TERMINATED"
+)
+
+// ProcessInstanceFilter narrows a process instance search. Zero fields are
+// omitted.
+type ProcessInstanceFilter struct {
+	ProcessInstanceKey   string `json:"processInstanceKey,omitempty"`
+	ProcessDefinitionID  string `json:"processDefinitionId,omitempty"`
+	ProcessDefinitionKey string `json:"processDefinitionKey,omitempty"`
+	State                string `json:"state,omitempty"`
+	HasIncident          *bool  `json:"hasIncident,omitempty"`
+	TenantID             string `json:"tenantId,omitempty"`
+}
+
+// ProcessInstance is a process instance as exported to secondary storage.
+type ProcessInstance struct {
+	ProcessInstanceKey       string `json:"processInstanceKey"`
+	ProcessDefinitionID      string `json:"processDefinitionId"`
+	ProcessDefinitionKey     string `json:"processDefinitionKey"`
+	ProcessDefinitionName    string `json:"processDefinitionName,omitempty"`
+	ProcessDefinitionVersion int    `json:"processDefinitionVersion"`
+	State                    string `json:"state"`
+	StartDate                Time   `json:"startDate"`
+	EndDate                  Time   `json:"endDate"`
+	HasIncident              bool   `json:"hasIncident"`
+	ParentProcessInstanceKey string `json:"parentProcessInstanceKey,omitempty"`
+	TenantID                 string `json:"tenantId"`
+}
+
+// SearchProcessInstances returns one page of matching process instances.
+func (c *Client) SearchProcessInstances(ctx context.Context, filter ProcessInstanceFilter, page Page) (*SearchResult[ProcessInstance], error) {
+	return search[ProcessInstance](ctx, c, "/v2/process-instances/search", filter, page)
+}
+
+// GetProcessInstance returns the process instance with the given key from
+// secondary storage.
+func (c *Client) GetProcessInstance(ctx context.Context, processInstanceKey string) (*ProcessInstance, error) {
+	var out ProcessInstance
+	if err := c.call(ctx, http.MethodGet, "/v2/process-instances/"+url.PathEscape(processInstanceKey), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UserTaskFilter narrows a user task search. Zero fields are omitted.
+type UserTaskFilter struct {
+	UserTaskKey         string `json:"userTaskKey,omitempty"`
+	ProcessInstanceKey  string `json:"processInstanceKey,omitempty"`
+	ProcessDefinitionID string `json:"processDefinitionId,omitempty"`
+	ElementID           string `json:"elementId,omitempty"`
+	State               string `json:"state,omitempty"`
+	Assignee            string `json:"assignee,omitempty"`
+	TenantID            string `json:"tenantId,omitempty"`
+}
+
+// UserTask is a user task as exported to secondary storage.
+type UserTask struct {
+	UserTaskKey          string   `json:"userTaskKey"`
+	Name                 string   `json:"name"`
+	State                string   `json:"state"`
+	Assignee             string   `json:"assignee,omitempty"`
+	ElementID            string   `json:"elementId"`
+	CandidateGroups      []string `json:"candidateGroups,omitempty"`
+	CandidateUsers       []string `json:"candidateUsers,omitempty"`
+	ProcessInstanceKey   string   `json:"processInstanceKey"`
+	ProcessDefinitionID  string   `json:"processDefinitionId"`
+	ProcessDefinitionKey string   `json:"processDefinitionKey"`
+	CreationDate         Time     `json:"creationDate"`
+	CompletionDate       Time     `json:"completionDate"`
+	TenantID             string   `json:"tenantId"`
+}
+
+// SearchUserTasks returns one page of matching user tasks.
+func (c *Client) SearchUserTasks(ctx context.Context, filter UserTaskFilter, page Page) (*SearchResult[UserTask], error) {
+	return search[UserTask](ctx, c, "/v2/user-tasks/search", filter, page)
+}
+
+// IncidentFilter narrows an incident search. Zero fields are omitted.
+type IncidentFilter struct {
+	ProcessInstanceKey  string `json:"processInstanceKey,omitempty"`
+	ProcessDefinitionID string `json:"processDefinitionId,omitempty"`
+	ErrorType           string `json:"errorType,omitempty"`
+	State               string `json:"state,omitempty"`
+	TenantID            string `json:"tenantId,omitempty"`
+}
+
+// Incident is an incident as exported to secondary storage.
+type Incident struct {
+	IncidentKey          string `json:"incidentKey"`
+	ProcessInstanceKey   string `json:"processInstanceKey"`
+	ProcessDefinitionID  string `json:"processDefinitionId"`
+	ProcessDefinitionKey string `json:"processDefinitionKey"`
+	ErrorType            string `json:"errorType"`
+	ErrorMessage         string `json:"errorMessage"`
+	ElementID            string `json:"elementId"`
+	ElementInstanceKey   string `json:"elementInstanceKey"`
+	JobKey               string `json:"jobKey,omitempty"`
+	State                string `json:"state"`
+	CreationTime         Time   `json:"creationTime"`
+	TenantID             string `json:"tenantId"`
+}
+
+// SearchIncidents returns one page of matching incidents.
+func (c *Client) SearchIncidents(ctx context.Context, filter IncidentFilter, page Page) (*SearchResult[Incident], error) {
+	return search[Incident](ctx, c, "/v2/incidents/search", filter, page)
+}
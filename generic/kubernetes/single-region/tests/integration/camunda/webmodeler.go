@@ -0,0 +1,126 @@
+package camunda
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/helpers"
+)
+
+// WebModelerClient calls the Web Modeler public REST API (/api/v1). Tokens
+// need the web-modeler-public-api audience with create/read/delete
+// permissions.
+type WebModelerClient struct {
+	c *Client
+}
+
+// NewWebModeler returns a WebModelerClient for the Web Modeler instance at
+// baseURL (cfg.WebModelerURL).
+func NewWebModeler(hc *helpers.Client, baseURL string) *WebModelerClient {
+	return &WebModelerClient{c: New(hc, baseURL)}
+}
+
+// WebModelerInfo is the GET /api/v1/info response.
+type WebModelerInfo struct {
+	Version string `json:"version"`
+}
+
+// Info returns the Web Modeler version.
+func (w *WebModelerClient) Info(ctx context.Context) (*WebModelerInfo, error) {
+	var out WebModelerInfo
+	if err := w.c.call(ctx, http.MethodGet, "/api/v1/info", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Project is a Web Modeler project.
+type Project struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	CreatedBy string `json:"createdBy,omitempty"`
+	Created   Time   `json:"created"`
+}
+
+// CreateProject creates a project named name.
+func (w *WebModelerClient) CreateProject(ctx context.Context, name string) (*Project, error) {
+	body := struct {
+		Name string `json:"name"`
+	}{name}
+	var out Project
+	if err := w.c.call(ctx, http.MethodPost, "/api/v1/projects", body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetProject returns the project with the given ID.
+func (w *WebModelerClient) GetProject(ctx context.Context, projectID string) (*Project, error) {
+	var out Project
+	if err := w.c.call(ctx, http.MethodGet, "/api/v1/projects/"+url.PathEscape(projectID), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteProject deletes an empty project.
+func (w *WebModelerClient) DeleteProject(ctx context.Context, projectID string) error {
+	return w.c.call(ctx, http.MethodDelete, "/api/v1/projects/"+url.PathEscape(projectID), nil, nil)
+}
+
+// File types accepted by CreateFile.
+const (
+	FileTypeBPMN = "bpmn"
+	FileTypeDMN  = "dmn"
+	FileTypeForm = "form"
+)
+
+// NewFile is the POST /api/v1/files body.
+type NewFile struct {
+	Name      string `json:"name"`
+	ProjectID string `json:"projectId"`
+	FolderID  string `json:"folderId,omitempty"`
+	Content   string `json:"content"`
+	FileType  string `json:"fileType"`
+}
+
+// FileMetadata describes a stored Web Modeler file.
+type FileMetadata struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	ProjectID  string `json:"projectId"`
+	FolderID   string `json:"folderId,omitempty"`
+	Type       string `json:"type"`
+	Revision   int    `json:"revision"`
+	SimplePath string `json:"simplePath"`
+}
+
+// File is a Web Modeler file with its content.
+type File struct {
+	Metadata FileMetadata `json:"metadata"`
+	Content  string       `json:"content"`
+}
+
+// CreateFile stores a new file.
+func (w *WebModelerClient) CreateFile(ctx context.Context, f NewFile) (*File, error) {
+	var out File
+	if err := w.c.call(ctx, http.MethodPost, "/api/v1/files", f, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetFile returns the file with the given ID.
+func (w *WebModelerClient) GetFile(ctx context.Context, fileID string) (*File, error) {
+	var out File
+	if err := w.c.call(ctx, http.MethodGet, "/api/v1/files/"+url.PathEscape(fileID), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteFile deletes the file with the given ID.
+func (w *WebModelerClient) DeleteFile(ctx context.Context, fileID string) error {
+	return w.c.call(ctx, http.MethodDelete, "/api/v1/files/"+url.PathEscape(fileID), nil, nil)
+}
@@ -0,0 +1,86 @@
+// Command soak runs sustained load against a reference deployment before
+// sign-off (see package soak). It takes the same configuration as the
+// integration suites, with TEST_SOAK_* setting rate, duration, concurrency
+// and thresholds:
+//
+//	go run ./cmd/soak -profile kind.yaml -set TEST_SOAK_DURATION=30m
+//
+// The summary is printed and written as JSON to -summary (default
+// soak-summary.json in TEST_REPORT_DIR, or the working directory). The exit
+// status is 1 when a threshold is exceeded. Interrupting the run stops
+// starting instances and still writes the summary of what ran.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/camunda"
+	"github.com/camunda/camunda-deployment-references/tests/integration/config"
+	"github.com/camunda/camunda-deployment-references/tests/integration/helpers"
+	"github.com/camunda/camunda-deployment-references/tests/integration/soak"
+)
+
+func main() {
+	os.Exit(run())
+}
+
+func run() int {
+	opts := config.BindFlags(flag.CommandLine)
+	summaryPath := flag.String("summary", "", "path of the JSON summary (default soak-summary.json in TEST_REPORT_DIR or the working directory)")
+	flag.Parse()
+
+	cfg, err := config.Load(*opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
+		return 1
+	}
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	if *summaryPath == "" {
+		*summaryPath = filepath.Join(cfg.ReportDir, "soak-summary.json")
+	}
+	if cfg.PortForward {
+		tunnels, err := helpers.StartPortForwards(cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to set up port-forwards: %v\n", err)
+			return 1
+		}
+		defer tunnels.Close()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	api := camunda.New(helpers.NewClient(cfg), cfg.ZeebeGatewayURL)
+	runOpts := soak.OptionsFromConfig(cfg)
+	fmt.Fprintf(os.Stderr, "soak: starting %g instances/s for %s against %s (concurrency %d)\n",
+		runOpts.Rate, runOpts.Duration, cfg.ZeebeGatewayURL, runOpts.Concurrency)
+	summary, err := soak.Run(ctx, api, runOpts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	checkErr := summary.Check(cfg.SoakMaxErrorRate, cfg.SoakMaxBacklog)
+	fmt.Print(summary)
+	code := 0
+	if err := summary.WriteJSON(*summaryPath); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		code = 1
+	} else {
+		fmt.Fprintf(os.Stderr, "soak: summary written to %s\n", *summaryPath)
+	}
+	if checkErr != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", checkErr)
+		code = 1
+	}
+	return code
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+// AuthCheck validates the settings one auth mode depends on.
+type AuthCheck func(c *Config) []Problem
+
+// authModes maps every accepted TEST_AUTH_MODE to its validation. The
+// matching request-side implementations live in helpers (see
+// helpers.RegisterAuthenticator).
+var authModes = map[string]AuthCheck{
+	"none":  nil,
+	"basic": checkBasic,
+	// oidc is the OAuth2 client_credentials grant with client_secret_post.
+	"oidc":              checkOIDC(true),
+	"client-secret-jwt": checkOIDC(true),
+	"private-key-jwt":   checkPrivateKeyJWT,
+	"mtls":              checkMTLS,
+	"bearer":            checkBearer,
+}
+
+// RegisterAuthMode makes name an accepted TEST_AUTH_MODE. check may be nil
+// when the mode needs no settings beyond what its authenticator verifies.
+func RegisterAuthMode(name string, check AuthCheck) {
+	authModes[name] = check
+}
+
+// AuthModes returns the accepted auth mode names, sorted.
+func AuthModes() []string {
+	names := make([]string, 0, len(authModes))
+	for n := range authModes {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Authenticated reports whether requests carry credentials at all.
+func (c *Config) Authenticated() bool {
+	return c.AuthMode != "none"
+}
+
+// UsesOIDC reports whether the auth mode obtains tokens from the OIDC token
+// endpoint (KeycloakTokenURL).
+func (c *Config) UsesOIDC() bool {
+	switch c.AuthMode {
+	case "oidc", "client-secret-jwt", "private-key-jwt":
+		return true
+	}
+	return false
+}
+
+func required(key, mode string) Problem {
+	return Problem{Key: key, Message: fmt.Sprintf("required when TEST_AUTH_MODE=%s", mode)}
+}
+
+func checkBasic(c *Config) []Problem {
+	var p []Problem
+	if c.BasicUser == "" {
+		p = append(p, required("TEST_BASIC_USER", c.AuthMode))
+	}
+	if c.BasicPass == "" {
+		p = append(p, required("TEST_BASIC_PASSWORD", c.AuthMode))
+	}
+	return p
+}
+
+func checkOIDC(needSecret bool) AuthCheck {
+	return func(c *Config) []Problem {
+		var p []Problem
+		if c.OIDCClientID == "" {
+			p = append(p, required("TEST_OIDC_CLIENT_ID", c.AuthMode))
+		}
+		if needSecret && c.OIDCSecret == "" {
+			p = append(p, required("TEST_OIDC_CLIENT_SECRET", c.AuthMode))
+		}
+		tokenKey := "TEST_KEYCLOAK_URL"
+		if c.OIDCTokenURL != "" {
+			tokenKey = "TEST_OIDC_TOKEN_URL"
+		}
+		if msg := checkURL(c.KeycloakTokenURL()); msg != "" {
+			p = append(p, Problem{Key: tokenKey, Message: "token endpoint " + msg})
+		}
+		return p
+	}
+}
+
+func checkPrivateKeyJWT(c *Config) []Problem {
+	p := checkOIDC(false)(c)
+	if c.OIDCAssertionKey == "" {
+		p = append(p, required("TEST_OIDC_ASSERTION_KEY", c.AuthMode))
+	}
+	return p
+}
+
+func checkMTLS(c *Config) []Problem {
+	var p []Problem
+	if c.TLSClientCert == "" {
+		p = append(p, required("TEST_TLS_CLIENT_CERT", c.AuthMode))
+	}
+	if c.TLSClientKey == "" {
+		p = append(p, required("TEST_TLS_CLIENT_KEY", c.AuthMode))
+	}
+	return p
+}
+
+func checkBearer(c *Config) []Problem {
+	if c.BearerToken == "" {
+		return []Problem{required("TEST_BEARER_TOKEN", c.AuthMode)}
+	}
+	return nil
+}
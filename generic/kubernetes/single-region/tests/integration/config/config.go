@@ -1,16 +1,16 @@
 // Package config provides configuration for Camunda integration tests.
-// Configuration is loaded from environment variables.
+// Configuration is loaded from an optional profile file, environment
+// variables and explicit overrides, in that order of precedence (see Load).
 package config
 
 import (
 	"fmt"
-	"os"
-	"strconv"
-	"strings"
 	"time"
 )
 
-// Config holds all test configuration loaded from environment variables.
+// Config holds all test configuration. Every field is keyed by the
+// environment variable that sets it; the same keys are used in profile files
+// and -set overrides.
 type Config struct {
 	// Cluster access
 	Namespace   string
@@ -21,18 +21,44 @@ type Config struct {
 	Domain     string // empty = no-domain mode (use port-forward)
 	DomainGRPC string
 
-	// Auth
-	AuthMode     string // "oidc", "basic", "none"
+	// Auth. AuthMode names the authenticator used by helpers.Client; the
+	// built-in modes are listed in authModes (see auth.go).
+	AuthMode     string
 	BasicUser    string
 	BasicPass    string
 	OIDCTokenURL string
 	OIDCClientID string
 	OIDCSecret   string
+	// Optional token request parameters for external IdPs (Entra ID, Okta).
+	OIDCAudience string
+	OIDCScope    string
+	// PEM private key (RSA or EC) signing private_key_jwt client assertions,
+	// and the optional "kid" header the IdP uses to pick the public key.
+	OIDCAssertionKey   string
+	OIDCAssertionKeyID string
+	// Static bearer token for TEST_AUTH_MODE=bearer.
+	BearerToken string
+	// Client certificate (PEM files) presented on every TLS connection, and
+	// an optional CA bundle to trust. Required for TEST_AUTH_MODE=mtls but
+	// honoured in every mode, e.g. OIDC behind an mTLS ingress.
+	TLSClientCert string
+	TLSClientKey  string
+	TLSCACert     string
+	// OIDCM2MClients is a JSON object mapping client_id to client_secret for
+	// the per-component M2M token test.
+	OIDCM2MClients string
 
-	// Component toggles
+	// Component toggles. ElasticsearchEnabled is kept for existing callers
+	// and is true exactly when SecondaryStorage is "elasticsearch".
 	ElasticsearchEnabled bool
 	HubEnabled           bool
 	OptimizeEnabled      bool
+	// PublicAPIEnabled runs the Optimize and Web Modeler public API tests.
+	// They need an M2M client whose tokens carry the optimize-api and
+	// web-modeler-public-api audiences, which the suite's client usually
+	// does not have, so they are opt-in; once enabled, a rejected token
+	// fails them.
+	PublicAPIEnabled bool
 
 	// Service addresses (populated from env or derived)
 	ZeebeGatewayURL  string // e.g. http://localhost:8080
@@ -42,84 +68,266 @@ type Config struct {
 	// the cluster is deployed with security enabled, e.g. ECK operator).
 	ElasticsearchUser     string
 	ElasticsearchPassword string
-	OrchestrationURL      string // internal orchestration URL
-	ConnectorsURL         string
-	IdentityURL           string
-	OptimizeURL           string
-	WebModelerURL         string
+	// SecondaryStorage selects the backend preflight checks: elasticsearch,
+	// opensearch, rdbms or none. It defaults to elasticsearch or none
+	// following ELASTICSEARCH_ENABLED.
+	SecondaryStorage string
+	// IndexPrefix is the Camunda index prefix on Elasticsearch/OpenSearch
+	// (empty for the chart default).
+	IndexPrefix string
+	// OpenSearch endpoint and optional basic auth. Setting
+	// OpenSearchAWSRegion signs requests with AWS SigV4 instead, using the
+	// standard AWS_ACCESS_KEY_ID / AWS_SECRET_ACCESS_KEY / AWS_SESSION_TOKEN
+	// variables; OpenSearchAWSService is "es" for managed domains or "aoss"
+	// for OpenSearch Serverless.
+	OpenSearchURL        string
+	OpenSearchUser       string
+	OpenSearchPassword   string
+	OpenSearchAWSRegion  string
+	OpenSearchAWSService string
+	// RDBMSURL is a PostgreSQL/Aurora connection URL
+	// (postgres://host:5432/camunda?sslmode=require). RDBMSUser and
+	// RDBMSPassword, when set, replace the URL's user info so the password
+	// can come from a secret. RDBMSTablePrefix matches the Camunda
+	// rdbms table-prefix setting.
+	RDBMSURL         string
+	RDBMSUser        string
+	RDBMSPassword    string
+	RDBMSTablePrefix string
+	OrchestrationURL string // internal orchestration URL
+	ConnectorsURL    string
+	IdentityURL      string
+	OptimizeURL      string
+	WebModelerURL    string
+
+	// Port-forwarding: when enabled, TestMain tunnels the in-cluster services
+	// with kubectl and rewrites the URLs above (see helpers.StartPortForwards).
+	// KubeContext selects the kubectl context; empty uses the current one.
+	PortForward bool
+	KubeContext string
 
 	// Timeouts
 	HTTPTimeout   time.Duration
 	RetryAttempts int
 	RetryDelay    time.Duration
+
+	// End-to-end process test: instances started per run and the maximum
+	// time from start until every instance is COMPLETED in search.
+	E2EInstances  int
+	E2EMaxLatency time.Duration
+
+	// Connector webhook test: the in-process mock target listens on
+	// MockListenAddr and is called by the connectors runtime at
+	// MockAdvertiseURL, the address under which the cluster reaches the
+	// runner (on kind, e.g. http://172.18.0.1:18090). The test is skipped
+	// when MockAdvertiseURL is empty.
+	MockListenAddr   string
+	MockAdvertiseURL string
+
+	// Soak run (cmd/soak): process instances started per second for
+	// SoakDuration, at most SoakConcurrency requests in flight and as many
+	// job workers. The run fails when more than SoakMaxErrorRate (0..1) of
+	// the operations fail or more than SoakMaxBacklog instances are started
+	// but not yet completed at any point.
+	SoakRate         float64
+	SoakDuration     time.Duration
+	SoakConcurrency  int
+	SoakMaxErrorRate float64
+	SoakMaxBacklog   int
+
+	// Backup/restore test: destructive, so it only runs with BackupEnabled.
+	// BackupRepository is the snapshot repository registered on the
+	// secondary storage; BackupTimeout bounds the Zeebe backup, snapshot and
+	// restore each.
+	BackupEnabled    bool
+	BackupRepository string
+	BackupTimeout    time.Duration
+
+	// Upgrade harness: installs UpgradeFromVersion of UpgradeFromChart with
+	// UpgradeFromValues, seeds data, upgrades the release to
+	// UpgradeToVersion of UpgradeToChart with UpgradeValues and re-runs the
+	// core suite. Charts are names in UpgradeRepoURL, oci:// references or
+	// local chart directories. The harness is skipped when
+	// UpgradeFromVersion is empty. UpgradeTimeout bounds each Helm action.
+	// The harness uninstalls the release it installed unless
+	// UpgradeKeepRelease is set.
+	UpgradeRepoURL     string
+	UpgradeFromChart   string
+	UpgradeFromVersion string
+	UpgradeFromValues  []string
+	UpgradeToChart     string
+	UpgradeToVersion   string
+	UpgradeValues      []string
+	UpgradeTimeout     time.Duration
+	UpgradeKeepRelease bool
+
+	// Tenants run the deployment, search and instance tests once per tenant
+	// ID. Listed tenants that do not exist are created, with the suite's M2M
+	// client (or basic-auth user) assigned, and deleted again after the run.
+	// "<default>" names the default tenant, which is never created or
+	// deleted. Empty runs those tests once without a tenant.
+	Tenants []string
+	// TenantIsolationClientID/Secret name an OIDC client the tenant
+	// isolation test assigns to the second tenant only, to check that it
+	// cannot see the first. Basic auth creates a user instead, so these are
+	// only needed in the OIDC modes.
+	TenantIsolationClientID     string
+	TenantIsolationClientSecret string
+
+	// ReportDir receives the JSON and JUnit reports TestMain writes after a
+	// run (see helpers.Reporter); empty disables them.
+	ReportDir string
+
+	// sources records which layer supplied each key (see Source), and
+	// values the raw value it supplied (see Fingerprint).
+	sources map[string]Source
+	values  map[string]string
+	// problems holds values that failed to parse while loading; they are
+	// surfaced by Validate together with the cross-field checks.
+	problems []Problem
 }
 
-// FromEnv creates a Config by reading environment variables.
+// FromEnv creates a Config from environment variables (plus the profile
+// named by TEST_PROFILE, if set). It is equivalent to Load(Options{}).
 func FromEnv() (*Config, error) {
-	c := &Config{
-		Namespace:   envOr("TEST_NAMESPACE", "camunda"),
-		ReleaseName: envOr("TEST_RELEASE_NAME", "camunda"),
-		ClusterType: envOr("TEST_CLUSTER_TYPE", "kubernetes"),
-
-		Domain:     envOr("CAMUNDA_DOMAIN", ""),
-		DomainGRPC: envOr("CAMUNDA_DOMAIN_GRPC", ""),
-
-		AuthMode:     envOr("TEST_AUTH_MODE", "oidc"),
-		BasicUser:    envOr("TEST_BASIC_USER", ""),
-		BasicPass:    envOr("TEST_BASIC_PASSWORD", ""),
-		OIDCTokenURL: envOr("TEST_OIDC_TOKEN_URL", ""),
-		OIDCClientID: envOr("TEST_OIDC_CLIENT_ID", ""),
-		OIDCSecret:   envOr("TEST_OIDC_CLIENT_SECRET", ""),
-
-		ElasticsearchEnabled: envBool("ELASTICSEARCH_ENABLED", true),
-		HubEnabled:           envBool("HUB_ENABLED", false),
-		OptimizeEnabled:      envBool("OPTIMIZE_ENABLED", true),
-
-		ElasticsearchUser:     envOr("TEST_ELASTICSEARCH_USER", ""),
-		ElasticsearchPassword: envOr("TEST_ELASTICSEARCH_PASSWORD", ""),
-
-		HTTPTimeout:   envDuration("TEST_HTTP_TIMEOUT", 30*time.Second),
-		RetryAttempts: envInt("TEST_RETRY_ATTEMPTS", 3),
-		RetryDelay:    envDuration("TEST_RETRY_DELAY", 10*time.Second),
+	return Load(Options{})
+}
+
+// FromFile creates a Config from the profile at path, with environment
+// variables still taking precedence over the file.
+func FromFile(path string) (*Config, error) {
+	return Load(Options{ProfilePath: path})
+}
+
+func (c *Config) populate(l *loader) {
+	c.Namespace = l.str("TEST_NAMESPACE", "camunda")
+	c.ReleaseName = l.str("TEST_RELEASE_NAME", "camunda")
+	c.ClusterType = l.str("TEST_CLUSTER_TYPE", "kubernetes")
+
+	c.Domain = l.str("CAMUNDA_DOMAIN", "")
+	c.DomainGRPC = l.str("CAMUNDA_DOMAIN_GRPC", "")
+
+	c.AuthMode = l.str("TEST_AUTH_MODE", "oidc")
+	c.BasicUser = l.str("TEST_BASIC_USER", "")
+	c.BasicPass = l.str("TEST_BASIC_PASSWORD", "")
+	c.OIDCTokenURL = l.str("TEST_OIDC_TOKEN_URL", "")
+	c.OIDCClientID = l.str("TEST_OIDC_CLIENT_ID", "")
+	c.OIDCSecret = l.str("TEST_OIDC_CLIENT_SECRET", "")
+	c.OIDCAudience = l.str("TEST_OIDC_AUDIENCE", "")
+	c.OIDCScope = l.str("TEST_OIDC_SCOPE", "")
+	c.OIDCAssertionKey = l.str("TEST_OIDC_ASSERTION_KEY", "")
+	c.OIDCAssertionKeyID = l.str("TEST_OIDC_ASSERTION_KEY_ID", "")
+	c.BearerToken = l.str("TEST_BEARER_TOKEN", "")
+	c.TLSClientCert = l.str("TEST_TLS_CLIENT_CERT", "")
+	c.TLSClientKey = l.str("TEST_TLS_CLIENT_KEY", "")
+	c.TLSCACert = l.str("TEST_TLS_CA_CERT", "")
+	c.OIDCM2MClients = l.str("TEST_OIDC_M2M_CLIENTS", "")
+
+	c.ElasticsearchEnabled = l.bool("ELASTICSEARCH_ENABLED", true)
+	defaultStorage := "none"
+	if c.ElasticsearchEnabled {
+		defaultStorage = "elasticsearch"
+	}
+	c.SecondaryStorage = l.str("TEST_SECONDARY_STORAGE", defaultStorage)
+	c.ElasticsearchEnabled = c.SecondaryStorage == "elasticsearch"
+	c.IndexPrefix = l.str("TEST_INDEX_PREFIX", "")
+	c.HubEnabled = l.bool("HUB_ENABLED", false)
+	c.OptimizeEnabled = l.bool("OPTIMIZE_ENABLED", true)
+	c.PublicAPIEnabled = l.bool("TEST_PUBLIC_API_ENABLED", false)
+
+	c.ElasticsearchUser = l.str("TEST_ELASTICSEARCH_USER", "")
+	c.ElasticsearchPassword = l.str("TEST_ELASTICSEARCH_PASSWORD", "")
+	c.OpenSearchURL = l.str("TEST_OPENSEARCH_URL", "")
+	c.OpenSearchUser = l.str("TEST_OPENSEARCH_USER", "")
+	c.OpenSearchPassword = l.str("TEST_OPENSEARCH_PASSWORD", "")
+	c.OpenSearchAWSRegion = l.str("TEST_OPENSEARCH_AWS_REGION", "")
+	c.OpenSearchAWSService = l.str("TEST_OPENSEARCH_AWS_SERVICE", "es")
+	c.RDBMSURL = l.str("TEST_RDBMS_URL", "")
+	c.RDBMSUser = l.str("TEST_RDBMS_USER", "")
+	c.RDBMSPassword = l.str("TEST_RDBMS_PASSWORD", "")
+	c.RDBMSTablePrefix = l.str("TEST_RDBMS_TABLE_PREFIX", "")
+
+	c.PortForward = l.bool("TEST_PORT_FORWARD", false)
+	c.KubeContext = l.str("TEST_KUBE_CONTEXT", "")
+
+	c.HTTPTimeout = l.duration("TEST_HTTP_TIMEOUT", 30*time.Second)
+	c.RetryAttempts = l.int("TEST_RETRY_ATTEMPTS", 3)
+	c.RetryDelay = l.duration("TEST_RETRY_DELAY", 10*time.Second)
+
+	c.E2EInstances = l.int("TEST_E2E_INSTANCES", 5)
+	c.E2EMaxLatency = l.duration("TEST_E2E_MAX_LATENCY", 2*time.Minute)
+	c.MockListenAddr = l.str("TEST_MOCK_LISTEN_ADDR", ":18090")
+	c.MockAdvertiseURL = l.str("TEST_MOCK_ADVERTISE_URL", "")
+	c.SoakRate = l.float("TEST_SOAK_RATE", 10)
+	c.SoakDuration = l.duration("TEST_SOAK_DURATION", 10*time.Minute)
+	c.SoakConcurrency = l.int("TEST_SOAK_CONCURRENCY", 8)
+	c.SoakMaxErrorRate = l.float("TEST_SOAK_MAX_ERROR_RATE", 0.01)
+	c.SoakMaxBacklog = l.int("TEST_SOAK_MAX_BACKLOG", 500)
+	c.BackupEnabled = l.bool("TEST_BACKUP_ENABLED", false)
+	c.BackupRepository = l.str("TEST_BACKUP_REPOSITORY", "camunda_backup")
+	c.BackupTimeout = l.duration("TEST_BACKUP_TIMEOUT", 10*time.Minute)
+	c.UpgradeRepoURL = l.str("TEST_UPGRADE_REPO_URL", "https://helm.camunda.io")
+	c.UpgradeFromChart = l.str("TEST_UPGRADE_FROM_CHART", "camunda-platform")
+	c.UpgradeFromVersion = l.str("TEST_UPGRADE_FROM_VERSION", "")
+	c.UpgradeToChart = l.str("TEST_UPGRADE_TO_CHART", "camunda-platform")
+	c.UpgradeToVersion = l.str("TEST_UPGRADE_TO_VERSION", "")
+	c.UpgradeValues = l.list("TEST_UPGRADE_VALUES")
+	c.UpgradeFromValues = l.list("TEST_UPGRADE_FROM_VALUES")
+	if len(c.UpgradeFromValues) == 0 {
+		c.UpgradeFromValues = c.UpgradeValues
 	}
+	c.UpgradeTimeout = l.duration("TEST_UPGRADE_TIMEOUT", 20*time.Minute)
+	c.UpgradeKeepRelease = l.bool("TEST_UPGRADE_KEEP_RELEASE", false)
+	c.ReportDir = l.str("TEST_REPORT_DIR", "")
+	c.Tenants = l.list("TEST_TENANTS")
+	c.TenantIsolationClientID = l.str("TEST_TENANT_ISOLATION_CLIENT_ID", "")
+	c.TenantIsolationClientSecret = l.str("TEST_TENANT_ISOLATION_CLIENT_SECRET", "")
 
-	c.setServiceURLs()
-	return c, nil
+	c.setServiceURLs(l)
+	c.sources = l.sources
+	c.values = l.values
+	c.problems = l.problems
 }
 
-func (c *Config) setServiceURLs() {
+func (c *Config) setServiceURLs(l *loader) {
 	if c.Domain != "" {
 		base := fmt.Sprintf("https://%s", c.Domain)
 		c.ZeebeGatewayURL = base
-		c.KeycloakURL = base + "/auth"
+		// CI passes an in-cluster Keycloak URL in domain mode too, since
+		// the ingress does not always expose Keycloak; StartPortForwards
+		// tunnels it.
+		c.KeycloakURL = l.str("TEST_KEYCLOAK_URL", base+"/auth")
 		// Actuator endpoints (/actuator/health/...) are NOT exposed via the
 		// public ingress; they live on internal management ports. Allow the
 		// caller to override Orchestration/Connectors URLs (typically with
 		// localhost port-forwards) so preflight checks can still run.
-		c.OrchestrationURL = envOr("TEST_ORCHESTRATION_URL", base)
-		c.ConnectorsURL = envOr("TEST_CONNECTORS_URL", base+"/connectors")
-		c.IdentityURL = envOr("TEST_IDENTITY_URL", base+"/identity")
-		c.OptimizeURL = envOr("TEST_OPTIMIZE_URL", base+"/optimize")
-		c.WebModelerURL = envOr("TEST_WEBMODELER_URL", base+"/modeler")
+		c.OrchestrationURL = l.str("TEST_ORCHESTRATION_URL", base)
+		c.ConnectorsURL = l.str("TEST_CONNECTORS_URL", base+"/connectors")
+		c.IdentityURL = l.str("TEST_IDENTITY_URL", base+"/identity")
+		c.OptimizeURL = l.str("TEST_OPTIMIZE_URL", base+"/optimize")
+		c.WebModelerURL = l.str("TEST_WEBMODELER_URL", base+"/modeler")
 		// Elasticsearch is never exposed via the public ingress, so always
 		// require a port-forwarded URL (defaults to localhost:9200).
-		c.ElasticsearchURL = envOr("TEST_ELASTICSEARCH_URL", "http://localhost:9200")
+		c.ElasticsearchURL = l.str("TEST_ELASTICSEARCH_URL", "http://localhost:9200")
 	} else {
 		// Port-forward mode: services at localhost
-		c.ZeebeGatewayURL = envOr("TEST_ZEEBE_GATEWAY_URL", "http://localhost:8080")
-		c.KeycloakURL = envOr("TEST_KEYCLOAK_URL", "http://localhost:18080/auth")
-		c.ElasticsearchURL = envOr("TEST_ELASTICSEARCH_URL", "http://localhost:9200")
+		c.ZeebeGatewayURL = l.str("TEST_ZEEBE_GATEWAY_URL", "http://localhost:8080")
+		c.KeycloakURL = l.str("TEST_KEYCLOAK_URL", "http://localhost:18080/auth")
+		c.ElasticsearchURL = l.str("TEST_ELASTICSEARCH_URL", "http://localhost:9200")
 
 		// Internal service URLs (via port-forward or in-cluster)
 		rel := c.ReleaseName
-		c.OrchestrationURL = envOr("TEST_ORCHESTRATION_URL", fmt.Sprintf("http://localhost:9600"))
-		c.ConnectorsURL = envOr("TEST_CONNECTORS_URL", fmt.Sprintf("http://%s-connectors:8080", rel))
-		c.IdentityURL = envOr("TEST_IDENTITY_URL", fmt.Sprintf("http://%s-identity:8080", rel))
-		c.OptimizeURL = envOr("TEST_OPTIMIZE_URL", fmt.Sprintf("http://%s-optimize:8083", rel))
-		c.WebModelerURL = envOr("TEST_WEBMODELER_URL", fmt.Sprintf("http://%s-web-modeler-webapp:8070", rel))
+		c.OrchestrationURL = l.str("TEST_ORCHESTRATION_URL", fmt.Sprintf("http://localhost:9600"))
+		c.ConnectorsURL = l.str("TEST_CONNECTORS_URL", fmt.Sprintf("http://%s-connectors:8080", rel))
+		c.IdentityURL = l.str("TEST_IDENTITY_URL", fmt.Sprintf("http://%s-identity:8080", rel))
+		c.OptimizeURL = l.str("TEST_OPTIMIZE_URL", fmt.Sprintf("http://%s-optimize:8083", rel))
+		c.WebModelerURL = l.str("TEST_WEBMODELER_URL", fmt.Sprintf("http://%s-web-modeler-webapp:8070", rel))
 	}
 }
 
+// DefaultTenantID is the ID of the tenant every cluster has.
+const DefaultTenantID = "<default>"
+
 // HasDomain returns true if a domain (ingress) is configured.
 func (c *Config) HasDomain() bool {
 	return c.Domain != ""
@@ -132,42 +340,3 @@ func (c *Config) KeycloakTokenURL() string {
 	}
 	return c.KeycloakURL + "/realms/camunda-platform/protocol/openid-connect/token"
 }
-
-func envOr(key, def string) string {
-	if v := os.Getenv(key); v != "" {
-		return v
-	}
-	return def
-}
-
-func envBool(key string, def bool) bool {
-	v := os.Getenv(key)
-	if v == "" {
-		return def
-	}
-	return strings.EqualFold(v, "true") || v == "1"
-}
-
-func envInt(key string, def int) int {
-	v := os.Getenv(key)
-	if v == "" {
-		return def
-	}
-	n, err := strconv.Atoi(v)
-	if err != nil {
-		return def
-	}
-	return n
-}
-
-func envDuration(key string, def time.Duration) time.Duration {
-	v := os.Getenv(key)
-	if v == "" {
-		return def
-	}
-	d, err := time.ParseDuration(v)
-	if err != nil {
-		return def
-	}
-	return d
-}
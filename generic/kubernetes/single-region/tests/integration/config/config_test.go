@@ -0,0 +1,237 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeProfile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadLayering(t *testing.T) {
+	profile := writeProfile(t, "kind.yaml", `
+TEST_NAMESPACE: from-profile
+TEST_AUTH_MODE: basic
+TEST_RETRY_ATTEMPTS: 7
+TEST_HTTP_TIMEOUT: 45s
+HUB_ENABLED: true
+TEST_OIDC_M2M_CLIENTS:
+  connectors: secret
+`)
+	t.Setenv("TEST_AUTH_MODE", "oidc")
+	t.Setenv("TEST_RELEASE_NAME", "from-env")
+
+	c, err := Load(Options{
+		ProfilePath: profile,
+		Overrides:   map[string]string{"TEST_RELEASE_NAME": "from-flag"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "from-profile", c.Namespace)
+	assert.Equal(t, "oidc", c.AuthMode)
+	assert.Equal(t, "from-flag", c.ReleaseName)
+	assert.Equal(t, 7, c.RetryAttempts)
+	assert.Equal(t, 45*time.Second, c.HTTPTimeout)
+	assert.True(t, c.HubEnabled)
+	assert.JSONEq(t, `{"connectors":"secret"}`, c.OIDCM2MClients)
+	assert.Equal(t, "http://from-flag-connectors:8080", c.ConnectorsURL)
+
+	assert.Equal(t, SourceProfile, c.Source("TEST_NAMESPACE"))
+	assert.Equal(t, SourceEnv, c.Source("TEST_AUTH_MODE"))
+	assert.Equal(t, SourceFlag, c.Source("TEST_RELEASE_NAME"))
+	assert.Equal(t, SourceDefault, c.Source("TEST_CLUSTER_TYPE"))
+}
+
+func TestLoadJSONProfile(t *testing.T) {
+	profile := writeProfile(t, "domain.json", `{"CAMUNDA_DOMAIN":"camunda.example.com","TEST_RETRY_ATTEMPTS":2}`)
+
+	c, err := Load(Options{ProfilePath: profile})
+	require.NoError(t, err)
+	assert.Equal(t, "https://camunda.example.com", c.ZeebeGatewayURL)
+	assert.Equal(t, 2, c.RetryAttempts)
+}
+
+func TestFingerprint(t *testing.T) {
+	load := func(overrides map[string]string) string {
+		t.Helper()
+		c, err := Load(Options{Overrides: overrides})
+		require.NoError(t, err)
+		return c.Fingerprint()
+	}
+
+	base := load(map[string]string{"TEST_NAMESPACE": "a", "TEST_BASIC_PASSWORD": "one"})
+	assert.Len(t, base, 16)
+	assert.Equal(t, base, load(map[string]string{"TEST_NAMESPACE": "a", "TEST_BASIC_PASSWORD": "two"}),
+		"secret values must not affect the fingerprint")
+	assert.NotEqual(t, base, load(map[string]string{"TEST_NAMESPACE": "b", "TEST_BASIC_PASSWORD": "one"}))
+	assert.NotEqual(t, base, load(map[string]string{"TEST_NAMESPACE": "a"}),
+		"setting a secret at all must affect the fingerprint")
+}
+
+func TestLoadRejectsUnknownKeys(t *testing.T) {
+	t.Run("Profile", func(t *testing.T) {
+		profile := writeProfile(t, "typo.yaml", "TEST_ELASTICSEARH_URL: http://es:9200\n")
+		_, err := Load(Options{ProfilePath: profile})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "did you mean TEST_ELASTICSEARCH_URL?")
+	})
+
+	t.Run("Override", func(t *testing.T) {
+		_, err := Load(Options{Overrides: map[string]string{"NOT_A_KEY": "x"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "NOT_A_KEY")
+	})
+
+	t.Run("EnvTypo", func(t *testing.T) {
+		t.Setenv("TEST_ELASTICSEARH_URL", "http://es:9200")
+		_, err := Load(Options{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "did you mean TEST_ELASTICSEARCH_URL?")
+	})
+
+	t.Run("UnrelatedEnvIgnored", func(t *testing.T) {
+		t.Setenv("CAMUNDA_NAMESPACE_0", "camunda-primary")
+		_, err := Load(Options{})
+		assert.NoError(t, err)
+	})
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		c, err := Load(Options{Overrides: map[string]string{
+			"TEST_OIDC_CLIENT_ID":     "test",
+			"TEST_OIDC_CLIENT_SECRET": "secret",
+		}})
+		require.NoError(t, err)
+		assert.NoError(t, c.Validate())
+	})
+
+	t.Run("AggregatesProblems", func(t *testing.T) {
+		t.Setenv("TEST_HTTP_TIMEOUT", "thirty")
+		c, err := Load(Options{Overrides: map[string]string{
+			"TEST_CLUSTER_TYPE":      "eks",
+			"TEST_ZEEBE_GATEWAY_URL": "localhost:8080",
+			"HUB_ENABLED":            "yes",
+		}})
+		require.NoError(t, err)
+
+		var verr *ValidationError
+		require.ErrorAs(t, c.Validate(), &verr)
+		var keys []string
+		for _, p := range verr.Problems {
+			keys = append(keys, p.Key)
+		}
+		assert.ElementsMatch(t, []string{
+			"TEST_HTTP_TIMEOUT",
+			"HUB_ENABLED",
+			"TEST_CLUSTER_TYPE",
+			"TEST_OIDC_CLIENT_ID",
+			"TEST_OIDC_CLIENT_SECRET",
+			"TEST_ZEEBE_GATEWAY_URL",
+		}, keys)
+		assert.Contains(t, verr.Error(), "TEST_HTTP_TIMEOUT: \"thirty\" is not a duration")
+		assert.Contains(t, verr.Error(), "(set via flag)")
+	})
+
+	t.Run("BasicRequiresCredentials", func(t *testing.T) {
+		c, err := Load(Options{Overrides: map[string]string{"TEST_AUTH_MODE": "basic"}})
+		require.NoError(t, err)
+		err = c.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "TEST_BASIC_USER: required when TEST_AUTH_MODE=basic")
+		assert.Contains(t, err.Error(), "TEST_BASIC_PASSWORD: required when TEST_AUTH_MODE=basic")
+	})
+
+	t.Run("SecondaryStorage", func(t *testing.T) {
+		t.Setenv("ELASTICSEARCH_ENABLED", "false")
+		c, err := Load(Options{})
+		require.NoError(t, err)
+		assert.Equal(t, "none", c.SecondaryStorage)
+
+		c, err = Load(Options{Overrides: map[string]string{
+			"TEST_SECONDARY_STORAGE": "rdbms",
+			"TEST_RDBMS_URL":         "mysql://db:3306/camunda",
+		}})
+		require.NoError(t, err)
+		assert.False(t, c.ElasticsearchEnabled)
+		err = c.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "TEST_RDBMS_URL: must be a postgres://")
+
+		c, err = Load(Options{Overrides: map[string]string{
+			"TEST_SECONDARY_STORAGE":     "opensearch",
+			"TEST_OPENSEARCH_AWS_REGION": "eu-west-1",
+			"TEST_OPENSEARCH_USER":       "admin",
+		}})
+		require.NoError(t, err)
+		err = c.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "TEST_OPENSEARCH_URL: required")
+		assert.Contains(t, err.Error(), "TEST_OPENSEARCH_PASSWORD")
+		assert.Contains(t, err.Error(), "TEST_OPENSEARCH_AWS_REGION: SigV4 signing and basic auth are mutually exclusive")
+	})
+	t.Run("Tenants", func(t *testing.T) {
+		profile := writeProfile(t, "tenants.yaml", `
+TEST_AUTH_MODE: basic
+TEST_BASIC_USER: demo
+TEST_BASIC_PASSWORD: demo
+TEST_TENANTS: [tenant-a, "<default>"]
+`)
+		c, err := Load(Options{ProfilePath: profile})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"tenant-a", DefaultTenantID}, c.Tenants)
+		require.NoError(t, c.Validate())
+
+		c, err = Load(Options{Overrides: map[string]string{
+			"TEST_AUTH_MODE": "bearer",
+			"TEST_TENANTS":   "tenant a, tenant-b,tenant-b",
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"tenant a", "tenant-b", "tenant-b"}, c.Tenants)
+		err = c.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `tenant ID "tenant a" must be`)
+		assert.Contains(t, err.Error(), `tenant "tenant-b" is listed twice`)
+		assert.Contains(t, err.Error(), "needs an OIDC or basic auth mode")
+	})
+	t.Run("Soak", func(t *testing.T) {
+		c, err := Load(Options{Overrides: map[string]string{
+			"TEST_AUTH_MODE":           "bearer",
+			"TEST_SOAK_RATE":           "2.5",
+			"TEST_SOAK_MAX_ERROR_RATE": "1.5",
+			"TEST_SOAK_CONCURRENCY":    "0",
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, 2.5, c.SoakRate)
+		err = c.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "TEST_SOAK_MAX_ERROR_RATE: must be between 0 and 1, got 1.5")
+		assert.Contains(t, err.Error(), "TEST_SOAK_CONCURRENCY: must be at least 1, got 0")
+
+		c, err = Load(Options{Overrides: map[string]string{"TEST_SOAK_RATE": "fast"}})
+		require.NoError(t, err)
+		assert.Contains(t, c.Validate().Error(), `TEST_SOAK_RATE: "fast" is not a number`)
+	})
+	t.Run("Upgrade", func(t *testing.T) {
+		c, err := Load(Options{Overrides: map[string]string{
+			"TEST_AUTH_MODE":            "bearer",
+			"TEST_UPGRADE_FROM_VERSION": "14.0.0",
+		}})
+		require.NoError(t, err)
+		assert.Contains(t, c.Validate().Error(), "TEST_UPGRADE_VALUES: required when TEST_UPGRADE_FROM_VERSION is set")
+
+		c, err = Load(Options{Overrides: map[string]string{"TEST_UPGRADE_VALUES": "a.yml,b.yml"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a.yml", "b.yml"}, c.UpgradeValues)
+		assert.Equal(t, c.UpgradeValues, c.UpgradeFromValues, "N-1 uses the target values unless set")
+	})
+}
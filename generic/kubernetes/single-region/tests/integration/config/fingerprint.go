@@ -0,0 +1,47 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+)
+
+// secretKeys hold credentials. Fingerprint records only whether they are
+// set, so a report never carries anything derived from their values.
+var secretKeys = map[string]bool{
+	"TEST_BASIC_PASSWORD":                 true,
+	"TEST_OIDC_CLIENT_SECRET":             true,
+	"TEST_OIDC_ASSERTION_KEY":             true,
+	"TEST_BEARER_TOKEN":                   true,
+	"TEST_TLS_CLIENT_KEY":                 true,
+	"TEST_OIDC_M2M_CLIENTS":               true,
+	"TEST_ELASTICSEARCH_PASSWORD":         true,
+	"TEST_OPENSEARCH_PASSWORD":            true,
+	"TEST_RDBMS_URL":                      true, // may embed a password
+	"TEST_RDBMS_PASSWORD":                 true,
+	"TEST_TENANT_ISOLATION_CLIENT_SECRET": true,
+}
+
+// Fingerprint identifies the effective configuration: a short SHA-256 over
+// every key that was set explicitly (profile, environment or override) and
+// its value. Two runs with the same fingerprint targeted the same
+// installation with the same settings. Runtime rewrites such as
+// port-forward URLs are not included, and secrets contribute only the fact
+// that they were set.
+func (c *Config) Fingerprint() string {
+	keys := make([]string, 0, len(c.values))
+	for k := range c.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	h := sha256.New()
+	for _, k := range keys {
+		v := c.values[k]
+		if secretKeys[k] {
+			v = "<set>"
+		}
+		fmt.Fprintf(h, "%s=%s\n", k, v)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
@@ -0,0 +1,499 @@
+package config
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProfileEnv names the environment variable that points Load at a profile
+// file when Options.ProfilePath is empty.
+const ProfileEnv = "TEST_PROFILE"
+
+// Source identifies the layer a configuration value was taken from.
+type Source string
+
+const (
+	SourceDefault Source = "default"
+	SourceProfile Source = "profile"
+	SourceEnv     Source = "env"
+	SourceFlag    Source = "flag"
+	// SourcePortForward marks URLs rewritten to a local tunnel at runtime.
+	SourcePortForward Source = "port-forward"
+)
+
+// knownKeys lists every key the suite understands. Profile files and -set
+// overrides may only use these; environment variables that look like a
+// misspelling of one of them are rejected.
+var knownKeys = []string{
+	"TEST_NAMESPACE",
+	"TEST_RELEASE_NAME",
+	"TEST_CLUSTER_TYPE",
+	"CAMUNDA_DOMAIN",
+	"CAMUNDA_DOMAIN_GRPC",
+	"TEST_AUTH_MODE",
+	"TEST_BASIC_USER",
+	"TEST_BASIC_PASSWORD",
+	"TEST_OIDC_TOKEN_URL",
+	"TEST_OIDC_CLIENT_ID",
+	"TEST_OIDC_CLIENT_SECRET",
+	"TEST_OIDC_M2M_CLIENTS",
+	"TEST_OIDC_AUDIENCE",
+	"TEST_OIDC_SCOPE",
+	"TEST_OIDC_ASSERTION_KEY",
+	"TEST_OIDC_ASSERTION_KEY_ID",
+	"TEST_BEARER_TOKEN",
+	"TEST_TLS_CLIENT_CERT",
+	"TEST_TLS_CLIENT_KEY",
+	"TEST_TLS_CA_CERT",
+	"ELASTICSEARCH_ENABLED",
+	"HUB_ENABLED",
+	"OPTIMIZE_ENABLED",
+	"TEST_PUBLIC_API_ENABLED",
+	"TEST_ZEEBE_GATEWAY_URL",
+	"TEST_KEYCLOAK_URL",
+	"TEST_ELASTICSEARCH_URL",
+	"TEST_ELASTICSEARCH_USER",
+	"TEST_ELASTICSEARCH_PASSWORD",
+	"TEST_SECONDARY_STORAGE",
+	"TEST_INDEX_PREFIX",
+	"TEST_OPENSEARCH_URL",
+	"TEST_OPENSEARCH_USER",
+	"TEST_OPENSEARCH_PASSWORD",
+	"TEST_OPENSEARCH_AWS_REGION",
+	"TEST_OPENSEARCH_AWS_SERVICE",
+	"TEST_RDBMS_URL",
+	"TEST_RDBMS_USER",
+	"TEST_RDBMS_PASSWORD",
+	"TEST_RDBMS_TABLE_PREFIX",
+	"TEST_ORCHESTRATION_URL",
+	"TEST_CONNECTORS_URL",
+	"TEST_IDENTITY_URL",
+	"TEST_OPTIMIZE_URL",
+	"TEST_WEBMODELER_URL",
+	"TEST_HTTP_TIMEOUT",
+	"TEST_RETRY_ATTEMPTS",
+	"TEST_RETRY_DELAY",
+	"TEST_E2E_INSTANCES",
+	"TEST_E2E_MAX_LATENCY",
+	"TEST_MOCK_LISTEN_ADDR",
+	"TEST_MOCK_ADVERTISE_URL",
+	"TEST_SOAK_RATE",
+	"TEST_SOAK_DURATION",
+	"TEST_SOAK_CONCURRENCY",
+	"TEST_SOAK_MAX_ERROR_RATE",
+	"TEST_SOAK_MAX_BACKLOG",
+	"TEST_BACKUP_ENABLED",
+	"TEST_BACKUP_REPOSITORY",
+	"TEST_BACKUP_TIMEOUT",
+	"TEST_UPGRADE_REPO_URL",
+	"TEST_UPGRADE_FROM_CHART",
+	"TEST_UPGRADE_FROM_VERSION",
+	"TEST_UPGRADE_FROM_VALUES",
+	"TEST_UPGRADE_TO_CHART",
+	"TEST_UPGRADE_TO_VERSION",
+	"TEST_UPGRADE_VALUES",
+	"TEST_UPGRADE_TIMEOUT",
+	"TEST_UPGRADE_KEEP_RELEASE",
+	"TEST_REPORT_DIR",
+	"TEST_TENANTS",
+	"TEST_TENANT_ISOLATION_CLIENT_ID",
+	"TEST_TENANT_ISOLATION_CLIENT_SECRET",
+	"TEST_PORT_FORWARD",
+	"TEST_KUBE_CONTEXT",
+}
+
+// Options controls how Load assembles a Config.
+type Options struct {
+	// ProfilePath is a YAML (.yaml/.yml) or JSON (.json) file whose top-level
+	// keys are the same names as the environment variables. Empty falls back
+	// to $TEST_PROFILE; if that is empty too, no profile is read.
+	ProfilePath string
+	// Overrides win over both the profile and the environment. They are
+	// typically collected from -set KEY=VALUE flags (see BindFlags).
+	Overrides map[string]string
+}
+
+// Load builds a Config by layering, lowest precedence first: built-in
+// defaults, the profile file, environment variables and Overrides. Empty
+// values count as unset at every layer, matching the historical env-only
+// behaviour. Unknown keys in the profile or Overrides, and environment
+// variables that are a near-miss of a known key (e.g. TEST_ELASTICSEARH_URL),
+// are reported as errors instead of being silently ignored.
+func Load(opts Options) (*Config, error) {
+	l := &loader{sources: map[string]Source{}, values: map[string]string{}}
+
+	path := opts.ProfilePath
+	if path == "" {
+		path = os.Getenv(ProfileEnv)
+	}
+	if path != "" {
+		values, err := readProfile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkKeys(values, "profile "+path); err != nil {
+			return nil, err
+		}
+		l.layers = append(l.layers, layer{source: SourceProfile, lookup: mapLookup(values)})
+	}
+
+	if err := checkEnv(os.Environ()); err != nil {
+		return nil, err
+	}
+	l.layers = append(l.layers, layer{source: SourceEnv, lookup: os.LookupEnv})
+
+	if len(opts.Overrides) > 0 {
+		if err := checkKeys(opts.Overrides, "-set overrides"); err != nil {
+			return nil, err
+		}
+		l.layers = append(l.layers, layer{source: SourceFlag, lookup: mapLookup(opts.Overrides)})
+	}
+
+	c := &Config{}
+	c.populate(l)
+	return c, nil
+}
+
+// BindFlags registers -profile and -set on fs and returns the Options they
+// fill in once fs is parsed. Call it from TestMain before flag.Parse:
+//
+//	go test ./core/... -args -profile=kind-oidc.yaml -set TEST_AUTH_MODE=basic
+func BindFlags(fs *flag.FlagSet) *Options {
+	opts := &Options{Overrides: map[string]string{}}
+	fs.StringVar(&opts.ProfilePath, "profile", "", "YAML/JSON config profile (overrides $"+ProfileEnv+")")
+	fs.Var((*overrideFlag)(&opts.Overrides), "set", "config override KEY=VALUE (repeatable, wins over profile and env)")
+	return opts
+}
+
+// Source reports which layer supplied key. Keys that were never read (e.g.
+// the port-forward URLs in domain mode) report SourceDefault.
+func (c *Config) Source(key string) Source {
+	if s, ok := c.sources[key]; ok {
+		return s
+	}
+	return SourceDefault
+}
+
+// SetURL replaces the URL field controlled by key and records src as its
+// source. Only the service URL keys are accepted; it returns false for any
+// other key.
+func (c *Config) SetURL(key, value string, src Source) bool {
+	fields := map[string]*string{
+		"TEST_ZEEBE_GATEWAY_URL": &c.ZeebeGatewayURL,
+		"TEST_KEYCLOAK_URL":      &c.KeycloakURL,
+		"TEST_ELASTICSEARCH_URL": &c.ElasticsearchURL,
+		"TEST_ORCHESTRATION_URL": &c.OrchestrationURL,
+		"TEST_CONNECTORS_URL":    &c.ConnectorsURL,
+		"TEST_IDENTITY_URL":      &c.IdentityURL,
+		"TEST_OPTIMIZE_URL":      &c.OptimizeURL,
+		"TEST_WEBMODELER_URL":    &c.WebModelerURL,
+	}
+	f, ok := fields[key]
+	if !ok {
+		return false
+	}
+	*f = value
+	if c.sources == nil {
+		c.sources = map[string]Source{}
+	}
+	c.sources[key] = src
+	return true
+}
+
+// SourceReport renders one "KEY source" line per known key, sorted by key.
+// Values are deliberately omitted so secrets never reach the test log.
+func (c *Config) SourceReport() string {
+	keys := append([]string(nil), knownKeys...)
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%-30s %s\n", k, c.Source(k))
+	}
+	return b.String()
+}
+
+type layer struct {
+	source Source
+	lookup func(key string) (string, bool)
+}
+
+// loader resolves keys across layers and records the winning source. Values
+// that fail to parse fall back to the default and are recorded as problems,
+// which Validate reports.
+type loader struct {
+	layers   []layer
+	sources  map[string]Source
+	values   map[string]string
+	problems []Problem
+}
+
+func (l *loader) lookup(key string) (string, bool) {
+	for i := len(l.layers) - 1; i >= 0; i-- {
+		if v, ok := l.layers[i].lookup(key); ok && v != "" {
+			l.sources[key] = l.layers[i].source
+			l.values[key] = v
+			return v, true
+		}
+	}
+	l.sources[key] = SourceDefault
+	return "", false
+}
+
+func (l *loader) invalid(key, format string, args ...interface{}) {
+	l.problems = append(l.problems, Problem{Key: key, Message: fmt.Sprintf(format, args...)})
+}
+
+func (l *loader) str(key, def string) string {
+	if v, ok := l.lookup(key); ok {
+		return v
+	}
+	return def
+}
+
+// list reads a comma-separated value, or a JSON array (which is what a YAML
+// sequence in a profile becomes). Blank entries are dropped.
+func (l *loader) list(key string) []string {
+	v, ok := l.lookup(key)
+	if !ok {
+		return nil
+	}
+	var items []string
+	if strings.HasPrefix(strings.TrimSpace(v), "[") {
+		if err := json.Unmarshal([]byte(v), &items); err != nil {
+			l.invalid(key, "%q is not a list of strings", v)
+			return nil
+		}
+	} else {
+		items = strings.Split(v, ",")
+	}
+	var out []string
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func (l *loader) bool(key string, def bool) bool {
+	v, ok := l.lookup(key)
+	if !ok {
+		return def
+	}
+	switch {
+	case strings.EqualFold(v, "true") || v == "1":
+		return true
+	case strings.EqualFold(v, "false") || v == "0":
+		return false
+	}
+	l.invalid(key, "%q is not a boolean (want true/false/1/0)", v)
+	return def
+}
+
+func (l *loader) int(key string, def int) int {
+	v, ok := l.lookup(key)
+	if !ok {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		l.invalid(key, "%q is not an integer", v)
+		return def
+	}
+	return n
+}
+
+func (l *loader) float(key string, def float64) float64 {
+	v, ok := l.lookup(key)
+	if !ok {
+		return def
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		l.invalid(key, "%q is not a number", v)
+		return def
+	}
+	return f
+}
+
+func (l *loader) duration(key string, def time.Duration) time.Duration {
+	v, ok := l.lookup(key)
+	if !ok {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		l.invalid(key, "%q is not a duration (e.g. 30s, 2m)", v)
+		return def
+	}
+	return d
+}
+
+func mapLookup(m map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := m[key]
+		return v, ok
+	}
+}
+
+// readProfile parses a flat YAML or JSON document into string values.
+// Scalars are stringified; objects and arrays are re-encoded as JSON so keys
+// like TEST_OIDC_M2M_CLIENTS can be written as a native mapping.
+func readProfile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading profile: %w", err)
+	}
+	var raw map[string]interface{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		dec := json.NewDecoder(strings.NewReader(string(data)))
+		dec.UseNumber()
+		err = dec.Decode(&raw)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	default:
+		return nil, fmt.Errorf("profile %s: unsupported extension (want .yaml, .yml or .json)", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing profile %s: %w", path, err)
+	}
+
+	values := make(map[string]string, len(raw))
+	for k, v := range raw {
+		switch v := v.(type) {
+		case nil:
+			values[k] = ""
+		case string:
+			values[k] = v
+		case map[string]interface{}, []interface{}:
+			b, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("profile %s: key %s: %w", path, k, err)
+			}
+			values[k] = string(b)
+		default:
+			values[k] = fmt.Sprint(v)
+		}
+	}
+	return values, nil
+}
+
+func isKnownKey(key string) bool {
+	for _, k := range knownKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+func checkKeys(values map[string]string, origin string) error {
+	var unknown []string
+	for k := range values {
+		if !isKnownKey(k) {
+			unknown = append(unknown, k+suggest(k))
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+	return fmt.Errorf("%s: unknown keys: %s", origin, strings.Join(unknown, ", "))
+}
+
+// checkEnv rejects TEST_* and CAMUNDA_* environment variables that are
+// within two edits of a known key. The CI job environment is shared with
+// unrelated tooling (CAMUNDA_NAMESPACE, TEST_DIR, ...), so unrelated names are
+// left alone and only likely typos fail the run.
+func checkEnv(environ []string) error {
+	var typos []string
+	for _, kv := range environ {
+		name, _, _ := strings.Cut(kv, "=")
+		if isKnownKey(name) || name == ProfileEnv {
+			continue
+		}
+		if !strings.HasPrefix(name, "TEST_") && !strings.HasPrefix(name, "CAMUNDA_") {
+			continue
+		}
+		if hint := suggest(name); hint != "" {
+			typos = append(typos, name+hint)
+		}
+	}
+	if len(typos) == 0 {
+		return nil
+	}
+	sort.Strings(typos)
+	return fmt.Errorf("environment: unrecognised variables: %s", strings.Join(typos, ", "))
+}
+
+// suggest returns a " (did you mean X?)" hint when key is within two edits
+// of a known key, or "" otherwise.
+func suggest(key string) string {
+	best, bestDist := "", 3
+	for _, k := range knownKeys {
+		if d := editDistance(key, k); d < bestDist {
+			best, bestDist = k, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (did you mean %s?)", best)
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// overrideFlag collects repeated -set KEY=VALUE flags.
+type overrideFlag map[string]string
+
+func (o *overrideFlag) String() string {
+	if o == nil {
+		return ""
+	}
+	keys := make([]string, 0, len(*o))
+	for k := range *o {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
+
+func (o *overrideFlag) Set(s string) error {
+	k, v, ok := strings.Cut(s, "=")
+	if !ok || k == "" {
+		return fmt.Errorf("want KEY=VALUE, got %q", s)
+	}
+	if *o == nil {
+		*o = map[string]string{}
+	}
+	(*o)[k] = v
+	return nil
+}
@@ -0,0 +1,221 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// tenantIDPattern is the tenant ID format the orchestration cluster accepts.
+var tenantIDPattern = regexp.MustCompile(`^[\w.-]{1,31}$`)
+
+// Problem is a single invalid or missing setting, keyed by the environment
+// variable / profile key that controls it.
+type Problem struct {
+	Key     string
+	Message string
+}
+
+// ValidationError aggregates every Problem found by Validate so a broken
+// setup is fixed in one round-trip rather than one failing test at a time.
+type ValidationError struct {
+	Problems []Problem
+	sources  map[string]Source
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "invalid integration test config (%d problem(s)):", len(e.Problems))
+	for _, p := range e.Problems {
+		fmt.Fprintf(&b, "\n  - %s: %s", p.Key, p.Message)
+		if s, ok := e.sources[p.Key]; ok && s != SourceDefault {
+			fmt.Fprintf(&b, " (set via %s)", s)
+		}
+	}
+	return b.String()
+}
+
+// Validate checks parse errors recorded by Load and the cross-field rules
+// the suites depend on. It returns a *ValidationError listing all problems,
+// or nil when the config is usable.
+func (c *Config) Validate() error {
+	problems := append([]Problem(nil), c.problems...)
+	add := func(key, format string, args ...interface{}) {
+		problems = append(problems, Problem{Key: key, Message: fmt.Sprintf(format, args...)})
+	}
+
+	switch c.ClusterType {
+	case "kubernetes", "openshift":
+	default:
+		add("TEST_CLUSTER_TYPE", "%q is not supported (want kubernetes or openshift)", c.ClusterType)
+	}
+
+	if check, ok := authModes[c.AuthMode]; !ok {
+		add("TEST_AUTH_MODE", "%q is not supported (want one of %s)", c.AuthMode, strings.Join(AuthModes(), ", "))
+	} else if check != nil {
+		problems = append(problems, check(c)...)
+	}
+
+	if (c.TLSClientCert == "") != (c.TLSClientKey == "") {
+		add("TEST_TLS_CLIENT_KEY", "TEST_TLS_CLIENT_CERT and TEST_TLS_CLIENT_KEY must be set together")
+	}
+	for _, f := range []struct{ key, path string }{
+		{"TEST_OIDC_ASSERTION_KEY", c.OIDCAssertionKey},
+		{"TEST_TLS_CLIENT_CERT", c.TLSClientCert},
+		{"TEST_TLS_CLIENT_KEY", c.TLSClientKey},
+		{"TEST_TLS_CA_CERT", c.TLSCACert},
+	} {
+		if f.path == "" {
+			continue
+		}
+		if _, err := os.Stat(f.path); err != nil {
+			add(f.key, "cannot read %s: %v", f.path, err)
+		}
+	}
+
+	if c.OIDCM2MClients != "" {
+		var clients map[string]string
+		if err := json.Unmarshal([]byte(c.OIDCM2MClients), &clients); err != nil {
+			add("TEST_OIDC_M2M_CLIENTS", "must be a JSON object of client_id to secret: %v", err)
+		}
+	}
+
+	if len(c.Tenants) > 0 && !c.UsesOIDC() && c.AuthMode != "basic" {
+		add("TEST_TENANTS", "tenant tests assign the suite's principal to each tenant, which needs an OIDC or basic auth mode (got %q)", c.AuthMode)
+	}
+	seen := map[string]bool{}
+	for _, id := range c.Tenants {
+		switch {
+		case seen[id]:
+			add("TEST_TENANTS", "tenant %q is listed twice", id)
+		case id != DefaultTenantID && !tenantIDPattern.MatchString(id):
+			add("TEST_TENANTS", "tenant ID %q must be 1-31 letters, digits, '_', '-' or '.'", id)
+		}
+		seen[id] = true
+	}
+	if (c.TenantIsolationClientID == "") != (c.TenantIsolationClientSecret == "") {
+		add("TEST_TENANT_ISOLATION_CLIENT_ID", "TEST_TENANT_ISOLATION_CLIENT_ID and TEST_TENANT_ISOLATION_CLIENT_SECRET must be set together")
+	}
+
+	if strings.Contains(c.DomainGRPC, "://") {
+		add("CAMUNDA_DOMAIN_GRPC", "must be host[:port] without a scheme, got %q", c.DomainGRPC)
+	}
+
+	if (c.ElasticsearchUser == "") != (c.ElasticsearchPassword == "") {
+		add("TEST_ELASTICSEARCH_PASSWORD", "TEST_ELASTICSEARCH_USER and TEST_ELASTICSEARCH_PASSWORD must be set together")
+	}
+
+	switch c.SecondaryStorage {
+	case "none", "elasticsearch":
+	case "opensearch":
+		if c.OpenSearchURL == "" {
+			add("TEST_OPENSEARCH_URL", "required when TEST_SECONDARY_STORAGE=opensearch")
+		}
+		if (c.OpenSearchUser == "") != (c.OpenSearchPassword == "") {
+			add("TEST_OPENSEARCH_PASSWORD", "TEST_OPENSEARCH_USER and TEST_OPENSEARCH_PASSWORD must be set together")
+		}
+		if c.OpenSearchAWSRegion != "" && c.OpenSearchUser != "" {
+			add("TEST_OPENSEARCH_AWS_REGION", "SigV4 signing and basic auth are mutually exclusive; unset TEST_OPENSEARCH_USER")
+		}
+		if c.OpenSearchAWSService != "es" && c.OpenSearchAWSService != "aoss" {
+			add("TEST_OPENSEARCH_AWS_SERVICE", "%q is not supported (want es or aoss)", c.OpenSearchAWSService)
+		}
+	case "rdbms":
+		if c.RDBMSURL == "" {
+			add("TEST_RDBMS_URL", "required when TEST_SECONDARY_STORAGE=rdbms")
+		} else if u, err := url.Parse(c.RDBMSURL); err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") || u.Host == "" {
+			add("TEST_RDBMS_URL", "must be a postgres://host[:port]/database URL")
+		}
+	default:
+		add("TEST_SECONDARY_STORAGE", "%q is not supported (want elasticsearch, opensearch, rdbms or none)", c.SecondaryStorage)
+	}
+
+	urls := []struct {
+		key string
+		val string
+	}{
+		{"TEST_ZEEBE_GATEWAY_URL", c.ZeebeGatewayURL},
+		{"TEST_ORCHESTRATION_URL", c.OrchestrationURL},
+		{"TEST_CONNECTORS_URL", c.ConnectorsURL},
+		{"TEST_IDENTITY_URL", c.IdentityURL},
+		{"TEST_OPTIMIZE_URL", c.OptimizeURL},
+		{"TEST_WEBMODELER_URL", c.WebModelerURL},
+		{"TEST_ELASTICSEARCH_URL", c.ElasticsearchURL},
+		{"TEST_OPENSEARCH_URL", c.OpenSearchURL},
+		{"TEST_MOCK_ADVERTISE_URL", c.MockAdvertiseURL},
+	}
+	for _, u := range urls {
+		if u.val == "" {
+			continue
+		}
+		if msg := checkURL(u.val); msg != "" {
+			add(u.key, "%s", msg)
+		}
+	}
+
+	if c.HTTPTimeout <= 0 {
+		add("TEST_HTTP_TIMEOUT", "must be positive, got %s", c.HTTPTimeout)
+	}
+	if c.RetryAttempts < 1 {
+		add("TEST_RETRY_ATTEMPTS", "must be at least 1, got %d", c.RetryAttempts)
+	}
+	if c.RetryDelay < 0 {
+		add("TEST_RETRY_DELAY", "must not be negative, got %s", c.RetryDelay)
+	}
+	if c.E2EInstances < 1 {
+		add("TEST_E2E_INSTANCES", "must be at least 1, got %d", c.E2EInstances)
+	}
+	if c.E2EMaxLatency <= 0 {
+		add("TEST_E2E_MAX_LATENCY", "must be positive, got %s", c.E2EMaxLatency)
+	}
+	if c.SoakRate <= 0 {
+		add("TEST_SOAK_RATE", "must be positive, got %g", c.SoakRate)
+	}
+	if c.SoakDuration <= 0 {
+		add("TEST_SOAK_DURATION", "must be positive, got %s", c.SoakDuration)
+	}
+	if c.SoakConcurrency < 1 {
+		add("TEST_SOAK_CONCURRENCY", "must be at least 1, got %d", c.SoakConcurrency)
+	}
+	if c.SoakMaxErrorRate < 0 || c.SoakMaxErrorRate > 1 {
+		add("TEST_SOAK_MAX_ERROR_RATE", "must be between 0 and 1, got %g", c.SoakMaxErrorRate)
+	}
+	if c.SoakMaxBacklog < 1 {
+		add("TEST_SOAK_MAX_BACKLOG", "must be at least 1, got %d", c.SoakMaxBacklog)
+	}
+	if c.BackupTimeout <= 0 {
+		add("TEST_BACKUP_TIMEOUT", "must be positive, got %s", c.BackupTimeout)
+	}
+	if c.UpgradeFromVersion != "" {
+		if len(c.UpgradeValues) == 0 {
+			add("TEST_UPGRADE_VALUES", "required when TEST_UPGRADE_FROM_VERSION is set (the reference helm-values files)")
+		}
+		if c.UpgradeTimeout <= 0 {
+			add("TEST_UPGRADE_TIMEOUT", "must be positive, got %s", c.UpgradeTimeout)
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: problems, sources: c.sources}
+}
+
+// checkURL returns a human-readable reason when raw is not an absolute
+// http(s) URL, or "" when it is.
+func checkURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Sprintf("%q does not parse: %v", raw, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Sprintf("%q must use http or https", raw)
+	}
+	if u.Host == "" {
+		return fmt.Sprintf("%q has no host", raw)
+	}
+	return ""
+}
@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/helpers"
 )
 
 // componentReadyTimeout bounds, in wall-clock time, how long a component
@@ -31,10 +33,13 @@ const (
 //
 // Each sub-test is skipped when the corresponding component is disabled or
 // when the URL is unreachable from the runner (typically no-domain mode where
-// no port-forward was set up for that component).
+// no port-forward was set up for that component). Optimize and Web Modeler
+// have functional checks of their own (TestOptimizePublicAPI,
+// TestWebModelerAPI).
 func TestComponentAPIs(t *testing.T) {
-	if cfg.AuthMode != "oidc" && cfg.AuthMode != "basic" {
-		t.Skip("component API tests require authentication")
+	helpers.Track(t)
+	if !cfg.Authenticated() {
+		helpers.Skip(t, "component API tests require authentication")
 	}
 
 	cases := []struct {
@@ -63,14 +68,14 @@ func TestComponentAPIs(t *testing.T) {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
 			if tc.skip {
-				t.Skip(tc.skipReason)
+				helpers.Skip(t, tc.skipReason)
 			}
 			if tc.url == "" {
-				t.Skipf("%s URL not configured", tc.name)
+				helpers.Skipf(t, "%s URL not configured", tc.name)
 			}
 			fullURL := tc.url + tc.path
 			probe := func() error {
-				resp, err := client.Get(fullURL)
+				resp, err := client.GetContext(t.Context(), fullURL)
 				if err != nil {
 					return fmt.Errorf("GET %s failed: %w", fullURL, err)
 				}
@@ -82,24 +87,10 @@ func TestComponentAPIs(t *testing.T) {
 				}
 				return fmt.Errorf("GET %s: unexpected status %d (accepted: %v)", fullURL, resp.StatusCode, tc.acceptCodes)
 			}
-			// Retry the reachability probe until it returns an accepted status or
-			// componentReadyTimeout of wall-clock elapses, waiting
-			// componentReadyPollInterval between tries (clamped so a sleep never
-			// runs past the deadline). See the consts for the rationale.
-			deadline := time.Now().Add(componentReadyTimeout)
-			err := probe()
-			for err != nil {
-				remaining := time.Until(deadline)
-				if remaining <= 0 {
-					break
-				}
-				sleep := componentReadyPollInterval
-				if sleep > remaining {
-					sleep = remaining
-				}
-				time.Sleep(sleep)
-				err = probe()
-			}
+			// Poll until the probe returns an accepted status or
+			// componentReadyTimeout of wall-clock elapses. See the consts for
+			// the rationale.
+			err := helpers.DeadlineRetry(componentReadyTimeout, componentReadyPollInterval).Do(t.Context(), t, probe)
 			assert.NoError(t, err, "%s API endpoint %s should be reachable", tc.name, tc.path)
 		})
 	}
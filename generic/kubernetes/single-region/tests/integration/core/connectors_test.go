@@ -0,0 +1,101 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/camunda"
+	"github.com/camunda/camunda-deployment-references/tests/integration/camunda/fixtures"
+	"github.com/camunda/camunda-deployment-references/tests/integration/helpers"
+)
+
+const (
+	webhookProcessID = "integration-test-webhook-rest"
+	webhookContext   = "integration-test-webhook-rest"
+
+	// webhookTimeout bounds both waits: the connectors runtime picking up
+	// the new process definition (it polls for them) and the outbound REST
+	// call reaching the mock.
+	webhookTimeout = 3 * time.Minute
+)
+
+// TestInboundWebhookConnector exercises the connectors runtime end to end:
+// a POST to the webhook inbound connector starts a process whose REST
+// outbound connector calls back into a mock served by the test itself. The
+// EKS dual-region suite does the same with the mock-api-server StatefulSet;
+// here the mock runs in-process so the check also works on kind, provided
+// TEST_MOCK_ADVERTISE_URL is reachable from the cluster.
+func TestInboundWebhookConnector(t *testing.T) {
+	helpers.Track(t)
+	if !cfg.Authenticated() {
+		helpers.Skip(t, "process deployment requires authentication")
+	}
+	if cfg.MockAdvertiseURL == "" {
+		helpers.Skip(t, "TEST_MOCK_ADVERTISE_URL not set; the connectors runtime cannot reach a mock target")
+	}
+
+	mock, err := helpers.StartMockServer(cfg.MockListenAddr)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = mock.Close() })
+	t.Logf("mock target listening on %s, advertised as %s", mock.Addr(), cfg.MockAdvertiseURL)
+
+	resource := camunda.Resource{Name: "webhook-rest.bpmn", Content: []byte(fixtures.WebhookRESTBPMN(webhookProcessID, webhookContext))}
+	err = helpers.ConstantRetry(5, cfg.RetryDelay).Do(t.Context(), t, func() error {
+		_, err := api.Deploy(t.Context(), "", resource)
+		return err
+	})
+	require.NoError(t, err, "deploy should succeed")
+
+	runID := fmt.Sprintf("%d", time.Now().UnixNano())
+	callbackPath := "/callback/" + runID
+	payload := map[string]interface{}{"runId": runID, "text": "camunda integration test"}
+	trigger, err := json.Marshal(map[string]interface{}{
+		"callbackURL": strings.TrimRight(cfg.MockAdvertiseURL, "/") + callbackPath,
+		"payload":     payload,
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(t.Context(), webhookTimeout)
+	defer cancel()
+
+	t.Run("TriggerWebhook", func(t *testing.T) {
+		webhookURL := strings.TrimRight(cfg.ConnectorsURL, "/") + "/inbound/" + webhookContext
+		// 404 means the runtime has not registered the webhook for the new
+		// deployment yet.
+		policy := helpers.DeadlineRetry(webhookTimeout, 5*time.Second)
+		policy.Retryable = func(err error) bool {
+			return helpers.StatusCode(err) == http.StatusNotFound || helpers.IsRetryable(err)
+		}
+		err := policy.Do(ctx, t, func() error {
+			resp, err := client.PostJSONContext(ctx, webhookURL, string(trigger))
+			if err != nil {
+				return err
+			}
+			body, err := helpers.ReadBody(resp)
+			if err != nil {
+				return err
+			}
+			if resp.StatusCode != http.StatusOK {
+				return helpers.NewStatusError(resp, body)
+			}
+			return nil
+		})
+		require.NoError(t, err, "webhook %s should accept the trigger", webhookURL)
+	})
+
+	t.Run("MockReceivedCall", func(t *testing.T) {
+		got, err := mock.WaitFor(ctx, func(r helpers.MockRequest) bool { return r.Path == callbackPath })
+		require.NoError(t, err, "REST outbound connector should call the mock at %s", callbackPath)
+		assert.Equal(t, http.MethodPost, got.Method)
+		want, _ := json.Marshal(payload)
+		assert.JSONEq(t, string(want), string(got.Body), "outbound payload")
+	})
+}
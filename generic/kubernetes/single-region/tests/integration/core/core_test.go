@@ -3,16 +3,17 @@
 package core
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"net/http"
-	"os"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/camunda/camunda-deployment-references/tests/integration/camunda"
+	"github.com/camunda/camunda-deployment-references/tests/integration/camunda/fixtures"
 	"github.com/camunda/camunda-deployment-references/tests/integration/config"
 	"github.com/camunda/camunda-deployment-references/tests/integration/helpers"
 )
@@ -20,24 +21,25 @@ import (
 var (
 	cfg    *config.Config
 	client *helpers.Client
+	api    *camunda.Client
 )
 
-func TestMain(m *testing.M) {
-	var err error
-	cfg, err = config.FromEnv()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
-		os.Exit(1)
-	}
-	client = helpers.NewClient(cfg)
-	os.Exit(m.Run())
+func TestMain(m *testing.M) { helpers.RunSuite(m, helpers.Suite{Name: "core", Setup: setupSuite}) }
+
+// setupSuite stores the suite environment and sets up the configured
+// tenants; the tenant cleanup runs after the tests.
+func setupSuite(env *helpers.SuiteEnv) (func(), error) {
+	cfg, client = env.Config, env.Client
+	api = camunda.New(client, cfg.ZeebeGatewayURL)
+	return setupTenants(context.Background())
 }
 
 // TestM2MTokenGeneration verifies that machine-to-machine tokens can be obtained
 // from Keycloak for each Camunda component that requires one.
 func TestM2MTokenGeneration(t *testing.T) {
+	helpers.Track(t)
 	if cfg.AuthMode != "oidc" {
-		t.Skip("M2M token test requires OIDC auth mode")
+		helpers.Skip(t, "M2M token test requires OIDC auth mode")
 	}
 
 	tokenURL := cfg.KeycloakTokenURL()
@@ -53,57 +55,48 @@ func TestM2MTokenGeneration(t *testing.T) {
 
 // TestOrchestrationTopology checks the Zeebe cluster topology via REST API.
 func TestOrchestrationTopology(t *testing.T) {
-	url := cfg.ZeebeGatewayURL + "/v2/topology"
-
-	err := helpers.Retry(cfg.RetryAttempts, cfg.RetryDelay, func() error {
-		resp, err := client.Get(url)
+	helpers.Track(t)
+	var topology *camunda.Topology
+	err := helpers.ConstantRetry(cfg.RetryAttempts, cfg.RetryDelay).Do(t.Context(), t, func() error {
+		var err error
+		topology, err = api.Topology(t.Context())
 		if err != nil {
 			return fmt.Errorf("topology request failed: %w", err)
 		}
-		body, err := helpers.ReadBody(resp)
-		if err != nil {
-			return fmt.Errorf("reading body failed: %w", err)
-		}
-		if resp.StatusCode != http.StatusOK {
-			return fmt.Errorf("expected 200, got %d: %s", resp.StatusCode, body)
-		}
-
-		var topology map[string]interface{}
-		if err := json.Unmarshal([]byte(body), &topology); err != nil {
-			return fmt.Errorf("invalid JSON: %w", err)
+		if len(topology.Brokers) == 0 {
+			return fmt.Errorf("topology reports no brokers")
 		}
-
-		if _, ok := topology["brokers"]; !ok {
-			return fmt.Errorf("topology response missing 'brokers' key")
+		if len(topology.Brokers) != topology.ClusterSize {
+			return fmt.Errorf("%d of %d brokers joined", len(topology.Brokers), topology.ClusterSize)
 		}
 		return nil
 	})
 	require.NoError(t, err, "should get valid topology")
+	assert.NotEmpty(t, topology.GatewayVersion, "topology should report the gateway version")
 }
 
-// TestOrchestrationProcessDefinitionSearch checks the process definition search API.
+// TestOrchestrationProcessDefinitionSearch checks the process definition
+// search API, once per tenant when TEST_TENANTS is set.
 func TestOrchestrationProcessDefinitionSearch(t *testing.T) {
-	url := cfg.ZeebeGatewayURL + "/v2/process-definitions/search"
-	body := `{"filter":{}}`
-
-	err := helpers.Retry(cfg.RetryAttempts, cfg.RetryDelay, func() error {
-		resp, err := client.PostJSON(url, body)
-		if err != nil {
-			return fmt.Errorf("search request failed: %w", err)
-		}
-		defer resp.Body.Close()
-		if resp.StatusCode != http.StatusOK {
-			return fmt.Errorf("expected 200, got %d", resp.StatusCode)
-		}
-		return nil
+	helpers.Track(t)
+	forEachTenant(t, func(t *testing.T, tenantID string) {
+		filter := camunda.ProcessDefinitionFilter{TenantID: tenantID}
+		err := helpers.ConstantRetry(cfg.RetryAttempts, cfg.RetryDelay).Do(t.Context(), t, func() error {
+			if _, err := api.SearchProcessDefinitions(t.Context(), filter, camunda.Page{}); err != nil {
+				return fmt.Errorf("search request failed: %w", err)
+			}
+			return nil
+		})
+		require.NoError(t, err, "process definition search should succeed")
 	})
-	require.NoError(t, err, "process definition search should succeed")
 }
 
-// TestDeployAndVerifyProcess deploys a BPMN process and verifies it's searchable.
+// TestDeployAndVerifyProcess deploys a BPMN process and verifies it's
+// searchable, once per tenant when TEST_TENANTS is set.
 func TestDeployAndVerifyProcess(t *testing.T) {
-	if cfg.AuthMode != "oidc" && cfg.AuthMode != "basic" {
-		t.Skip("process deployment requires authentication")
+	helpers.Track(t)
+	if !cfg.Authenticated() {
+		helpers.Skip(t, "process deployment requires authentication")
 	}
 
 	cases := []struct {
@@ -114,114 +107,63 @@ func TestDeployAndVerifyProcess(t *testing.T) {
 		{
 			name:      "Basic",
 			processID: "integration-test-process",
-			bpmn:      basicProcessBPMN("integration-test-process"),
+			bpmn:      fixtures.BasicProcessBPMN("integration-test-process"),
 		},
 		{
 			// Mirrors the venom "TEST - Deploy Inbound Connector Process"
 			// step. Validates that the engine accepts a BPMN that references
 			// an inbound connector type (deployment-time validation only;
-			// the connector worker itself is not exercised here).
+			// TestInboundWebhookConnector exercises the connectors runtime).
 			name:      "InboundConnector",
 			processID: "integration-test-inbound-connector",
-			bpmn:      inboundConnectorBPMN("integration-test-inbound-connector"),
+			bpmn:      fixtures.InboundConnectorBPMN("integration-test-inbound-connector"),
 		},
 	}
 
-	for _, tc := range cases {
-		tc := tc
-		t.Run(tc.name, func(t *testing.T) {
-			deployAndVerify(t, tc.processID, tc.bpmn)
-		})
-	}
+	forEachTenant(t, func(t *testing.T, tenantID string) {
+		for _, tc := range cases {
+			tc := tc
+			t.Run(tc.name, func(t *testing.T) {
+				deployAndVerify(t, tenantID, tc.processID, tc.bpmn)
+			})
+		}
+	})
 }
 
-func deployAndVerify(t *testing.T, processID, bpmn string) {
+func deployAndVerify(t *testing.T, tenantID, processID, bpmn string) {
 	t.Helper()
 
-	// Deploy via multipart form
 	t.Run("Deploy", func(t *testing.T) {
-		deployURL := cfg.ZeebeGatewayURL + "/v2/deployments"
-
-		// Build multipart body once — it's cheap to rebuild on retry.
-		var b strings.Builder
-		boundary := "----TestBoundary"
-		b.WriteString("--" + boundary + "\r\n")
-		b.WriteString("Content-Disposition: form-data; name=\"resources\"; filename=\"test-process.bpmn\"\r\n")
-		b.WriteString("Content-Type: application/octet-stream\r\n\r\n")
-		b.WriteString(bpmn)
-		b.WriteString("\r\n--" + boundary + "--\r\n")
-		body := b.String()
+		resource := camunda.Resource{Name: "test-process.bpmn", Content: []byte(bpmn)}
 
 		// Retry on transient 5xx (e.g. JWT JWK-set fetch timeouts when
 		// Identity/Keycloak briefly hiccups between deploys — observed on
-		// ROSA HCP). 4xx are returned without retry.
-		var lastBody string
-		var lastStatus int
-		err := helpers.Retry(5, cfg.RetryDelay, func() error {
-			req, err := http.NewRequest(http.MethodPost, deployURL, strings.NewReader(body))
-			if err != nil {
-				return err
-			}
-			req.Header.Set("Content-Type", "multipart/form-data; boundary="+boundary)
-			req.Header.Set("Accept", "application/json")
-
-			resp, err := client.Do(req)
-			if err != nil {
-				return err
-			}
-			respBody, err := helpers.ReadBody(resp)
-			if err != nil {
-				return err
-			}
-			lastBody = respBody
-			lastStatus = resp.StatusCode
-			if resp.StatusCode == http.StatusOK {
-				return nil
-			}
-			// Only retry on transient server errors; surface 4xx immediately.
-			if resp.StatusCode >= 500 {
-				return fmt.Errorf("deploy returned %d: %s", resp.StatusCode, respBody)
-			}
-			return nil
+		// ROSA HCP). 4xx are permanent and fail on the first attempt.
+		var deployment *camunda.Deployment
+		err := helpers.ConstantRetry(5, cfg.RetryDelay).Do(t.Context(), t, func() error {
+			var err error
+			deployment, err = api.Deploy(t.Context(), tenantID, resource)
+			return err
 		})
-		require.NoError(t, err, "deploy should succeed after retries: %s", lastBody)
-		require.Equal(t, http.StatusOK, lastStatus, "deploy should succeed: %s", lastBody)
-
-		var result map[string]interface{}
-		require.NoError(t, json.Unmarshal([]byte(lastBody), &result))
-
-		deployments, ok := result["deployments"].([]interface{})
-		require.True(t, ok, "response should have deployments array")
-		require.NotEmpty(t, deployments, "deployments should not be empty")
+		require.NoError(t, err, "deploy should succeed")
+
+		processes := deployment.Processes()
+		require.Len(t, processes, 1, "deployment should contain exactly one process")
+		assert.Equal(t, processID, processes[0].ProcessDefinitionID)
+		assert.NotEmpty(t, processes[0].ProcessDefinitionKey)
+		if tenantID != "" {
+			assert.Equal(t, tenantID, deployment.TenantID, "deployment tenant")
+		}
 	})
 
 	// Verify the process is searchable
 	t.Run("VerifyDeployed", func(t *testing.T) {
-		searchURL := cfg.ZeebeGatewayURL + "/v2/process-definitions/search"
-		searchBody := fmt.Sprintf(`{"filter":{"processDefinitionId":"%s"}}`, processID)
-
-		err := helpers.Retry(5, cfg.RetryDelay, func() error {
-			resp, err := client.PostJSON(searchURL, searchBody)
+		filter := camunda.ProcessDefinitionFilter{ProcessDefinitionID: processID, TenantID: tenantID}
+		err := helpers.ConstantRetry(5, cfg.RetryDelay).Do(t.Context(), t, func() error {
+			result, err := api.SearchProcessDefinitions(t.Context(), filter, camunda.Page{})
 			if err != nil {
 				return err
 			}
-			body, err := helpers.ReadBody(resp)
-			if err != nil {
-				return err
-			}
-			if resp.StatusCode != http.StatusOK {
-				return fmt.Errorf("search returned %d: %s", resp.StatusCode, body)
-			}
-
-			var result struct {
-				Items []struct {
-					ProcessDefinitionID string `json:"processDefinitionId"`
-				} `json:"items"`
-			}
-			if err := json.Unmarshal([]byte(body), &result); err != nil {
-				return fmt.Errorf("invalid JSON: %w", err)
-			}
-
 			for _, item := range result.Items {
 				if item.ProcessDefinitionID == processID {
 					return nil
@@ -235,8 +177,9 @@ func deployAndVerify(t *testing.T, processID, bpmn string) {
 
 // TestLoginPages checks that key Camunda web UIs return HTTP 200 and no error.
 func TestLoginPages(t *testing.T) {
+	helpers.Track(t)
 	if !cfg.HasDomain() {
-		t.Skip("login page tests require domain/ingress")
+		helpers.Skip(t, "login page tests require domain/ingress")
 	}
 
 	pages := []struct {
@@ -244,7 +187,7 @@ func TestLoginPages(t *testing.T) {
 		url  string
 		skip bool
 	}{
-		{name: "Keycloak", url: cfg.KeycloakURL, skip: cfg.AuthMode != "oidc"},
+		{name: "Keycloak", url: cfg.KeycloakURL, skip: !cfg.UsesOIDC()},
 		{name: "Optimize", url: cfg.OptimizeURL, skip: !cfg.OptimizeEnabled},
 		{name: "WebModeler", url: cfg.WebModelerURL, skip: !cfg.HubEnabled},
 	}
@@ -252,14 +195,14 @@ func TestLoginPages(t *testing.T) {
 	for _, p := range pages {
 		t.Run(p.name, func(t *testing.T) {
 			if p.skip {
-				t.Skipf("%s is not enabled", p.name)
+				helpers.Skipf(t, "%s is not enabled", p.name)
 			}
 
-			err := helpers.Retry(cfg.RetryAttempts, cfg.RetryDelay, func() error {
+			err := helpers.ConstantRetry(cfg.RetryAttempts, cfg.RetryDelay).Do(t.Context(), t, func() error {
 				// Login pages are public; do NOT attach a bearer token. SPA
 				// ingresses (e.g. WebModeler) reject M2M tokens with 401
 				// because the token has no user scope.
-				resp, err := client.GetUnauth(p.url)
+				resp, err := client.GetUnauthContext(t.Context(), p.url)
 				if err != nil {
 					return fmt.Errorf("GET %s failed: %w", p.url, err)
 				}
@@ -291,29 +234,18 @@ func TestLoginPages(t *testing.T) {
 
 // TestOrchestrationBasicAuth checks topology endpoint with basic auth.
 func TestOrchestrationBasicAuth(t *testing.T) {
+	helpers.Track(t)
 	if cfg.AuthMode != "basic" {
-		t.Skip("basic auth test only runs in basic auth mode")
+		helpers.Skip(t, "basic auth test only runs in basic auth mode")
 	}
 
-	url := cfg.ZeebeGatewayURL + "/v2/topology"
-	err := helpers.Retry(cfg.RetryAttempts, cfg.RetryDelay, func() error {
-		resp, err := client.Get(url)
-		if err != nil {
-			return err
-		}
-		body, err := helpers.ReadBody(resp)
+	err := helpers.ConstantRetry(cfg.RetryAttempts, cfg.RetryDelay).Do(t.Context(), t, func() error {
+		topology, err := api.Topology(t.Context())
 		if err != nil {
 			return err
 		}
-		if resp.StatusCode != http.StatusOK {
-			return fmt.Errorf("expected 200, got %d: %s", resp.StatusCode, body)
-		}
-		var topology map[string]interface{}
-		if err := json.Unmarshal([]byte(body), &topology); err != nil {
-			return fmt.Errorf("invalid JSON: %w", err)
-		}
-		if _, ok := topology["brokers"]; !ok {
-			return fmt.Errorf("missing 'brokers' key in topology response")
+		if len(topology.Brokers) == 0 {
+			return fmt.Errorf("topology reports no brokers")
 		}
 		return nil
 	})
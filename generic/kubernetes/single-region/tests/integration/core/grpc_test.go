@@ -1,62 +1,100 @@
 package core
 
 import (
-	"crypto/tls"
+	"errors"
 	"fmt"
-	"net"
-	"strings"
+	"sort"
 	"testing"
-	"time"
 
-	"github.com/camunda/camunda-deployment-references/tests/integration/helpers"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/camunda"
+	"github.com/camunda/camunda-deployment-references/tests/integration/helpers"
 )
 
-// TestOrchestrationGRPC validates that the Zeebe gateway gRPC endpoint is
-// reachable over TLS and negotiates HTTP/2 (the transport gRPC requires).
-// Mirrors the venom "TEST - Orchestration Keycloak Auth" sub-step that ran
-// `zbctl status` over gRPC.
-//
-// We deliberately avoid pulling in a full Zeebe gRPC client to keep this
-// module dependency-free; verifying the TLS+ALPN handshake catches the same
-// class of regressions (ingress wiring, certificate, port exposure) that
-// `zbctl status` did, without exercising Zeebe internals already covered by
-// the REST topology test.
+// TestOrchestrationGRPC calls the Zeebe gateway over gRPC with the suite's
+// credentials: the standard grpc.health.v1 service and the gateway Topology
+// RPC, whose result must match the REST /v2/topology. Mirrors the venom
+// "TEST - Orchestration Keycloak Auth" sub-step that ran `zbctl status` over
+// gRPC, but also catches an ingress routing to the wrong backend or
+// rejecting the token, which a bare TLS/ALPN handshake would not.
 func TestOrchestrationGRPC(t *testing.T) {
+	helpers.Track(t)
 	if cfg.DomainGRPC == "" {
-		t.Skip("CAMUNDA_DOMAIN_GRPC not set; skipping gRPC connectivity check")
+		helpers.Skip(t, "CAMUNDA_DOMAIN_GRPC not set; skipping gRPC check")
 	}
 
-	addr := cfg.DomainGRPC
-	if !strings.Contains(addr, ":") {
-		addr += ":443"
-	}
-	host := addr
-	if h, _, err := net.SplitHostPort(addr); err == nil {
-		host = h
-	}
+	g, err := camunda.NewGRPC(client, cfg.DomainGRPC)
+	require.NoError(t, err)
 
-	dialer := &net.Dialer{Timeout: 10 * time.Second}
-
-	// Retry the dial + ALPN handshake: right after deployment the public gRPC
-	// hostname may not resolve yet (external-DNS / ingress propagation lag),
-	// which otherwise fails the very first attempt with "no such host". Mirror
-	// the retry/warmup the REST topology test already uses.
-	err := helpers.Retry(cfg.RetryAttempts, cfg.RetryDelay, func() error {
-		conn, err := tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{
-			ServerName: host,
-			MinVersion: tls.VersionTLS12,
-			NextProtos: []string{"h2"},
+	// Right after deployment the public gRPC hostname may not resolve yet
+	// (external-DNS / ingress propagation lag), so transport errors are
+	// retried; auth and routing failures are not.
+	retry := helpers.ConstantRetry(cfg.RetryAttempts, cfg.RetryDelay)
+
+	t.Run("Health", func(t *testing.T) {
+		err := retry.Do(t.Context(), t, func() error {
+			status, err := g.Health(t.Context(), "")
+			if err != nil {
+				return grpcRetryable(err)
+			}
+			if status != camunda.HealthServing {
+				return fmt.Errorf("gateway health is %s", status)
+			}
+			return nil
 		})
-		if err != nil {
-			return fmt.Errorf("TLS dial to %s failed: %w", addr, err)
+		var se *camunda.GRPCStatusError
+		if errors.As(err, &se) && se.Code == camunda.GRPCUnimplemented {
+			helpers.Skip(t, "gateway does not expose grpc.health.v1")
 		}
-		defer conn.Close()
+		require.NoError(t, err, "gRPC health check on %s should report SERVING", cfg.DomainGRPC)
+	})
 
-		if proto := conn.ConnectionState().NegotiatedProtocol; proto != "h2" {
-			return fmt.Errorf("gRPC requires HTTP/2: ingress must negotiate ALPN h2 (got %q)", proto)
-		}
-		return nil
+	t.Run("Topology", func(t *testing.T) {
+		var viaGRPC, viaREST *camunda.Topology
+		err := retry.Do(t.Context(), t, func() error {
+			var err error
+			if viaGRPC, err = g.Topology(t.Context()); err != nil {
+				return grpcRetryable(err)
+			}
+			if viaREST, err = api.Topology(t.Context()); err != nil {
+				return err
+			}
+			return nil
+		})
+		require.NoError(t, err, "gRPC and REST topology requests should succeed")
+
+		assert.Equal(t, viaREST.ClusterSize, viaGRPC.ClusterSize, "clusterSize")
+		assert.Equal(t, viaREST.PartitionsCount, viaGRPC.PartitionsCount, "partitionsCount")
+		assert.Equal(t, viaREST.ReplicationFactor, viaGRPC.ReplicationFactor, "replicationFactor")
+		assert.Equal(t, viaREST.GatewayVersion, viaGRPC.GatewayVersion, "gatewayVersion")
+		// Leadership may move between the two calls; replica placement may not.
+		assert.Equal(t, replicaPlacement(viaREST), replicaPlacement(viaGRPC), "partitions hosted per broker")
 	})
-	require.NoError(t, err, "gRPC endpoint %s should be reachable over TLS negotiating ALPN h2", addr)
+}
+
+// grpcRetryable marks gRPC statuses that will not fix themselves (bad
+// credentials, missing service) as permanent so the retry stops early.
+func grpcRetryable(err error) error {
+	var se *camunda.GRPCStatusError
+	if errors.As(err, &se) && se.Code != camunda.GRPCUnavailable {
+		return helpers.Permanent(err)
+	}
+	return err
+}
+
+// replicaPlacement maps each broker node ID to the sorted partition IDs it
+// hosts.
+func replicaPlacement(t *camunda.Topology) map[int][]int {
+	out := map[int][]int{}
+	for _, b := range t.Brokers {
+		ids := []int{}
+		for _, p := range b.Partitions {
+			ids = append(ids, p.PartitionID)
+		}
+		sort.Ints(ids)
+		out[b.NodeID] = ids
+	}
+	return out
 }
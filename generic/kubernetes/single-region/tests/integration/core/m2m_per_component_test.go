@@ -2,11 +2,12 @@ package core
 
 import (
 	"encoding/json"
-	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/helpers"
 )
 
 // TestM2MTokenPerComponent validates that a client_credentials token can be
@@ -14,17 +15,18 @@ import (
 // "TEST - Generating M2M Token" suite that iterated over connectors / optimize
 // / orchestration clients.
 //
-// The set of components is provided via the TEST_OIDC_M2M_CLIENTS env var as
-// a JSON object: {"connectors":"<secret>","optimize":"<secret>", ...}
+// The set of components is provided via the TEST_OIDC_M2M_CLIENTS key (env
+// var or profile) as a JSON object: {"connectors":"<secret>", ...}
 // The map key is used as the OIDC client_id.
 func TestM2MTokenPerComponent(t *testing.T) {
+	helpers.Track(t)
 	if cfg.AuthMode != "oidc" {
-		t.Skip("M2M token test requires OIDC auth mode")
+		helpers.Skip(t, "M2M token test requires OIDC auth mode")
 	}
 
-	raw := os.Getenv("TEST_OIDC_M2M_CLIENTS")
+	raw := cfg.OIDCM2MClients
 	if raw == "" {
-		t.Skip("TEST_OIDC_M2M_CLIENTS not set; skipping per-component M2M check")
+		helpers.Skip(t, "TEST_OIDC_M2M_CLIENTS not set; skipping per-component M2M check")
 	}
 
 	var clients map[string]string
@@ -36,7 +38,7 @@ func TestM2MTokenPerComponent(t *testing.T) {
 		clientID, secret := clientID, secret
 		t.Run(clientID, func(t *testing.T) {
 			if secret == "" {
-				t.Skipf("no secret provided for client %q", clientID)
+				helpers.Skipf(t, "no secret provided for client %q", clientID)
 			}
 			token, err := client.GetTokenForClient(tokenURL, clientID, secret)
 			require.NoError(t, err, "should obtain token for client %q", clientID)
@@ -0,0 +1,133 @@
+package core
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/camunda"
+	"github.com/camunda/camunda-deployment-references/tests/integration/camunda/fixtures"
+	"github.com/camunda/camunda-deployment-references/tests/integration/helpers"
+)
+
+const (
+	optimizeProcessID = "integration-test-optimize"
+
+	// Audiences the Optimize and Web Modeler public APIs require in the
+	// token.
+	optimizeAudience   = "optimize-api"
+	webModelerAudience = "web-modeler-public-api"
+
+	// optimizeImportTimeout bounds the wait for Optimize to import a freshly
+	// deployed definition from secondary storage; its importer runs on an
+	// interval and starts from scratch on an empty installation.
+	optimizeImportTimeout = 5 * time.Minute
+)
+
+// usesJWT reports whether requests carry a JWT bearer token, which the
+// Optimize and Web Modeler public APIs require.
+func usesJWT() bool {
+	return cfg.UsesOIDC() || cfg.AuthMode == "bearer"
+}
+
+// skipUnlessPublicAPI skips t unless the public API tests of component are
+// enabled and the suite sends a JWT.
+func skipUnlessPublicAPI(t *testing.T, component string) {
+	t.Helper()
+	if !cfg.PublicAPIEnabled {
+		helpers.Skipf(t, "the %s public API tests are disabled; set TEST_PUBLIC_API_ENABLED=true with an M2M client allowed to call it", component)
+	}
+	if !usesJWT() {
+		helpers.Skipf(t, "the %s public API requires a JWT; auth mode is %s", component, cfg.AuthMode)
+	}
+}
+
+// requireAccepted fails t with the audience the public API of component
+// checks when it answers 401 or 403: the tests only run once the user opted
+// in, so a rejected token is a misconfigured client, not a reason to skip.
+func requireAccepted(t *testing.T, component, audience string, err error) {
+	t.Helper()
+	var se *helpers.StatusError
+	if errors.As(err, &se) && (se.StatusCode == http.StatusUnauthorized || se.StatusCode == http.StatusForbidden) {
+		require.Failf(t, "public API rejected the token", "%s answered %d; the suite's client needs the %s audience in its token: %v", component, se.StatusCode, audience, err)
+	}
+}
+
+// skipIfUnresolvable skips t when err is a DNS failure: in no-domain mode
+// the satellite components default to in-cluster hostnames the runner can
+// only reach through a port-forward.
+func skipIfUnresolvable(t *testing.T, name, url string, err error) {
+	t.Helper()
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		helpers.Skipf(t, "%s URL %s is not resolvable from the runner: %v", name, url, err)
+	}
+}
+
+// TestOptimizePublicAPI calls the Optimize public API with the suite's M2M
+// token: report and dashboard exports must be authorised, and a process
+// deployed and started here must eventually be imported by Optimize.
+func TestOptimizePublicAPI(t *testing.T) {
+	helpers.Track(t)
+	if !cfg.OptimizeEnabled {
+		helpers.Skip(t, "Optimize is not enabled")
+	}
+	skipUnlessPublicAPI(t, "Optimize")
+	optimize := camunda.NewOptimize(client, cfg.OptimizeURL)
+
+	t.Run("ExportReports", func(t *testing.T) {
+		var exported []camunda.ExportedEntity
+		err := helpers.DeadlineRetry(componentReadyTimeout, componentReadyPollInterval).Do(t.Context(), t, func() error {
+			var err error
+			exported, err = optimize.ExportReports(t.Context())
+			return err
+		})
+		skipIfUnresolvable(t, "Optimize", cfg.OptimizeURL, err)
+		requireAccepted(t, "Optimize", optimizeAudience, err)
+		require.NoError(t, err, "report export should accept the M2M token")
+		assert.Empty(t, exported, "exporting no report IDs should return an empty export")
+	})
+
+	t.Run("ExportDashboards", func(t *testing.T) {
+		_, err := optimize.ExportDashboards(t.Context())
+		skipIfUnresolvable(t, "Optimize", cfg.OptimizeURL, err)
+		requireAccepted(t, "Optimize", optimizeAudience, err)
+		require.NoError(t, err, "dashboard export should accept the M2M token")
+	})
+
+	t.Run("ProcessImported", func(t *testing.T) {
+		if !cfg.Authenticated() {
+			helpers.Skip(t, "process deployment requires authentication")
+		}
+		resource := camunda.Resource{Name: "optimize.bpmn", Content: []byte(fixtures.BasicProcessBPMN(optimizeProcessID))}
+		err := helpers.ConstantRetry(5, cfg.RetryDelay).Do(t.Context(), t, func() error {
+			_, err := api.Deploy(t.Context(), "", resource)
+			return err
+		})
+		require.NoError(t, err, "deploy should succeed")
+		_, err = api.CreateProcessInstance(t.Context(), camunda.CreateProcessInstanceRequest{
+			ProcessDefinitionID: optimizeProcessID,
+			Variables:           map[string]interface{}{"source": "integration-test"},
+		})
+		require.NoError(t, err, "starting an instance should succeed")
+
+		label := camunda.VariableLabel{VariableName: "source", VariableType: "String", VariableLabel: "Source"}
+		policy := helpers.DeadlineRetry(optimizeImportTimeout, 10*time.Second)
+		policy.Retryable = func(err error) bool {
+			return errors.Is(err, camunda.ErrNotImported) || helpers.IsRetryable(err)
+		}
+		start := time.Now()
+		err = policy.Do(t.Context(), t, func() error {
+			return optimize.LabelVariables(t.Context(), optimizeProcessID, label)
+		})
+		skipIfUnresolvable(t, "Optimize", cfg.OptimizeURL, err)
+		requireAccepted(t, "Optimize", optimizeAudience, err)
+		require.NoError(t, err, "Optimize should import %s within %s", optimizeProcessID, optimizeImportTimeout)
+		t.Logf("Optimize imported %s after %s", optimizeProcessID, time.Since(start).Round(time.Second))
+	})
+}
@@ -0,0 +1,151 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/camunda"
+	"github.com/camunda/camunda-deployment-references/tests/integration/camunda/fixtures"
+	"github.com/camunda/camunda-deployment-references/tests/integration/helpers"
+)
+
+const e2eProcessID = "integration-test-service-task"
+
+// TestProcessExecution runs the full workflow path: deploy a process with a
+// service task, start TEST_E2E_INSTANCES instances, activate and complete
+// their jobs through the REST job API and wait until every instance is
+// COMPLETED in search. Reaching COMPLETED in search proves the broker
+// processed the jobs and the exporter wrote the result to secondary storage;
+// the whole round trip must stay under TEST_E2E_MAX_LATENCY. Runs once per
+// tenant when TEST_TENANTS is set.
+func TestProcessExecution(t *testing.T) {
+	helpers.Track(t)
+	if !cfg.Authenticated() {
+		helpers.Skip(t, "process execution requires authentication")
+	}
+	if cfg.SecondaryStorage == "none" {
+		helpers.Skip(t, "process execution check requires secondary storage for search")
+	}
+	forEachTenant(t, runProcessExecution)
+}
+
+func runProcessExecution(t *testing.T, tenantID string) {
+	// A job type unique to this run keeps jobs of instances left behind by
+	// an earlier, aborted run from being activated here.
+	runID := fmt.Sprintf("%d", time.Now().UnixNano())
+	jobType := "integration-test-e2e-" + runID
+
+	var definitionKey string
+	resource := camunda.Resource{Name: "service-task.bpmn", Content: []byte(fixtures.ServiceTaskBPMN(e2eProcessID, jobType))}
+	err := helpers.ConstantRetry(5, cfg.RetryDelay).Do(t.Context(), t, func() error {
+		deployment, err := api.Deploy(t.Context(), tenantID, resource)
+		if err != nil {
+			return err
+		}
+		processes := deployment.Processes()
+		if len(processes) != 1 {
+			return helpers.Permanent(fmt.Errorf("deployment contains %d processes, want 1", len(processes)))
+		}
+		definitionKey = processes[0].ProcessDefinitionKey
+		return nil
+	})
+	require.NoError(t, err, "deploy should succeed")
+
+	start := time.Now()
+	instances := map[string]int{} // processInstanceKey -> index
+	for i := 0; i < cfg.E2EInstances; i++ {
+		created, err := api.CreateProcessInstance(t.Context(), camunda.CreateProcessInstanceRequest{
+			ProcessDefinitionKey: definitionKey,
+			Variables:            map[string]interface{}{"runId": runID, "index": i},
+			TenantID:             tenantID,
+		})
+		require.NoError(t, err, "starting instance %d should succeed", i)
+		instances[created.ProcessInstanceKey] = i
+	}
+	t.Logf("started %d instances of %s (key %s)", len(instances), e2eProcessID, definitionKey)
+
+	ctx, cancel := context.WithTimeout(t.Context(), cfg.E2EMaxLatency)
+	defer cancel()
+
+	t.Run("CompleteJobs", func(t *testing.T) {
+		completed := completeJobs(t, ctx, tenantID, jobType, instances)
+		assert.Len(t, completed, len(instances), "every instance should have produced one job")
+	})
+
+	t.Run("CompletedInSearch", func(t *testing.T) {
+		err := helpers.DeadlineRetry(time.Until(start.Add(cfg.E2EMaxLatency)), 2*time.Second).Do(ctx, t, func() error {
+			done, err := camunda.Collect(ctx, 100, func(ctx context.Context, p camunda.Page) (*camunda.SearchResult[camunda.ProcessInstance], error) {
+				return api.SearchProcessInstances(ctx, camunda.ProcessInstanceFilter{
+					ProcessDefinitionKey: definitionKey,
+					State:                camunda.StateCompleted,
+					TenantID:             tenantID,
+				}, p)
+			})
+			if err != nil {
+				return err
+			}
+			seen := 0
+			for _, pi := range done {
+				if _, ok := instances[pi.ProcessInstanceKey]; ok {
+					seen++
+				}
+			}
+			if seen < len(instances) {
+				return fmt.Errorf("%d of %d instances COMPLETED in search", seen, len(instances))
+			}
+			return nil
+		})
+		latency := time.Since(start)
+		require.NoError(t, err, "all instances should be COMPLETED in search within %s", cfg.E2EMaxLatency)
+		t.Logf("end-to-end latency for %d instances: %s", len(instances), latency.Round(time.Millisecond))
+		assert.LessOrEqual(t, latency, cfg.E2EMaxLatency, "end-to-end latency")
+	})
+}
+
+// completeJobs activates jobs of jobType until one job per instance has been
+// completed or ctx expires, and returns the completed instance keys. Each job
+// must carry the variables its instance was started with.
+func completeJobs(t *testing.T, ctx context.Context, tenantID, jobType string, instances map[string]int) map[string]bool {
+	t.Helper()
+	var tenantIDs []string
+	if tenantID != "" {
+		tenantIDs = []string{tenantID}
+	}
+	completed := map[string]bool{}
+	for len(completed) < len(instances) && ctx.Err() == nil {
+		jobs, err := api.ActivateJobs(ctx, camunda.ActivateJobsRequest{
+			Type:              jobType,
+			Worker:            "integration-test",
+			Timeout:           time.Minute.Milliseconds(),
+			MaxJobsToActivate: len(instances) - len(completed),
+			FetchVariable:     []string{"runId", "index"},
+			RequestTimeout:    (10 * time.Second).Milliseconds(),
+			TenantIDs:         tenantIDs,
+		})
+		if err != nil {
+			if !helpers.IsRetryable(err) {
+				require.NoError(t, err, "job activation should succeed")
+			}
+			t.Logf("job activation failed, retrying: %v", err)
+			time.Sleep(time.Second)
+			continue
+		}
+		for _, job := range jobs {
+			index, ok := instances[job.ProcessInstanceKey]
+			if !assert.True(t, ok, "job %s belongs to unknown instance %s", job.JobKey, job.ProcessInstanceKey) {
+				continue
+			}
+			// JSON numbers decode as float64.
+			assert.EqualValues(t, index, job.Variables["index"], "job %s variables", job.JobKey)
+			err := api.CompleteJob(ctx, job.JobKey, map[string]interface{}{"result": index * 2})
+			require.NoError(t, err, "completing job %s should succeed", job.JobKey)
+			completed[job.ProcessInstanceKey] = true
+		}
+	}
+	return completed
+}
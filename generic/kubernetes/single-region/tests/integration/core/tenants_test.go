@@ -0,0 +1,262 @@
+package core
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/camunda"
+	"github.com/camunda/camunda-deployment-references/tests/integration/camunda/fixtures"
+	"github.com/camunda/camunda-deployment-references/tests/integration/config"
+	"github.com/camunda/camunda-deployment-references/tests/integration/helpers"
+)
+
+// forEachTenant runs fn once per configured tenant, each as a subtest named
+// after the tenant. Without TEST_TENANTS it runs fn directly on t with an
+// empty tenant ID, so the API applies its default and test names stay as
+// they were before tenants were supported.
+func forEachTenant(t *testing.T, fn func(t *testing.T, tenantID string)) {
+	t.Helper()
+	if len(cfg.Tenants) == 0 {
+		fn(t, "")
+		return
+	}
+	for _, tenantID := range cfg.Tenants {
+		t.Run(tenantID, func(t *testing.T) {
+			fn(t, tenantID)
+		})
+	}
+}
+
+// tenantPrincipal assigns or unassigns the suite's own principal: the OIDC
+// client in the OIDC modes, the basic-auth user otherwise (Validate rejects
+// TEST_TENANTS for any other mode).
+func tenantPrincipal(assign bool) func(ctx context.Context, tenantID string) error {
+	if cfg.UsesOIDC() {
+		if assign {
+			return func(ctx context.Context, id string) error {
+				return api.AssignClientToTenant(ctx, id, cfg.OIDCClientID)
+			}
+		}
+		return func(ctx context.Context, id string) error {
+			return api.UnassignClientFromTenant(ctx, id, cfg.OIDCClientID)
+		}
+	}
+	if assign {
+		return func(ctx context.Context, id string) error {
+			return api.AssignUserToTenant(ctx, id, cfg.BasicUser)
+		}
+	}
+	return func(ctx context.Context, id string) error {
+		return api.UnassignUserFromTenant(ctx, id, cfg.BasicUser)
+	}
+}
+
+// setupTenants creates the configured tenants that do not exist yet and
+// assigns the suite's principal to every configured tenant through the v2
+// tenant API. The returned cleanup undoes exactly what setup did: it
+// unassigns the principal where it was assigned here and deletes only the
+// tenants setup created. Called from setupSuite.
+func setupTenants(ctx context.Context) (cleanup func(), err error) {
+	var created, assigned []string
+	cleanup = func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		unassign := tenantPrincipal(false)
+		for _, id := range assigned {
+			if err := unassign(ctx, id); err != nil {
+				fmt.Fprintf(os.Stderr, "tenant cleanup: unassigning from %s: %v\n", id, err)
+			}
+		}
+		for _, id := range created {
+			if err := api.DeleteTenant(ctx, id); err != nil {
+				fmt.Fprintf(os.Stderr, "tenant cleanup: deleting %s: %v\n", id, err)
+			}
+		}
+	}
+
+	retry := helpers.ConstantRetry(cfg.RetryAttempts, cfg.RetryDelay)
+	assign := tenantPrincipal(true)
+	for _, id := range cfg.Tenants {
+		if id == config.DefaultTenantID {
+			continue
+		}
+		err := retry.Do(ctx, nil, func() error {
+			_, err := api.CreateTenant(ctx, camunda.Tenant{TenantID: id, Name: id, Description: "integration test tenant"})
+			return err
+		})
+		switch {
+		case err == nil:
+			created = append(created, id)
+		case isConflict(err):
+			// Pre-existing tenant: use it but leave it in place.
+		default:
+			cleanup()
+			return func() {}, fmt.Errorf("creating tenant %s: %w", id, err)
+		}
+
+		err = retry.Do(ctx, nil, func() error { return assign(ctx, id) })
+		switch {
+		case err == nil:
+			assigned = append(assigned, id)
+		case isConflict(err):
+			// Already a member before the run; keep the membership.
+		default:
+			cleanup()
+			return func() {}, fmt.Errorf("assigning the suite's principal to tenant %s: %w", id, err)
+		}
+	}
+	return cleanup, nil
+}
+
+func isConflict(err error) bool {
+	var se *helpers.StatusError
+	return errors.As(err, &se) && se.StatusCode == http.StatusConflict
+}
+
+// TestTenantIsolation checks that tenants are isolated for a principal that
+// belongs to only one of them. It deploys and starts a process in the first
+// configured tenant with the suite's principal (a member of every tenant),
+// then acts as an outsider assigned to the second tenant only, holding read
+// and start permissions on every process definition: without any tenant
+// filter, the outsider must neither find nor read the first tenant's
+// definition and instance, nor start the process in the first tenant.
+func TestTenantIsolation(t *testing.T) {
+	helpers.Track(t)
+	if len(cfg.Tenants) < 2 {
+		helpers.Skip(t, "tenant isolation needs at least two tenants in TEST_TENANTS")
+	}
+	owner, other := cfg.Tenants[0], cfg.Tenants[1]
+	suffix := fmt.Sprintf("%d", time.Now().UnixNano())
+	processID := "integration-test-isolation-" + suffix
+
+	outsider := tenantOutsider(t, other, suffix)
+
+	resource := camunda.Resource{Name: "isolation.bpmn", Content: []byte(fixtures.BasicProcessBPMN(processID))}
+	err := helpers.ConstantRetry(5, cfg.RetryDelay).Do(t.Context(), t, func() error {
+		_, err := api.Deploy(t.Context(), owner, resource)
+		return err
+	})
+	require.NoError(t, err, "deploy to tenant %s should succeed", owner)
+	created, err := api.CreateProcessInstance(t.Context(), camunda.CreateProcessInstanceRequest{ProcessDefinitionID: processID, TenantID: owner})
+	require.NoError(t, err, "starting %s in tenant %s should succeed", processID, owner)
+	instanceKey := created.ProcessInstanceKey
+
+	// Wait until the owner sees the instance, so the negative checks below
+	// are not passing merely because the exporter is behind.
+	err = helpers.PropagationRetry().Do(t.Context(), t, func() error {
+		_, err := api.GetProcessInstance(t.Context(), instanceKey)
+		return err
+	})
+	require.NoError(t, err, "tenant %s should see its own instance", owner)
+
+	// Positive control: the outsider does see its own tenant's processes, so
+	// the empty results below are not caused by missing permissions or a
+	// principal that has not propagated yet.
+	controlID := "integration-test-isolation-control-" + suffix
+	_, err = api.Deploy(t.Context(), other, camunda.Resource{Name: "isolation-control.bpmn", Content: []byte(fixtures.BasicProcessBPMN(controlID))})
+	require.NoError(t, err, "deploy to tenant %s should succeed", other)
+	err = helpers.PropagationRetry().Do(t.Context(), t, func() error {
+		r, err := outsider.SearchProcessDefinitions(t.Context(), camunda.ProcessDefinitionFilter{ProcessDefinitionID: controlID}, camunda.Page{})
+		if err != nil {
+			return err
+		}
+		if len(r.Items) == 0 {
+			return fmt.Errorf("process %q not yet visible to the member of %s", controlID, other)
+		}
+		return nil
+	})
+	require.NoError(t, err, "a member of %s only should see the processes of %s", other, other)
+
+	t.Run("SearchDefinitionsUnfiltered", func(t *testing.T) {
+		r, err := outsider.SearchProcessDefinitions(t.Context(), camunda.ProcessDefinitionFilter{ProcessDefinitionID: processID}, camunda.Page{})
+		require.NoError(t, err)
+		assert.Empty(t, r.Items, "a member of %s only must not see a process deployed to %s", other, owner)
+	})
+
+	t.Run("SearchInstancesUnfiltered", func(t *testing.T) {
+		r, err := outsider.SearchProcessInstances(t.Context(), camunda.ProcessInstanceFilter{ProcessDefinitionID: processID}, camunda.Page{})
+		require.NoError(t, err)
+		assert.Empty(t, r.Items, "a member of %s only must not see an instance started in %s", other, owner)
+	})
+
+	t.Run("GetInstance", func(t *testing.T) {
+		_, err := outsider.GetProcessInstance(t.Context(), instanceKey)
+		require.Error(t, err, "a member of %s only must not read instance %s of %s", other, instanceKey, owner)
+		assert.Contains(t, []int{http.StatusForbidden, http.StatusNotFound}, helpers.StatusCode(err), "expected 403 or 404, got %v", err)
+	})
+
+	t.Run("StartInOwnerTenant", func(t *testing.T) {
+		_, err := outsider.CreateProcessInstance(t.Context(), camunda.CreateProcessInstanceRequest{
+			ProcessDefinitionID: processID,
+			TenantID:            owner,
+		})
+		require.Error(t, err, "a member of %s only must not start %s in %s", other, processID, owner)
+		assert.Contains(t, []int{http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound}, helpers.StatusCode(err), "engine should reject the start: %v", err)
+	})
+}
+
+// tenantOutsider returns a client for a principal that is a member of
+// tenantID only and may read and start every process definition: a user
+// created for the test under basic auth, or the pre-provisioned
+// TEST_TENANT_ISOLATION_CLIENT_ID in the OIDC modes. Memberships,
+// authorizations and the user are removed when t ends.
+func tenantOutsider(t *testing.T, tenantID, suffix string) *camunda.Client {
+	t.Helper()
+	outsiderCfg := *cfg
+	var ownerID, ownerType string
+	switch {
+	case cfg.AuthMode == "basic":
+		ownerID, ownerType = "it-tenant-outsider-"+suffix, camunda.OwnerUser
+		password := randomPassword(t)
+		_, err := api.CreateUser(t.Context(), camunda.User{Username: ownerID, Name: ownerID, Email: ownerID + "@example.com", Password: password})
+		require.NoError(t, err, "creating user %s", ownerID)
+		t.Cleanup(func() { helpers.LogCleanup(t, "user "+ownerID, api.DeleteUser(context.Background(), ownerID)) })
+		require.NoError(t, api.AssignUserToTenant(t.Context(), tenantID, ownerID), "assigning %s to tenant %s", ownerID, tenantID)
+		t.Cleanup(func() {
+			helpers.LogCleanup(t, "membership of "+ownerID, api.UnassignUserFromTenant(context.Background(), tenantID, ownerID))
+		})
+		outsiderCfg.BasicUser, outsiderCfg.BasicPass = ownerID, password
+	case cfg.TenantIsolationClientID != "":
+		ownerID, ownerType = cfg.TenantIsolationClientID, camunda.OwnerClient
+		require.NoError(t, api.AssignClientToTenant(t.Context(), tenantID, ownerID), "assigning client %s to tenant %s", ownerID, tenantID)
+		t.Cleanup(func() {
+			helpers.LogCleanup(t, "membership of "+ownerID, api.UnassignClientFromTenant(context.Background(), tenantID, ownerID))
+		})
+		outsiderCfg.AuthMode = "oidc"
+		outsiderCfg.OIDCClientID, outsiderCfg.OIDCSecret = cfg.TenantIsolationClientID, cfg.TenantIsolationClientSecret
+	default:
+		helpers.Skipf(t, "tenant isolation needs a principal in one tenant only: set TEST_TENANT_ISOLATION_CLIENT_ID/SECRET to an OIDC client for it (auth mode %s)", cfg.AuthMode)
+	}
+
+	key, err := api.CreateAuthorization(t.Context(), camunda.Authorization{
+		OwnerID:         ownerID,
+		OwnerType:       ownerType,
+		ResourceID:      "*",
+		ResourceType:    camunda.ResourceTypeProcessDefinition,
+		PermissionTypes: []string{camunda.PermissionReadProcessDefinition, camunda.PermissionReadProcessInstance, camunda.PermissionCreateProcessInstance},
+	})
+	require.NoError(t, err, "authorizing %s %s", ownerType, ownerID)
+	t.Cleanup(func() {
+		helpers.LogCleanup(t, "authorization "+key, api.DeleteAuthorization(context.Background(), key))
+	})
+
+	return camunda.New(helpers.NewClient(&outsiderCfg), cfg.ZeebeGatewayURL)
+}
+
+func randomPassword(t *testing.T) string {
+	t.Helper()
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	require.NoError(t, err)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,82 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/camunda"
+	"github.com/camunda/camunda-deployment-references/tests/integration/camunda/fixtures"
+	"github.com/camunda/camunda-deployment-references/tests/integration/helpers"
+)
+
+// TestWebModelerAPI creates a project and a BPMN file through the Web
+// Modeler public API, reads the file back and deletes both again.
+func TestWebModelerAPI(t *testing.T) {
+	helpers.Track(t)
+	if !cfg.HubEnabled {
+		helpers.Skip(t, "Web Modeler is not enabled")
+	}
+	skipUnlessPublicAPI(t, "Web Modeler")
+	modeler := camunda.NewWebModeler(client, cfg.WebModelerURL)
+
+	var info *camunda.WebModelerInfo
+	err := helpers.DeadlineRetry(componentReadyTimeout, componentReadyPollInterval).Do(t.Context(), t, func() error {
+		var err error
+		info, err = modeler.Info(t.Context())
+		return err
+	})
+	skipIfUnresolvable(t, "WebModeler", cfg.WebModelerURL, err)
+	requireAccepted(t, "Web Modeler", webModelerAudience, err)
+	require.NoError(t, err, "Web Modeler API should accept the M2M token")
+	t.Logf("Web Modeler version %s", info.Version)
+
+	name := fmt.Sprintf("integration-test-%d", time.Now().UnixNano())
+	project, err := modeler.CreateProject(t.Context(), name)
+	require.NoError(t, err, "project creation should succeed")
+	projectDeleted := false
+	t.Cleanup(func() {
+		if !projectDeleted {
+			// t.Context is already canceled when cleanups run.
+			_ = modeler.DeleteProject(context.Background(), project.ID)
+		}
+	})
+	assert.Equal(t, name, project.Name)
+
+	content := fixtures.BasicProcessBPMN("integration-test-web-modeler")
+	file, err := modeler.CreateFile(t.Context(), camunda.NewFile{
+		Name:      "integration-test.bpmn",
+		ProjectID: project.ID,
+		Content:   content,
+		FileType:  camunda.FileTypeBPMN,
+	})
+	require.NoError(t, err, "file creation should succeed")
+	assert.Equal(t, project.ID, file.Metadata.ProjectID)
+
+	got, err := modeler.GetFile(t.Context(), file.Metadata.ID)
+	require.NoError(t, err, "created file should be readable")
+	assert.Contains(t, got.Content, `id="integration-test-web-modeler"`)
+
+	require.NoError(t, modeler.DeleteFile(t.Context(), file.Metadata.ID), "file deletion should succeed")
+	_, err = modeler.GetFile(t.Context(), file.Metadata.ID)
+	assertNotFound(t, err, "deleted file")
+
+	require.NoError(t, modeler.DeleteProject(t.Context(), project.ID), "project deletion should succeed")
+	projectDeleted = true
+	_, err = modeler.GetProject(t.Context(), project.ID)
+	assertNotFound(t, err, "deleted project")
+}
+
+func assertNotFound(t *testing.T, err error, what string) {
+	t.Helper()
+	var se *helpers.StatusError
+	if assert.True(t, errors.As(err, &se), "%s: expected a 404, got %v", what, err) {
+		assert.Equal(t, http.StatusNotFound, se.StatusCode, "%s", what)
+	}
+}
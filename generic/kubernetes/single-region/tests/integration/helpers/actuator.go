@@ -0,0 +1,176 @@
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Spring Boot health statuses.
+const (
+	StatusUp           = "UP"
+	StatusDown         = "DOWN"
+	StatusOutOfService = "OUT_OF_SERVICE"
+	StatusUnknown      = "UNKNOWN"
+)
+
+// Health is a Spring Boot actuator health document. Components and Details
+// are only present when the endpoint is configured to show them
+// (management.endpoint.health.show-components / show-details).
+type Health struct {
+	Status     string                 `json:"status"`
+	Components map[string]Health      `json:"components,omitempty"`
+	Details    map[string]interface{} `json:"details,omitempty"`
+}
+
+// HealthIndicator is one leaf of a Health tree. Path joins the component
+// names from the root, e.g. "db/secondaryStorage".
+type HealthIndicator struct {
+	Path    string
+	Status  string
+	Details map[string]interface{}
+}
+
+// Name is the last segment of the indicator path.
+func (i HealthIndicator) Name() string {
+	return i.Path[strings.LastIndex(i.Path, "/")+1:]
+}
+
+// Kind groups the indicator into the areas preflight reports on:
+// "partitions", "exporters", "secondary storage", "disk space" or "other".
+// The match is by name because indicator names differ between components
+// and versions.
+func (i HealthIndicator) Kind() string {
+	name := strings.ToLower(i.Path)
+	switch {
+	case strings.Contains(name, "partition"):
+		return "partitions"
+	case strings.Contains(name, "export"):
+		return "exporters"
+	case strings.Contains(name, "diskspace"):
+		return "disk space"
+	}
+	for _, s := range []string{"elasticsearch", "opensearch", "searchengine", "secondarystorage", "rdbms", "datasource", "db"} {
+		if strings.Contains(name, s) {
+			return "secondary storage"
+		}
+	}
+	return "other"
+}
+
+func (i HealthIndicator) String() string {
+	return fmt.Sprintf("%s=%s (%s)", i.Path, i.Status, i.Kind())
+}
+
+// Indicators flattens h into its leaf indicators, sorted by path. A document
+// without components yields nothing.
+func (h *Health) Indicators() []HealthIndicator {
+	var out []HealthIndicator
+	var walk func(prefix string, components map[string]Health)
+	walk = func(prefix string, components map[string]Health) {
+		for name, c := range components {
+			path := prefix + name
+			if len(c.Components) > 0 {
+				walk(path+"/", c.Components)
+				continue
+			}
+			out = append(out, HealthIndicator{Path: path, Status: c.Status, Details: c.Details})
+		}
+	}
+	walk("", h.Components)
+	sort.Slice(out, func(a, b int) bool { return out[a].Path < out[b].Path })
+	return out
+}
+
+// Down returns the indicators reporting DOWN or OUT_OF_SERVICE. They are
+// listed even when the overall status is UP, which happens when a status
+// aggregator or health group is configured to ignore them.
+func (h *Health) Down() []HealthIndicator {
+	var out []HealthIndicator
+	for _, i := range h.Indicators() {
+		if i.Status == StatusDown || i.Status == StatusOutOfService {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// Degraded returns the indicators that are neither UP nor DOWN/OUT_OF_SERVICE
+// (UNKNOWN or a custom status such as Zeebe's "DEGRADED").
+func (h *Health) Degraded() []HealthIndicator {
+	var out []HealthIndicator
+	for _, i := range h.Indicators() {
+		switch i.Status {
+		case StatusUp, StatusDown, StatusOutOfService:
+		default:
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// ErrNoHealthDocument means the URL answered but not with actuator health
+// JSON, e.g. an ingress serving a SPA page or the actuator not being exposed
+// on that port.
+var ErrNoHealthDocument = errors.New("response is not an actuator health document")
+
+// Health fetches baseURL/actuator/health, or the named health group
+// ("readiness", "liveness") when group is set. A 503 is not an error: Spring
+// Boot answers DOWN with 503 and the same JSON body.
+func (c *Client) Health(ctx context.Context, baseURL, group string) (*Health, error) {
+	url := strings.TrimRight(baseURL, "/") + "/actuator/health"
+	if group != "" {
+		url += "/" + group
+	}
+	resp, err := c.GetContext(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	body, err := ReadBody(resp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusServiceUnavailable {
+		return nil, NewStatusError(resp, body)
+	}
+	var h Health
+	if err := json.Unmarshal([]byte(body), &h); err != nil || h.Status == "" {
+		return nil, fmt.Errorf("GET %s: %w", url, ErrNoHealthDocument)
+	}
+	return &h, nil
+}
+
+// Info is the part of a Spring Boot /actuator/info document the suite
+// reads. Build is only present when the application packages build-info.
+type Info struct {
+	Build struct {
+		Name     string `json:"name"`
+		Artifact string `json:"artifact"`
+		Version  string `json:"version"`
+	} `json:"build"`
+}
+
+// Info fetches baseURL/actuator/info.
+func (c *Client) Info(ctx context.Context, baseURL string) (*Info, error) {
+	url := strings.TrimRight(baseURL, "/") + "/actuator/info"
+	resp, err := c.GetContext(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	body, err := ReadBody(resp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, NewStatusError(resp, body)
+	}
+	var info Info
+	if err := json.Unmarshal([]byte(body), &info); err != nil {
+		return nil, fmt.Errorf("GET %s: decoding info: %w", url, err)
+	}
+	return &info, nil
+}
@@ -0,0 +1,62 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/config"
+)
+
+func TestHealthIndicators(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/actuator/health":
+			fmt.Fprint(w, `{"status":"UP","components":{
+				"diskSpace":{"status":"UP","details":{"free":1024}},
+				"brokerStatus":{"status":"UP","components":{
+					"partition-1":{"status":"UP"},
+					"partition-2":{"status":"DOWN"}}},
+				"exporters":{"status":"DEGRADED"},
+				"searchEngine":{"status":"OUT_OF_SERVICE"}}}`)
+		case "/actuator/health/readiness":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, `{"status":"DOWN"}`)
+		default:
+			fmt.Fprint(w, `<html>modeler</html>`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	c := NewClient(&config.Config{AuthMode: "none", HTTPTimeout: 5 * time.Second})
+
+	h, err := c.Health(t.Context(), srv.URL, "")
+	require.NoError(t, err)
+	assert.Equal(t, StatusUp, h.Status)
+
+	var paths []string
+	for _, i := range h.Indicators() {
+		paths = append(paths, i.Path)
+	}
+	assert.Equal(t, []string{"brokerStatus/partition-1", "brokerStatus/partition-2", "diskSpace", "exporters", "searchEngine"}, paths)
+
+	down := h.Down()
+	require.Len(t, down, 2)
+	assert.Equal(t, "brokerStatus/partition-2=DOWN (partitions)", down[0].String())
+	assert.Equal(t, "searchEngine=OUT_OF_SERVICE (secondary storage)", down[1].String())
+	require.Len(t, h.Degraded(), 1)
+	assert.Equal(t, "exporters", h.Degraded()[0].Kind())
+
+	h, err = c.Health(t.Context(), srv.URL, "readiness")
+	require.NoError(t, err, "503 with a health body is a DOWN document, not an error")
+	assert.Equal(t, StatusDown, h.Status)
+	assert.Empty(t, h.Indicators())
+
+	_, err = c.Health(t.Context(), srv.URL+"/modeler", "")
+	assert.True(t, errors.Is(err, ErrNoHealthDocument))
+}
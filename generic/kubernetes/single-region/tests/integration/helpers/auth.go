@@ -0,0 +1,311 @@
+package helpers
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/config"
+)
+
+// Authenticator attaches credentials to an outgoing request. Client.Do looks
+// the implementation up by Config.AuthMode, so new schemes only need a
+// RegisterAuthenticator call.
+type Authenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// Invalidator is implemented by authenticators whose credentials can be
+// rejected before they expire (bearer tokens). After a 401, Do calls
+// Invalidate with the rejected request, re-authenticates and retries once.
+type Invalidator interface {
+	Invalidate(req *http.Request)
+}
+
+// TLSConfigurer is implemented by authenticators that act at the transport
+// layer. NewClient calls ConfigureTLS on the client's TLS config once.
+type TLSConfigurer interface {
+	ConfigureTLS(tc *tls.Config) error
+}
+
+// AuthenticatorFactory builds the Authenticator for c's config. Factories
+// may use c.TokenSource to share the client's per-client token cache.
+type AuthenticatorFactory func(c *Client) (Authenticator, error)
+
+var authenticators = map[string]AuthenticatorFactory{
+	"none":              func(*Client) (Authenticator, error) { return noAuth{}, nil },
+	"basic":             newBasicAuth,
+	"oidc":              newClientSecretAuth,
+	"client-secret-jwt": newClientSecretJWTAuth,
+	"private-key-jwt":   newPrivateKeyJWTAuth,
+	"mtls":              newMTLSAuth,
+	"bearer":            newBearerAuth,
+}
+
+// RegisterAuthenticator makes name selectable through TEST_AUTH_MODE. It also
+// registers name with config so Validate accepts it; pass a config.AuthCheck
+// to have Validate check the settings the authenticator needs.
+func RegisterAuthenticator(name string, f AuthenticatorFactory, check config.AuthCheck) {
+	authenticators[name] = f
+	config.RegisterAuthMode(name, check)
+}
+
+func newAuthenticator(c *Client) (Authenticator, error) {
+	f, ok := authenticators[c.cfg.AuthMode]
+	if !ok {
+		return nil, fmt.Errorf("no authenticator registered for auth mode %q", c.cfg.AuthMode)
+	}
+	return f(c)
+}
+
+// oidcParams returns the optional audience/scope token request parameters.
+func oidcParams(cfg *config.Config) url.Values {
+	v := url.Values{}
+	if cfg.OIDCAudience != "" {
+		v.Set("audience", cfg.OIDCAudience)
+	}
+	if cfg.OIDCScope != "" {
+		v.Set("scope", cfg.OIDCScope)
+	}
+	return v
+}
+
+type noAuth struct{}
+
+func (noAuth) Authenticate(*http.Request) error { return nil }
+
+type basicAuth struct{ user, pass string }
+
+func newBasicAuth(c *Client) (Authenticator, error) {
+	return basicAuth{user: c.cfg.BasicUser, pass: c.cfg.BasicPass}, nil
+}
+
+func (a basicAuth) Authenticate(req *http.Request) error {
+	req.SetBasicAuth(a.user, a.pass)
+	return nil
+}
+
+// tokenAuth sends a bearer token from a TokenSource and drops it on 401.
+type tokenAuth struct{ ts *TokenSource }
+
+func (a tokenAuth) Authenticate(req *http.Request) error {
+	token, err := a.ts.Token()
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+func (a tokenAuth) Invalidate(req *http.Request) {
+	a.ts.Invalidate(strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer "))
+}
+
+// newClientSecretAuth is the client_credentials grant with the secret in the
+// form body. Without audience/scope it shares the token cache used by
+// GetTokenForClient.
+func newClientSecretAuth(c *Client) (Authenticator, error) {
+	params := oidcParams(c.cfg)
+	if len(params) == 0 {
+		return tokenAuth{ts: c.TokenSource(c.cfg.KeycloakTokenURL(), c.cfg.OIDCClientID, c.cfg.OIDCSecret)}, nil
+	}
+	ts := NewTokenSource(c.http, c.cfg.KeycloakTokenURL(), c.cfg.OIDCClientID, c.cfg.OIDCSecret).WithParams(params)
+	return tokenAuth{ts: ts}, nil
+}
+
+func newClientSecretJWTAuth(c *Client) (Authenticator, error) {
+	tokenURL := c.cfg.KeycloakTokenURL()
+	key := []byte(c.cfg.OIDCSecret)
+	ts := NewTokenSource(c.http, tokenURL, c.cfg.OIDCClientID, "").
+		WithParams(oidcParams(c.cfg)).
+		WithClientAssertion(func() (string, error) {
+			return signAssertion(c.cfg.OIDCClientID, tokenURL, "", hs256Signer(key))
+		})
+	return tokenAuth{ts: ts}, nil
+}
+
+func newPrivateKeyJWTAuth(c *Client) (Authenticator, error) {
+	signer, err := loadAssertionSigner(c.cfg.OIDCAssertionKey)
+	if err != nil {
+		return nil, err
+	}
+	tokenURL := c.cfg.KeycloakTokenURL()
+	ts := NewTokenSource(c.http, tokenURL, c.cfg.OIDCClientID, "").
+		WithParams(oidcParams(c.cfg)).
+		WithClientAssertion(func() (string, error) {
+			return signAssertion(c.cfg.OIDCClientID, tokenURL, c.cfg.OIDCAssertionKeyID, signer)
+		})
+	return tokenAuth{ts: ts}, nil
+}
+
+type bearerAuth struct{ token string }
+
+func newBearerAuth(c *Client) (Authenticator, error) {
+	return bearerAuth{token: c.cfg.BearerToken}, nil
+}
+
+func (a bearerAuth) Authenticate(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+a.token)
+	return nil
+}
+
+// mtlsAuth authenticates with the configured client certificate only.
+type mtlsAuth struct{ cfg *config.Config }
+
+func newMTLSAuth(c *Client) (Authenticator, error) {
+	if c.cfg.TLSClientCert == "" || c.cfg.TLSClientKey == "" {
+		return nil, fmt.Errorf("mtls auth requires TEST_TLS_CLIENT_CERT and TEST_TLS_CLIENT_KEY")
+	}
+	return mtlsAuth{cfg: c.cfg}, nil
+}
+
+func (mtlsAuth) Authenticate(*http.Request) error { return nil }
+
+func (a mtlsAuth) ConfigureTLS(tc *tls.Config) error {
+	return configureClientTLS(a.cfg, tc)
+}
+
+// configureClientTLS loads the client certificate and extra CA bundle from
+// cfg into tc. It is applied in every auth mode so e.g. OIDC can run behind
+// an ingress that also requires a client certificate.
+func configureClientTLS(cfg *config.Config, tc *tls.Config) error {
+	if cfg.TLSClientCert != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.TLSClientCert, cfg.TLSClientKey)
+		if err != nil {
+			return fmt.Errorf("loading client certificate: %w", err)
+		}
+		tc.Certificates = []tls.Certificate{cert}
+	}
+	if cfg.TLSCACert != "" {
+		pemData, err := os.ReadFile(cfg.TLSCACert)
+		if err != nil {
+			return fmt.Errorf("reading CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pemData) {
+			return fmt.Errorf("no certificates found in %s", cfg.TLSCACert)
+		}
+		tc.RootCAs = pool
+	}
+	return nil
+}
+
+// assertionSigner signs a JWS signing input and names its "alg".
+type assertionSigner struct {
+	alg  string
+	sign func(input []byte) ([]byte, error)
+}
+
+func hs256Signer(key []byte) assertionSigner {
+	return assertionSigner{alg: "HS256", sign: func(input []byte) ([]byte, error) {
+		m := hmac.New(sha256.New, key)
+		m.Write(input)
+		return m.Sum(nil), nil
+	}}
+}
+
+// loadAssertionSigner reads a PEM private key (PKCS#1, PKCS#8 or SEC 1) and
+// returns an RS256 or ES256 signer for it.
+func loadAssertionSigner(path string) (assertionSigner, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return assertionSigner{}, fmt.Errorf("reading assertion key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return assertionSigner{}, fmt.Errorf("%s: no PEM block found", path)
+	}
+	var key interface{}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return assertionSigner{}, fmt.Errorf("%s: parsing private key: %w", path, err)
+	}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return assertionSigner{alg: "RS256", sign: func(input []byte) ([]byte, error) {
+			h := sha256.Sum256(input)
+			return rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, h[:])
+		}}, nil
+	case *ecdsa.PrivateKey:
+		if k.Curve.Params().BitSize != 256 {
+			return assertionSigner{}, fmt.Errorf("%s: only P-256 EC keys are supported (ES256)", path)
+		}
+		return assertionSigner{alg: "ES256", sign: func(input []byte) ([]byte, error) {
+			h := sha256.Sum256(input)
+			r, s, err := ecdsa.Sign(rand.Reader, k, h[:])
+			if err != nil {
+				return nil, err
+			}
+			// JWS wants the fixed-width r||s encoding, not ASN.1.
+			out := make([]byte, 64)
+			r.FillBytes(out[:32])
+			s.FillBytes(out[32:])
+			return out, nil
+		}}, nil
+	}
+	return assertionSigner{}, fmt.Errorf("%s: unsupported key type %T (want RSA or EC P-256)", path, key)
+}
+
+// signAssertion builds an RFC 7523 client assertion: iss and sub are the
+// client ID, aud is the token endpoint, and the JWT is valid for one minute.
+func signAssertion(clientID, tokenURL, keyID string, s assertionSigner) (string, error) {
+	header := map[string]string{"alg": s.alg, "typ": "JWT"}
+	if keyID != "" {
+		header["kid"] = keyID
+	}
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", err
+	}
+	now := time.Now()
+	claims := map[string]interface{}{
+		"iss": clientID,
+		"sub": clientID,
+		"aud": tokenURL,
+		"jti": hex.EncodeToString(jti),
+		"iat": now.Unix(),
+		"exp": now.Add(time.Minute).Unix(),
+	}
+
+	enc := base64.RawURLEncoding
+	h, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	cl, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	input := enc.EncodeToString(h) + "." + enc.EncodeToString(cl)
+	sig, err := s.sign([]byte(input))
+	if err != nil {
+		return "", err
+	}
+	return input + "." + enc.EncodeToString(sig), nil
+}
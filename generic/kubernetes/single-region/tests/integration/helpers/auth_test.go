@@ -0,0 +1,122 @@
+package helpers
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/config"
+)
+
+func TestPrivateKeyJWTAuthenticator(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyPath := filepath.Join(t.TempDir(), "client.pem")
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	}), 0o600))
+
+	var tokenURL string
+	idp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "api://camunda", r.PostForm.Get("audience"))
+		assert.Empty(t, r.PostForm.Get("client_secret"))
+		assert.Equal(t, "urn:ietf:params:oauth:client-assertion-type:jwt-bearer", r.PostForm.Get("client_assertion_type"))
+
+		parts := strings.Split(r.PostForm.Get("client_assertion"), ".")
+		require.Len(t, parts, 3)
+		sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+		require.NoError(t, err)
+		h := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		assert.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, h[:], sig))
+
+		var header map[string]string
+		raw, _ := base64.RawURLEncoding.DecodeString(parts[0])
+		require.NoError(t, json.Unmarshal(raw, &header))
+		assert.Equal(t, "RS256", header["alg"])
+		assert.Equal(t, "kid-1", header["kid"])
+
+		var claims map[string]interface{}
+		raw, _ = base64.RawURLEncoding.DecodeString(parts[1])
+		require.NoError(t, json.Unmarshal(raw, &claims))
+		assert.Equal(t, "test-client", claims["iss"])
+		assert.Equal(t, "test-client", claims["sub"])
+		assert.Equal(t, tokenURL, claims["aud"])
+
+		fmt.Fprint(w, `{"access_token":"jwt-token","expires_in":300}`)
+	}))
+	t.Cleanup(idp.Close)
+	tokenURL = idp.URL + "/token"
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer jwt-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(api.Close)
+
+	c := NewClient(&config.Config{
+		AuthMode:           "private-key-jwt",
+		OIDCTokenURL:       tokenURL,
+		OIDCClientID:       "test-client",
+		OIDCAudience:       "api://camunda",
+		OIDCAssertionKey:   keyPath,
+		OIDCAssertionKeyID: "kid-1",
+		HTTPTimeout:        5 * time.Second,
+	})
+	resp, err := c.Get(api.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestRegisterAuthenticator(t *testing.T) {
+	RegisterAuthenticator("test-header", func(c *Client) (Authenticator, error) {
+		return headerAuth{"X-Test-Auth", "ok"}, nil
+	}, nil)
+	t.Cleanup(func() { delete(authenticators, "test-header") })
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Test-Auth") != "ok" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(api.Close)
+
+	cfg := &config.Config{AuthMode: "test-header", ClusterType: "kubernetes", HTTPTimeout: 5 * time.Second, RetryAttempts: 1}
+	assert.NotContains(t, fmt.Sprint(cfg.Validate()), "TEST_AUTH_MODE")
+
+	resp, err := NewClient(cfg).Get(api.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+type headerAuth struct{ name, value string }
+
+func (a headerAuth) Authenticate(req *http.Request) error {
+	req.Header.Set(a.name, a.value)
+	return nil
+}
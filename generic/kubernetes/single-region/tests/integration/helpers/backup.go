@@ -0,0 +1,118 @@
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Zeebe backup states reported by /actuator/backups.
+const (
+	BackupCompleted    = "COMPLETED"
+	BackupInProgress   = "IN_PROGRESS"
+	BackupFailed       = "FAILED"
+	BackupIncomplete   = "INCOMPLETE"
+	BackupDoesNotExist = "DOES_NOT_EXIST"
+)
+
+// Backup is the status of one Zeebe backup, aggregated over partitions.
+type Backup struct {
+	BackupID      int64             `json:"backupId"`
+	State         string            `json:"state"`
+	FailureReason string            `json:"failureReason,omitempty"`
+	Details       []BackupPartition `json:"details"`
+}
+
+// BackupPartition is the backup status of one partition.
+type BackupPartition struct {
+	PartitionID   int    `json:"partitionId"`
+	State         string `json:"state"`
+	FailureReason string `json:"failureReason,omitempty"`
+	CreatedAt     string `json:"createdAt,omitempty"`
+	BrokerVersion string `json:"brokerVersion,omitempty"`
+}
+
+// TakeBackup schedules a Zeebe backup with id through the management API at
+// baseURL (the orchestration cluster's management port). The backup runs
+// asynchronously; poll GetBackup until it leaves IN_PROGRESS. IDs must be
+// greater than every existing backup's ID.
+func (c *Client) TakeBackup(ctx context.Context, baseURL string, id int64) error {
+	return c.actuator(ctx, http.MethodPost, baseURL, "/actuator/backups", fmt.Sprintf(`{"backupId":%d}`, id), nil)
+}
+
+// GetBackup returns the status of the backup with id.
+func (c *Client) GetBackup(ctx context.Context, baseURL string, id int64) (*Backup, error) {
+	var b Backup
+	if err := c.actuator(ctx, http.MethodGet, baseURL, fmt.Sprintf("/actuator/backups/%d", id), "", &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// ListBackups returns every backup the configured backup store holds.
+func (c *Client) ListBackups(ctx context.Context, baseURL string) ([]Backup, error) {
+	var out []Backup
+	if err := c.actuator(ctx, http.MethodGet, baseURL, "/actuator/backups", "", &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DeleteBackup removes the backup with id from the backup store.
+func (c *Client) DeleteBackup(ctx context.Context, baseURL string, id int64) error {
+	return c.actuator(ctx, http.MethodDelete, baseURL, fmt.Sprintf("/actuator/backups/%d", id), "", nil)
+}
+
+// PauseExporting pauses exporting on every partition. A soft pause keeps
+// sending records to the exporters but holds back the exported position, so
+// the log is not compacted past them; the backup procedure uses it. Only a
+// hard pause stops records from reaching secondary storage.
+func (c *Client) PauseExporting(ctx context.Context, baseURL string, soft bool) error {
+	path := "/actuator/exporting/pause"
+	if soft {
+		path += "?soft=true"
+	}
+	return c.actuator(ctx, http.MethodPost, baseURL, path, "", nil)
+}
+
+// ResumeExporting resumes exporting paused by PauseExporting.
+func (c *Client) ResumeExporting(ctx context.Context, baseURL string) error {
+	return c.actuator(ctx, http.MethodPost, baseURL, "/actuator/exporting/resume", "", nil)
+}
+
+// actuator sends a management API request and decodes a JSON response into
+// out (which may be nil). Any 2xx is success.
+func (c *Client) actuator(ctx context.Context, method, baseURL, path, body string, out interface{}) error {
+	url := strings.TrimRight(baseURL, "/") + path
+	var (
+		resp *http.Response
+		err  error
+	)
+	switch method {
+	case http.MethodGet:
+		resp, err = c.GetContext(ctx, url)
+	case http.MethodDelete:
+		resp, err = c.Delete(ctx, url)
+	default:
+		resp, err = c.send(ctx, method, url, strings.NewReader(body), "application/json")
+	}
+	if err != nil {
+		return err
+	}
+	data, err := ReadBody(resp)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return NewStatusError(resp, data)
+	}
+	if out == nil || data == "" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(data), out); err != nil {
+		return fmt.Errorf("%s %s: decoding response: %w", method, url, err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/config"
+)
+
+func TestZeebeBackupAPI(t *testing.T) {
+	var calls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.RequestURI())
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/actuator/backups":
+			var body struct {
+				BackupID int64 `json:"backupId"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, int64(42), body.BackupID)
+			w.WriteHeader(http.StatusAccepted)
+			fmt.Fprint(w, `{"message":"A backup with id 42 has been scheduled"}`)
+		case r.URL.Path == "/actuator/backups/42":
+			fmt.Fprint(w, `{"backupId":42,"state":"COMPLETED","details":[
+				{"partitionId":1,"state":"COMPLETED","createdAt":"2026-01-01T00:00:00Z","brokerVersion":"8.8.0"},
+				{"partitionId":2,"state":"COMPLETED"}]}`)
+		case r.URL.Path == "/actuator/backups/7":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"no backup with id 7"}`)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	c := NewClient(&config.Config{AuthMode: "none", HTTPTimeout: 5 * time.Second})
+
+	require.NoError(t, c.PauseExporting(t.Context(), srv.URL, true))
+	require.NoError(t, c.TakeBackup(t.Context(), srv.URL+"/", 42))
+	b, err := c.GetBackup(t.Context(), srv.URL, 42)
+	require.NoError(t, err)
+	assert.Equal(t, BackupCompleted, b.State)
+	require.Len(t, b.Details, 2)
+	assert.Equal(t, "8.8.0", b.Details[0].BrokerVersion)
+	require.NoError(t, c.ResumeExporting(t.Context(), srv.URL))
+
+	_, err = c.GetBackup(t.Context(), srv.URL, 7)
+	var se *StatusError
+	require.ErrorAs(t, err, &se)
+	assert.Equal(t, http.StatusNotFound, se.StatusCode)
+
+	assert.Equal(t, []string{
+		"POST /actuator/exporting/pause?soft=true",
+		"POST /actuator/backups",
+		"GET /actuator/backups/42",
+		"POST /actuator/exporting/resume",
+		"GET /actuator/backups/7",
+	}, calls)
+}
@@ -2,111 +2,119 @@
 package helpers
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"mime/multipart"
+	"net"
 	"net/http"
-	"net/url"
+	"net/textproto"
 	"strings"
-	"time"
 
 	"github.com/camunda/camunda-deployment-references/tests/integration/config"
 )
 
-// Client wraps an HTTP client with authentication support.
+// Client wraps an HTTP client with authentication support. It is safe for
+// concurrent use by parallel subtests.
 type Client struct {
-	http    *http.Client
-	cfg     *config.Config
-	tokenFn func() (string, error)
-	token   string
-	tokenAt time.Time
+	http   *http.Client
+	cfg    *config.Config
+	tokens tokenCache
+	auth   Authenticator
+	// authErr is reported by every Do call when the authenticator could not
+	// be built (e.g. unreadable key file), mirroring how token fetch errors
+	// surface as "auth failed".
+	authErr error
 }
 
-// NewClient creates an authenticated HTTP client from config.
+// NewClient creates an authenticated HTTP client from config. The
+// authenticator is chosen by cfg.AuthMode (see RegisterAuthenticator).
+// HTTP/2 is negotiated over TLS so the same client can carry gRPC calls.
 func NewClient(cfg *config.Config) *Client {
-	transport := &http.Transport{
-		TLSClientConfig: &tls.Config{MinVersion: tls.VersionTLS12},
-	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
 	c := &Client{
 		http: &http.Client{
-			Timeout:   cfg.HTTPTimeout,
-			Transport: transport,
+			Timeout: cfg.HTTPTimeout,
+			Transport: &http.Transport{
+				TLSClientConfig:   tlsConfig,
+				ForceAttemptHTTP2: true,
+			},
 		},
 		cfg: cfg,
 	}
-	if cfg.AuthMode == "oidc" {
-		c.tokenFn = c.fetchOIDCToken
+	c.auth, c.authErr = newAuthenticator(c)
+	if c.authErr != nil {
+		return c
+	}
+	if tc, ok := c.auth.(TLSConfigurer); ok {
+		c.authErr = tc.ConfigureTLS(tlsConfig)
+	} else {
+		c.authErr = configureClientTLS(cfg, tlsConfig)
 	}
 	return c
 }
 
-func (c *Client) fetchOIDCToken() (string, error) {
-	data := url.Values{
-		"grant_type":    {"client_credentials"},
-		"client_id":     {c.cfg.OIDCClientID},
-		"client_secret": {c.cfg.OIDCSecret},
-	}
-	resp, err := c.http.Post(c.cfg.KeycloakTokenURL(), "application/x-www-form-urlencoded", strings.NewReader(data.Encode()))
-	if err != nil {
-		return "", fmt.Errorf("token request failed: %w", err)
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("token endpoint returned %d: %s", resp.StatusCode, string(body))
-	}
-	var result struct {
-		AccessToken string `json:"access_token"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("failed to decode token response: %w", err)
-	}
-	if result.AccessToken == "" {
-		return "", fmt.Errorf("empty access_token in response")
-	}
-	return result.AccessToken, nil
+// Config returns the configuration the client was built from.
+func (c *Client) Config() *config.Config {
+	return c.cfg
+}
+
+// TokenSource returns the client's shared, cached TokenSource for a
+// client_id/client_secret pair at tokenURL.
+func (c *Client) TokenSource(tokenURL, clientID, clientSecret string) *TokenSource {
+	return c.tokens.get(c.http, tokenURL, clientID, clientSecret)
 }
 
-func (c *Client) getToken() (string, error) {
-	if c.tokenFn == nil {
-		return "", nil
+// Do executes an HTTP request with authentication. When the authenticator
+// implements Invalidator (token-based modes), a 401 is retried once with
+// fresh credentials, provided the request body can be replayed (requests
+// built by http.NewRequest from a strings/bytes reader always can).
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	if c.authErr != nil {
+		return nil, fmt.Errorf("auth failed: %w", c.authErr)
 	}
-	if c.token != "" && time.Since(c.tokenAt) < 4*time.Minute {
-		return c.token, nil
+	if err := c.auth.Authenticate(req); err != nil {
+		return nil, fmt.Errorf("auth failed: %w", requestError(req, err))
 	}
-	t, err := c.tokenFn()
+	resp, err := c.http.Do(req)
 	if err != nil {
-		return "", err
+		return nil, requestError(req, err)
 	}
-	c.token = t
-	c.tokenAt = time.Now()
-	return t, nil
-}
-
-// Do executes an HTTP request with authentication.
-func (c *Client) Do(req *http.Request) (*http.Response, error) {
-	switch c.cfg.AuthMode {
-	case "basic":
-		req.SetBasicAuth(c.cfg.BasicUser, c.cfg.BasicPass)
-	case "oidc":
-		token, err := c.getToken()
-		if err != nil {
-			return nil, fmt.Errorf("auth failed: %w", err)
+	inv, ok := c.auth.(Invalidator)
+	if !ok || resp.StatusCode != http.StatusUnauthorized {
+		return resp, nil
+	}
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return resp, nil
 		}
-		req.Header.Set("Authorization", "Bearer "+token)
 	}
-	return c.http.Do(req)
+	inv.Invalidate(req)
+	if err := c.auth.Authenticate(retry); err != nil {
+		return resp, nil
+	}
+	resp.Body.Close()
+	resp, err = c.http.Do(retry)
+	return resp, requestError(retry, err)
 }
 
-// Get performs a GET request.
+// Get performs a GET request. It is GetContext with context.Background(), so
+// only the client-wide HTTPTimeout bounds it; tests should prefer GetContext
+// with t.Context().
 func (c *Client) Get(url string) (*http.Response, error) {
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
-	if err != nil {
-		return nil, err
-	}
-	return c.Do(req)
+	return c.GetContext(context.Background(), url)
+}
+
+// GetContext performs a GET request bound to ctx.
+func (c *Client) GetContext(ctx context.Context, url string) (*http.Response, error) {
+	return c.send(ctx, http.MethodGet, url, nil, "")
 }
 
 // GetUnauth performs a GET request without injecting any authentication
@@ -117,7 +125,12 @@ func (c *Client) Get(url string) (*http.Response, error) {
 // and following the redirect can lead to an http:// URL (e.g. WebModeler's
 // /modeler -> /modeler/ trailing-slash redirect drops https) that hangs.
 func (c *Client) GetUnauth(url string) (*http.Response, error) {
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	return c.GetUnauthContext(context.Background(), url)
+}
+
+// GetUnauthContext is GetUnauth bound to ctx.
+func (c *Client) GetUnauthContext(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -125,70 +138,112 @@ func (c *Client) GetUnauth(url string) (*http.Response, error) {
 	noRedirect.CheckRedirect = func(*http.Request, []*http.Request) error {
 		return http.ErrUseLastResponse
 	}
-	return noRedirect.Do(req)
+	resp, err := noRedirect.Do(req)
+	return resp, requestError(req, err)
 }
 
 // PostJSON performs a POST request with a JSON body.
 func (c *Client) PostJSON(url string, body string) (*http.Response, error) {
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, url, strings.NewReader(body))
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
-	return c.Do(req)
+	return c.PostJSONContext(context.Background(), url, body)
 }
 
-// GetTokenForClient fetches an OIDC token for a specific client_id/client_secret pair.
-func (c *Client) GetTokenForClient(tokenURL, clientID, clientSecret string) (string, error) {
-	data := url.Values{
-		"grant_type":    {"client_credentials"},
-		"client_id":     {clientID},
-		"client_secret": {clientSecret},
-	}
-	resp, err := c.http.Post(tokenURL, "application/x-www-form-urlencoded", strings.NewReader(data.Encode()))
-	if err != nil {
-		return "", fmt.Errorf("token request failed: %w", err)
+// PostJSONContext performs a POST request with a JSON body bound to ctx.
+func (c *Client) PostJSONContext(ctx context.Context, url string, body string) (*http.Response, error) {
+	return c.send(ctx, http.MethodPost, url, strings.NewReader(body), "application/json")
+}
+
+// PutJSON performs a PUT request with a JSON body bound to ctx.
+func (c *Client) PutJSON(ctx context.Context, url string, body string) (*http.Response, error) {
+	return c.send(ctx, http.MethodPut, url, strings.NewReader(body), "application/json")
+}
+
+// PatchJSON performs a PATCH request with a JSON body bound to ctx.
+func (c *Client) PatchJSON(ctx context.Context, url string, body string) (*http.Response, error) {
+	return c.send(ctx, http.MethodPatch, url, strings.NewReader(body), "application/json")
+}
+
+// Delete performs a DELETE request bound to ctx.
+func (c *Client) Delete(ctx context.Context, url string) (*http.Response, error) {
+	return c.send(ctx, http.MethodDelete, url, nil, "")
+}
+
+// FormFile is one file part of a multipart upload.
+type FormFile struct {
+	Field    string // form field name, e.g. "resources"
+	Filename string
+	Content  []byte
+	// ContentType defaults to application/octet-stream.
+	ContentType string
+}
+
+// PostMultipart uploads fields and files as multipart/form-data bound to
+// ctx. The body is fully buffered so it can be replayed after a 401.
+func (c *Client) PostMultipart(ctx context.Context, url string, fields map[string]string, files ...FormFile) (*http.Response, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for k, v := range fields {
+		if err := w.WriteField(k, v); err != nil {
+			return nil, err
+		}
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("token endpoint returned %d: %s", resp.StatusCode, string(body))
+	for _, f := range files {
+		ct := f.ContentType
+		if ct == "" {
+			ct = "application/octet-stream"
+		}
+		h := textproto.MIMEHeader{}
+		h.Set("Content-Disposition", fmt.Sprintf(`form-data; name=%q; filename=%q`, f.Field, f.Filename))
+		h.Set("Content-Type", ct)
+		part, err := w.CreatePart(h)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := part.Write(f.Content); err != nil {
+			return nil, err
+		}
 	}
-	var result struct {
-		AccessToken string `json:"access_token"`
+	if err := w.Close(); err != nil {
+		return nil, err
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("decode failed: %w", err)
+	return c.send(ctx, http.MethodPost, url, bytes.NewReader(buf.Bytes()), w.FormDataContentType())
+}
+
+// send builds an authenticated request bound to ctx. A JSON body also sets
+// Accept: application/json, as the Camunda REST API expects.
+func (c *Client) send(ctx context.Context, method, url string, body io.Reader, contentType string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
 	}
-	if result.AccessToken == "" {
-		return "", fmt.Errorf("empty access_token in response")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Accept", "application/json")
 	}
-	return result.AccessToken, nil
+	return c.Do(req)
 }
 
-// Retry executes fn up to maxAttempts times with the given delay between attempts.
-// maxAttempts < 1 is normalised to 1 so the function always runs at least once and
-// can never return a nil error without having actually attempted the operation.
-func Retry(maxAttempts int, delay time.Duration, fn func() error) error {
-	if maxAttempts < 1 {
-		maxAttempts = 1
-	}
-	var lastErr error
-	for i := 0; i < maxAttempts; i++ {
-		if err := fn(); err != nil {
-			lastErr = err
-			if i < maxAttempts-1 {
-				time.Sleep(delay)
-			}
-			continue
-		}
+// requestError labels context and client timeouts with the request they
+// interrupted, e.g. "deadline exceeded on GET /v2/topology", so a hung
+// endpoint is obvious in the test output. Other errors pass through.
+func requestError(req *http.Request, err error) error {
+	if err == nil {
 		return nil
 	}
-	if lastErr == nil {
-		return fmt.Errorf("failed after %d attempts: no error captured", maxAttempts)
+	var ne net.Error
+	switch {
+	case errors.Is(err, context.Canceled):
+		return fmt.Errorf("canceled on %s %s: %w", req.Method, req.URL.Path, err)
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &ne) && ne.Timeout():
+		return fmt.Errorf("deadline exceeded on %s %s: %w", req.Method, req.URL.Path, err)
 	}
-	return fmt.Errorf("failed after %d attempts: %w", maxAttempts, lastErr)
+	return err
+}
+
+// GetTokenForClient returns an OIDC token for a specific client_id/client_secret
+// pair. Tokens are cached per client and shared with Do, so repeated calls
+// only hit the token endpoint when the cached token is about to expire.
+func (c *Client) GetTokenForClient(tokenURL, clientID, clientSecret string) (string, error) {
+	return c.TokenSource(tokenURL, clientID, clientSecret).Token()
 }
 
 // ReadBody reads the response body and closes it.
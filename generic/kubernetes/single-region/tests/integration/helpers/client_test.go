@@ -0,0 +1,59 @@
+package helpers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/config"
+)
+
+func TestGetContextDeadline(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { close(release) })
+
+	c := NewClient(&config.Config{AuthMode: "none", HTTPTimeout: time.Minute})
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := c.GetContext(ctx, srv.URL+"/v2/topology")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "deadline exceeded on GET /v2/topology")
+}
+
+func TestPostMultipart(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		assert.Equal(t, "<default>", r.FormValue("tenantId"))
+		f, hdr, err := r.FormFile("resources")
+		require.NoError(t, err)
+		defer f.Close()
+		body, _ := io.ReadAll(f)
+		assert.Equal(t, "process.bpmn", hdr.Filename)
+		assert.Equal(t, "<bpmn/>", string(body))
+		assert.True(t, strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data; boundary="))
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := NewClient(&config.Config{AuthMode: "none", HTTPTimeout: 5 * time.Second})
+	resp, err := c.PostMultipart(t.Context(), srv.URL, map[string]string{"tenantId": "<default>"},
+		FormFile{Field: "resources", Filename: "process.bpmn", Content: []byte("<bpmn/>")})
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
@@ -0,0 +1,163 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"helm.sh/helm/v3/pkg/action"
+	helmchart "helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/cli/values"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/registry"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/config"
+)
+
+// HelmChart locates a chart the way `helm install NAME CHART` does: Ref is
+// a chart name in RepoURL, an oci:// reference or a local chart directory
+// (built from source for pre-releases). Version is ignored for a local
+// directory; empty means the latest release.
+type HelmChart struct {
+	Ref     string
+	RepoURL string
+	Version string
+}
+
+func (c HelmChart) String() string {
+	if c.Version == "" {
+		return c.Ref
+	}
+	return c.Ref + "@" + c.Version
+}
+
+// Helm installs and upgrades one release through the Helm SDK, with the
+// same kubeconfig, context and namespace kubectl uses for the port-forwards.
+type Helm struct {
+	Release   string
+	Namespace string
+	// Timeout bounds each install or upgrade, including waiting for the
+	// workloads to become ready.
+	Timeout time.Duration
+
+	settings *cli.EnvSettings
+	action   *action.Configuration
+}
+
+// NewHelm returns a Helm for cfg.ReleaseName in cfg.Namespace on
+// cfg.KubeContext. Helm's debug output goes to logf.
+func NewHelm(cfg *config.Config, timeout time.Duration, logf func(format string, args ...interface{})) (*Helm, error) {
+	settings := cli.New()
+	settings.KubeContext = cfg.KubeContext
+	settings.SetNamespace(cfg.Namespace)
+
+	ac := new(action.Configuration)
+	if err := ac.Init(settings.RESTClientGetter(), cfg.Namespace, os.Getenv("HELM_DRIVER"), logf); err != nil {
+		return nil, fmt.Errorf("helm: %w", err)
+	}
+	rc, err := registry.NewClient(registry.ClientOptWriter(io.Discard), registry.ClientOptCredentialsFile(settings.RegistryConfig))
+	if err != nil {
+		return nil, fmt.Errorf("helm registry client: %w", err)
+	}
+	ac.RegistryClient = rc
+	return &Helm{Release: cfg.ReleaseName, Namespace: cfg.Namespace, Timeout: timeout, settings: settings, action: ac}, nil
+}
+
+// Current returns the deployed release, or nil when there is none.
+func (h *Helm) Current() (*release.Release, error) {
+	rel, err := action.NewGet(h.action).Run(h.Release)
+	if errors.Is(err, driver.ErrReleaseNotFound) {
+		return nil, nil
+	}
+	return rel, err
+}
+
+// Install installs chart as the release with the merged valueFiles and
+// waits for it to be ready. The namespace is created if needed.
+func (h *Helm) Install(ctx context.Context, chart HelmChart, valueFiles []string) (*release.Release, error) {
+	install := action.NewInstall(h.action)
+	install.ReleaseName = h.Release
+	install.Namespace = h.Namespace
+	install.CreateNamespace = true
+	install.Wait = true
+	install.Timeout = h.Timeout
+	install.RepoURL = chart.RepoURL
+	install.Version = chart.Version
+
+	chrt, vals, err := h.load(&install.ChartPathOptions, chart, valueFiles)
+	if err != nil {
+		return nil, err
+	}
+	rel, err := install.RunWithContext(ctx, chrt, vals)
+	if err != nil {
+		return nil, fmt.Errorf("helm install %s %s: %w", h.Release, chart, err)
+	}
+	return rel, nil
+}
+
+// Upgrade upgrades the release to chart with the merged valueFiles (values
+// of the previous revision are not reused) and waits for it to be ready.
+func (h *Helm) Upgrade(ctx context.Context, chart HelmChart, valueFiles []string) (*release.Release, error) {
+	upgrade := action.NewUpgrade(h.action)
+	upgrade.Namespace = h.Namespace
+	upgrade.Wait = true
+	upgrade.Timeout = h.Timeout
+	upgrade.RepoURL = chart.RepoURL
+	upgrade.Version = chart.Version
+
+	chrt, vals, err := h.load(&upgrade.ChartPathOptions, chart, valueFiles)
+	if err != nil {
+		return nil, err
+	}
+	rel, err := upgrade.RunWithContext(ctx, h.Release, chrt, vals)
+	if err != nil {
+		return nil, fmt.Errorf("helm upgrade %s %s: %w", h.Release, chart, err)
+	}
+	return rel, nil
+}
+
+// Uninstall removes the release and waits for its resources to be gone.
+func (h *Helm) Uninstall() error {
+	uninstall := action.NewUninstall(h.action)
+	uninstall.Wait = true
+	uninstall.Timeout = h.Timeout
+	if _, err := uninstall.Run(h.Release); err != nil {
+		return fmt.Errorf("helm uninstall %s: %w", h.Release, err)
+	}
+	return nil
+}
+
+// load downloads (or reads) chart and merges valueFiles, later files
+// winning, as repeated --values flags do.
+func (h *Helm) load(opts *action.ChartPathOptions, chart HelmChart, valueFiles []string) (*helmchart.Chart, map[string]interface{}, error) {
+	path, err := opts.LocateChart(chart.Ref, h.settings)
+	if err != nil {
+		return nil, nil, fmt.Errorf("locating chart %s: %w", chart, err)
+	}
+	chrt, err := loader.Load(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading chart %s: %w", chart, err)
+	}
+	vals, err := MergeHelmValues(valueFiles)
+	if err != nil {
+		return nil, nil, err
+	}
+	return chrt, vals, nil
+}
+
+// MergeHelmValues reads and deep-merges Helm values files in order.
+func MergeHelmValues(files []string) (map[string]interface{}, error) {
+	opts := values.Options{ValueFiles: files}
+	vals, err := opts.MergeValues(getter.All(cli.New()))
+	if err != nil {
+		return nil, fmt.Errorf("merging values %v: %w", files, err)
+	}
+	return vals, nil
+}
@@ -0,0 +1,28 @@
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeHelmValues(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "values.yml")
+	overlay := filepath.Join(dir, "overlay.yml")
+	require.NoError(t, os.WriteFile(base, []byte("orchestration:\n  clusterSize: 3\n  partitionCount: 3\nidentity:\n  enabled: true\n"), 0o644))
+	require.NoError(t, os.WriteFile(overlay, []byte("orchestration:\n  clusterSize: 1\n"), 0o644))
+
+	vals, err := MergeHelmValues([]string{base, overlay})
+	require.NoError(t, err)
+	orchestration := vals["orchestration"].(map[string]interface{})
+	assert.EqualValues(t, 1, orchestration["clusterSize"], "later files win")
+	assert.EqualValues(t, 3, orchestration["partitionCount"], "maps are merged, not replaced")
+	assert.Equal(t, map[string]interface{}{"enabled": true}, vals["identity"])
+
+	_, err = MergeHelmValues([]string{filepath.Join(dir, "missing.yml")})
+	assert.Error(t, err)
+}
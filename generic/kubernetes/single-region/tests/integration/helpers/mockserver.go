@@ -0,0 +1,108 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// MockRequest is one request recorded by a MockServer.
+type MockRequest struct {
+	Received time.Time
+	Method   string
+	Path     string
+	Header   http.Header
+	Body     []byte
+}
+
+// MockServer is an in-process HTTP target for outbound connector calls. It
+// answers every request with 200 and {"status":"ok"} and records it, like
+// the EKS suite's mock-api-server StatefulSet but without deploying anything
+// into the cluster. The cluster must be able to reach the listen address
+// (on kind, the docker network gateway of the host).
+type MockServer struct {
+	srv      *http.Server
+	listener net.Listener
+
+	mu       sync.Mutex
+	requests []MockRequest
+	notify   chan struct{}
+}
+
+// StartMockServer listens on addr (host:port; port 0 picks a free one) and
+// serves until Close.
+func StartMockServer(addr string) (*MockServer, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("mock server: %w", err)
+	}
+	m := &MockServer{listener: l, notify: make(chan struct{})}
+	m.srv = &http.Server{Handler: http.HandlerFunc(m.record), ReadHeaderTimeout: 10 * time.Second}
+	go func() { _ = m.srv.Serve(l) }()
+	return m, nil
+}
+
+// Addr is the address the server listens on.
+func (m *MockServer) Addr() net.Addr {
+	return m.listener.Addr()
+}
+
+func (m *MockServer) record(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	m.mu.Lock()
+	m.requests = append(m.requests, MockRequest{
+		Received: time.Now(),
+		Method:   r.Method,
+		Path:     r.URL.Path,
+		Header:   r.Header.Clone(),
+		Body:     body,
+	})
+	close(m.notify)
+	m.notify = make(chan struct{})
+	m.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(`{"status":"ok"}`))
+}
+
+// Requests returns a copy of the requests received so far.
+func (m *MockServer) Requests() []MockRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]MockRequest(nil), m.requests...)
+}
+
+// WaitFor blocks until a received request satisfies match and returns it,
+// or returns an error when ctx ends first.
+func (m *MockServer) WaitFor(ctx context.Context, match func(MockRequest) bool) (MockRequest, error) {
+	for {
+		m.mu.Lock()
+		for _, r := range m.requests {
+			if match(r) {
+				m.mu.Unlock()
+				return r, nil
+			}
+		}
+		seen, notify := len(m.requests), m.notify
+		m.mu.Unlock()
+
+		select {
+		case <-notify:
+		case <-ctx.Done():
+			return MockRequest{}, fmt.Errorf("no matching request among %d received: %w", seen, ctx.Err())
+		}
+	}
+}
+
+// Close stops the server.
+func (m *MockServer) Close() error {
+	if err := m.srv.Close(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMockServerWaitFor(t *testing.T) {
+	m, err := StartMockServer("127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = m.Close() })
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		resp, err := http.Post("http://"+m.Addr().String()+"/callback", "application/json", bytes.NewBufferString(`{"runId":"42"}`))
+		if err == nil {
+			resp.Body.Close()
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+	r, err := m.WaitFor(ctx, func(r MockRequest) bool { return r.Path == "/callback" })
+	require.NoError(t, err)
+	assert.Equal(t, http.MethodPost, r.Method)
+	assert.JSONEq(t, `{"runId":"42"}`, string(r.Body))
+	assert.Len(t, m.Requests(), 1)
+
+	short, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+	_, err = m.WaitFor(short, func(r MockRequest) bool { return r.Path == "/other" })
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,397 @@
+package helpers
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/config"
+)
+
+// portForwardReadyTimeout bounds how long a single kubectl port-forward may
+// take to print its "Forwarding from" line; portForwardAttempts is how many
+// times a tunnel is retried before giving up on it.
+const (
+	portForwardReadyTimeout = 60 * time.Second
+	portForwardAttempts     = 3
+	portForwardBackoff      = 5 * time.Second
+)
+
+var forwardingRe = regexp.MustCompile(`Forwarding from (?:127\.0\.0\.1|\[::1\]):(\d+)`)
+
+// Tunnel is a running `kubectl port-forward` to a Service port.
+type Tunnel struct {
+	Service    string
+	RemotePort int
+	// LocalAddr is the host:port the tunnel listens on (always loopback).
+	LocalAddr string
+
+	cmd  *exec.Cmd
+	pipe *os.File
+}
+
+// Close stops the kubectl subprocess. It is safe to call more than once.
+func (t *Tunnel) Close() {
+	if t == nil || t.cmd == nil {
+		return
+	}
+	if t.cmd.Process != nil {
+		_ = t.cmd.Process.Kill()
+	}
+	_ = t.cmd.Wait()
+	_ = t.pipe.Close()
+	t.cmd = nil
+}
+
+// PortForwarder owns the lifecycle of a set of kubectl tunnels into one
+// namespace. Create it with NewPortForwarder and Close it when done.
+type PortForwarder struct {
+	Namespace string
+	Context   string
+	// Logf receives progress messages; defaults to stderr because tunnels are
+	// set up in TestMain, before any *testing.T exists.
+	Logf func(format string, args ...interface{})
+
+	mu      sync.Mutex
+	tunnels []*Tunnel
+}
+
+// NewPortForwarder returns a PortForwarder for cfg.Namespace / cfg.KubeContext.
+func NewPortForwarder(cfg *config.Config) *PortForwarder {
+	return &PortForwarder{
+		Namespace: cfg.Namespace,
+		Context:   cfg.KubeContext,
+		Logf: func(format string, args ...interface{}) {
+			fmt.Fprintf(os.Stderr, "[port-forward] "+format+"\n", args...)
+		},
+	}
+}
+
+func (p *PortForwarder) kubectlArgs(args ...string) []string {
+	var base []string
+	if p.Context != "" {
+		base = append(base, "--context", p.Context)
+	}
+	if p.Namespace != "" {
+		base = append(base, "--namespace", p.Namespace)
+	}
+	return append(base, args...)
+}
+
+// ServiceExists reports whether the Service is present in the namespace.
+func (p *PortForwarder) ServiceExists(service string) bool {
+	cmd := exec.Command("kubectl", p.kubectlArgs("get", "svc", service, "--request-timeout=30s")...)
+	return cmd.Run() == nil
+}
+
+// Forward opens a tunnel to service:remotePort on a free local port,
+// retrying transient failures. The tunnel is closed by Close.
+func (p *PortForwarder) Forward(service string, remotePort int) (*Tunnel, error) {
+	var lastErr error
+	for i := 0; i < portForwardAttempts; i++ {
+		t, err := p.start(service, remotePort)
+		if err == nil {
+			p.mu.Lock()
+			p.tunnels = append(p.tunnels, t)
+			p.mu.Unlock()
+			p.Logf("svc/%s:%d -> %s", service, remotePort, t.LocalAddr)
+			return t, nil
+		}
+		lastErr = err
+		p.Logf("svc/%s:%d failed (attempt %d/%d): %v", service, remotePort, i+1, portForwardAttempts, err)
+		if i < portForwardAttempts-1 {
+			time.Sleep(portForwardBackoff)
+		}
+	}
+	return nil, fmt.Errorf("port-forward to svc/%s:%d in %s: %w", service, remotePort, p.Namespace, lastErr)
+}
+
+// start runs one kubectl port-forward subprocess and waits for it to report
+// the local port it bound. Mirrors startKubectlPortForward in the EKS
+// dual-region helpers: stdout and stderr share one pipe so the readiness
+// line is found whichever stream kubectl writes it to, and any error output
+// is kept for diagnostics.
+func (p *PortForwarder) start(service string, remotePort int) (*Tunnel, error) {
+	cmd := exec.Command("kubectl", p.kubectlArgs("port-forward", "svc/"+service, fmt.Sprintf(":%d", remotePort))...)
+	pipeR, pipeW, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	cmd.Stdout = pipeW
+	cmd.Stderr = pipeW
+	if err := cmd.Start(); err != nil {
+		_ = pipeW.Close()
+		_ = pipeR.Close()
+		return nil, err
+	}
+	_ = pipeW.Close()
+
+	t := &Tunnel{Service: service, RemotePort: remotePort, cmd: cmd, pipe: pipeR}
+
+	type portResult struct {
+		port string
+		err  error
+	}
+	resultCh := make(chan portResult, 1)
+	go func() {
+		scanner := bufio.NewScanner(pipeR)
+		found := false
+		var out strings.Builder
+		for scanner.Scan() {
+			if found {
+				// Keep draining so kubectl never blocks on a full pipe.
+				continue
+			}
+			line := scanner.Text()
+			out.WriteString(line)
+			out.WriteByte('\n')
+			if m := forwardingRe.FindStringSubmatch(line); m != nil {
+				found = true
+				resultCh <- portResult{port: m[1]}
+			}
+		}
+		if !found {
+			if scanErr := scanner.Err(); scanErr != nil {
+				resultCh <- portResult{err: scanErr}
+				return
+			}
+			resultCh <- portResult{err: fmt.Errorf("kubectl port-forward exited before forwarding: %s", strings.TrimSpace(out.String()))}
+		}
+	}()
+
+	select {
+	case r := <-resultCh:
+		if r.err != nil {
+			t.Close()
+			return nil, r.err
+		}
+		t.LocalAddr = "127.0.0.1:" + r.port
+		return t, nil
+	case <-time.After(portForwardReadyTimeout):
+		t.Close()
+		return nil, fmt.Errorf("timed out after %s waiting for kubectl port-forward", portForwardReadyTimeout)
+	}
+}
+
+// Close stops every tunnel opened by p. A nil PortForwarder is a no-op so
+// TestMain can defer it unconditionally.
+func (p *PortForwarder) Close() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, t := range p.tunnels {
+		t.Close()
+	}
+	p.tunnels = nil
+}
+
+// forwardTarget describes one Config URL that can be served through a tunnel.
+type forwardTarget struct {
+	key string
+	// candidates are (service, port) pairs; the first existing Service wins.
+	candidates []serviceRef
+	required   bool
+	// domainToo marks targets that are not exposed via the ingress (actuator
+	// management ports, Elasticsearch) and so need a tunnel in domain mode too.
+	domainToo bool
+	// inCluster marks a configured URL naming an in-cluster Service, which
+	// the runner cannot reach: it is tunnelled even when set explicitly.
+	inCluster bool
+	enabled   bool
+}
+
+type serviceRef struct {
+	name string
+	port int
+}
+
+func forwardTargets(cfg *config.Config) []forwardTarget {
+	rel := cfg.ReleaseName
+	keycloak := []serviceRef{{rel + "-keycloak", 80}, {"keycloak-service", 18080}}
+	keycloakSvc, keycloakInCluster := inClusterService(cfg.KeycloakURL)
+	if keycloakInCluster {
+		keycloak = []serviceRef{keycloakSvc}
+	}
+	return []forwardTarget{
+		{
+			key:        "TEST_ZEEBE_GATEWAY_URL",
+			candidates: []serviceRef{{rel + "-zeebe-gateway", 8080}},
+			required:   true,
+			enabled:    true,
+		},
+		{
+			key:        "TEST_ORCHESTRATION_URL",
+			candidates: []serviceRef{{rel + "-zeebe-gateway", 9600}},
+			required:   true,
+			domainToo:  true,
+			enabled:    true,
+		},
+		{
+			// Same candidates the CI action probes (in-cluster Keycloak URLs
+			// are http://<release>-keycloak:80/auth or keycloak-service:18080).
+			// Like the action, an in-cluster URL is forwarded in domain mode
+			// too, because the ingress does not always expose Keycloak.
+			key:        "TEST_KEYCLOAK_URL",
+			candidates: keycloak,
+			domainToo:  keycloakInCluster,
+			inCluster:  keycloakInCluster,
+			enabled:    cfg.UsesOIDC() && cfg.OIDCTokenURL == "",
+		},
+		{
+			key:        "TEST_CONNECTORS_URL",
+			candidates: []serviceRef{{rel + "-connectors", 8080}},
+			domainToo:  true,
+			enabled:    true,
+		},
+		{
+			key:        "TEST_IDENTITY_URL",
+			candidates: []serviceRef{{rel + "-identity", 8080}},
+			enabled:    true,
+		},
+		{
+			key:        "TEST_OPTIMIZE_URL",
+			candidates: []serviceRef{{rel + "-optimize", 8083}},
+			enabled:    cfg.OptimizeEnabled,
+		},
+		{
+			key:        "TEST_WEBMODELER_URL",
+			candidates: []serviceRef{{rel + "-web-modeler-webapp", 8070}},
+			enabled:    cfg.HubEnabled,
+		},
+		{
+			key: "TEST_ELASTICSEARCH_URL",
+			candidates: []serviceRef{
+				{rel + "-elasticsearch", 9200},
+				{"elasticsearch-master", 9200},
+				{rel + "-elasticsearch-master", 9200},
+				{"elasticsearch-es-http", 9200},
+			},
+			domainToo: true,
+			enabled:   cfg.ElasticsearchEnabled,
+		},
+	}
+}
+
+// currentURL returns the Config field addressed by a forwardTarget key.
+func currentURL(cfg *config.Config, key string) string {
+	switch key {
+	case "TEST_ZEEBE_GATEWAY_URL":
+		return cfg.ZeebeGatewayURL
+	case "TEST_ORCHESTRATION_URL":
+		return cfg.OrchestrationURL
+	case "TEST_KEYCLOAK_URL":
+		return cfg.KeycloakURL
+	case "TEST_CONNECTORS_URL":
+		return cfg.ConnectorsURL
+	case "TEST_IDENTITY_URL":
+		return cfg.IdentityURL
+	case "TEST_OPTIMIZE_URL":
+		return cfg.OptimizeURL
+	case "TEST_WEBMODELER_URL":
+		return cfg.WebModelerURL
+	case "TEST_ELASTICSEARCH_URL":
+		return cfg.ElasticsearchURL
+	}
+	return ""
+}
+
+// StartPortForwards tunnels every in-cluster service the suite talks to and
+// rewrites the matching Config URLs to the local plain-HTTP endpoints,
+// keeping their path. URLs the caller set explicitly (any source other than the
+// built-in default) are left untouched unless they name an in-cluster Service. Missing optional Services are
+// skipped; a failure on a required one (the Zeebe gateway) closes all
+// tunnels opened so far and returns an error. The caller owns the returned
+// PortForwarder and must Close it once the tests have run.
+func StartPortForwards(cfg *config.Config) (*PortForwarder, error) {
+	pf := NewPortForwarder(cfg)
+	for _, target := range forwardTargets(cfg) {
+		if !target.enabled || (cfg.HasDomain() && !target.domainToo) {
+			continue
+		}
+		if cfg.Source(target.key) != config.SourceDefault && !target.inCluster {
+			pf.Logf("%s set explicitly (%s); not forwarding", target.key, cfg.Source(target.key))
+			continue
+		}
+
+		var svc *serviceRef
+		for i := range target.candidates {
+			if pf.ServiceExists(target.candidates[i].name) {
+				svc = &target.candidates[i]
+				break
+			}
+		}
+		if svc == nil {
+			if target.required {
+				pf.Close()
+				return nil, fmt.Errorf("no Service found for %s in namespace %s (tried %v)", target.key, cfg.Namespace, target.candidates)
+			}
+			pf.Logf("no Service found for %s; skipping", target.key)
+			continue
+		}
+
+		tunnel, err := pf.Forward(svc.name, svc.port)
+		if err != nil {
+			if target.required {
+				pf.Close()
+				return nil, err
+			}
+			pf.Logf("%v; skipping %s", err, target.key)
+			continue
+		}
+
+		local, err := rewriteHost(currentURL(cfg, target.key), tunnel.LocalAddr)
+		if err != nil {
+			pf.Close()
+			return nil, fmt.Errorf("%s: %w", target.key, err)
+		}
+		cfg.SetURL(target.key, local, config.SourcePortForward)
+	}
+	return pf, nil
+}
+
+// inClusterService returns the Service that raw names when its host is an
+// in-cluster DNS name ("svc" or "svc.ns.svc[.cluster.local]"), which only
+// resolves inside the cluster.
+func inClusterService(raw string) (serviceRef, bool) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Hostname() == "" || u.Hostname() == "localhost" || net.ParseIP(u.Hostname()) != nil {
+		return serviceRef{}, false
+	}
+	labels := strings.Split(u.Hostname(), ".")
+	if len(labels) != 1 && (len(labels) < 3 || labels[2] != "svc") {
+		return serviceRef{}, false
+	}
+	port := 80
+	if u.Scheme == "https" {
+		port = 443
+	}
+	if p := u.Port(); p != "" {
+		if port, err = strconv.Atoi(p); err != nil {
+			return serviceRef{}, false
+		}
+	}
+	return serviceRef{labels[0], port}, true
+}
+
+// rewriteHost points raw at the tunnel listening on addr, keeping the path.
+// The scheme becomes http: kubectl forwards to the plain-HTTP Service port,
+// whereas domain-mode defaults are https URLs of the TLS-terminating ingress.
+func rewriteHost(raw, addr string) (string, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", err
+	}
+	u.Scheme = "http"
+	u.Host = addr
+	return u.String(), nil
+}
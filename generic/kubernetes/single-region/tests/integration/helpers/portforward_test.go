@@ -0,0 +1,47 @@
+package helpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewriteHost(t *testing.T) {
+	cases := []struct {
+		name, raw, want string
+	}{
+		{"port-forward default", "http://localhost:9600", "http://127.0.0.1:4321"},
+		{"domain orchestration", "https://camunda.example.com", "http://127.0.0.1:4321"},
+		{"domain connectors keeps path", "https://camunda.example.com/connectors", "http://127.0.0.1:4321/connectors"},
+		{"in-cluster service", "http://camunda-identity:8080", "http://127.0.0.1:4321"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := rewriteHost(tc.raw, "127.0.0.1:4321")
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestInClusterService(t *testing.T) {
+	cases := []struct {
+		raw  string
+		want serviceRef
+		ok   bool
+	}{
+		{"http://keycloak-service:18080/auth", serviceRef{"keycloak-service", 18080}, true},
+		{"http://camunda-keycloak/auth", serviceRef{"camunda-keycloak", 80}, true},
+		{"http://camunda-keycloak.camunda.svc.cluster.local:80/auth", serviceRef{"camunda-keycloak", 80}, true},
+		{"https://camunda.example.com/auth", serviceRef{}, false},
+		{"https://example.com/auth", serviceRef{}, false},
+		{"http://localhost:18080/auth", serviceRef{}, false},
+		{"http://127.0.0.1:18080/auth", serviceRef{}, false},
+	}
+	for _, tc := range cases {
+		got, ok := inClusterService(tc.raw)
+		assert.Equal(t, tc.ok, ok, tc.raw)
+		assert.Equal(t, tc.want, got, tc.raw)
+	}
+}
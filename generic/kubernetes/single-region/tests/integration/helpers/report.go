@@ -0,0 +1,357 @@
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/config"
+)
+
+// Check statuses in a Report.
+const (
+	CheckPassed  = "passed"
+	CheckFailed  = "failed"
+	CheckSkipped = "skipped"
+)
+
+// CheckResult is one test (or subtest) in a Report. Retries counts the
+// failed attempts RetryPolicy.Do retried on the test's behalf.
+type CheckResult struct {
+	Name            string  `json:"name"`
+	Status          string  `json:"status"`
+	DurationSeconds float64 `json:"durationSeconds"`
+	Retries         int     `json:"retries"`
+	SkipReason      string  `json:"skipReason,omitempty"`
+}
+
+// Report is the machine-readable summary of one suite run.
+type Report struct {
+	Suite             string            `json:"suite"`
+	Started           time.Time         `json:"started"`
+	Finished          time.Time         `json:"finished"`
+	ConfigFingerprint string            `json:"configFingerprint"`
+	AuthMode          string            `json:"authMode"`
+	SecondaryStorage  string            `json:"secondaryStorage"`
+	Components        map[string]string `json:"components"`
+	Passed            int               `json:"passed"`
+	Failed            int               `json:"failed"`
+	Skipped           int               `json:"skipped"`
+	Checks            []CheckResult     `json:"checks"`
+}
+
+// Reporter collects check results while a suite runs and writes them as
+// JSON and JUnit XML. TestMain creates one with NewReporter; tests register
+// with Track and skip with Skip/Skipf so the reason is recorded (the testing
+// package does not expose it). Only one Reporter is active per test binary.
+type Reporter struct {
+	mu     sync.Mutex
+	report Report
+	checks map[string]*CheckResult
+	starts map[string]time.Time
+}
+
+var (
+	activeMu sync.Mutex
+	active   *Reporter
+)
+
+// NewReporter starts a report for suite and makes it the active Reporter.
+func NewReporter(suite string, cfg *config.Config) *Reporter {
+	r := &Reporter{
+		report: Report{
+			Suite:             suite,
+			Started:           time.Now().UTC(),
+			ConfigFingerprint: cfg.Fingerprint(),
+			AuthMode:          cfg.AuthMode,
+			SecondaryStorage:  cfg.SecondaryStorage,
+			Components:        map[string]string{},
+		},
+		checks: map[string]*CheckResult{},
+		starts: map[string]time.Time{},
+	}
+	activeMu.Lock()
+	active = r
+	activeMu.Unlock()
+	return r
+}
+
+func activeReporter() *Reporter {
+	activeMu.Lock()
+	defer activeMu.Unlock()
+	return active
+}
+
+// Track records tb as a check: its duration, retries and final status are
+// captured when it finishes. Call it first thing in each top-level test.
+// Without an active Reporter it does nothing.
+func Track(tb testing.TB) {
+	r := activeReporter()
+	if r == nil {
+		return
+	}
+	name := tb.Name()
+	r.mu.Lock()
+	r.check(name)
+	r.starts[name] = time.Now()
+	r.mu.Unlock()
+
+	tb.Cleanup(func() {
+		status := CheckPassed
+		switch {
+		case tb.Failed():
+			status = CheckFailed
+		case tb.Skipped():
+			status = CheckSkipped
+		}
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		c := r.check(name)
+		c.Status = status
+		c.DurationSeconds = time.Since(r.starts[name]).Seconds()
+	})
+}
+
+// Skip records the reason with the active Reporter, then skips tb.
+func Skip(tb testing.TB, args ...interface{}) {
+	tb.Helper()
+	recordSkip(tb, strings.TrimSuffix(fmt.Sprintln(args...), "\n"))
+	tb.Skip(args...)
+}
+
+// Skipf is Skip with a format string.
+func Skipf(tb testing.TB, format string, args ...interface{}) {
+	tb.Helper()
+	recordSkip(tb, fmt.Sprintf(format, args...))
+	tb.Skipf(format, args...)
+}
+
+// LogCleanup logs err, if any, from removing what in a cleanup: a leftover
+// test object is worth a note but not a failure.
+func LogCleanup(tb testing.TB, what string, err error) {
+	if err != nil {
+		tb.Logf("cleanup: removing %s: %v", what, err)
+	}
+}
+
+func recordSkip(tb testing.TB, reason string) {
+	r := activeReporter()
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c := r.check(tb.Name())
+	c.Status = CheckSkipped
+	c.SkipReason = reason
+}
+
+// countRetry attributes one retry to the tracked check tb belongs to: tb
+// itself or its nearest tracked parent.
+func countRetry(tb testing.TB) {
+	r := activeReporter()
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for name := tb.Name(); name != ""; {
+		if c, ok := r.checks[name]; ok {
+			c.Retries++
+			return
+		}
+		i := strings.LastIndex(name, "/")
+		if i < 0 {
+			return
+		}
+		name = name[:i]
+	}
+}
+
+// check returns the result for name, creating it on first use. r.mu must be
+// held.
+func (r *Reporter) check(name string) *CheckResult {
+	c, ok := r.checks[name]
+	if !ok {
+		c = &CheckResult{Name: name, Status: CheckPassed}
+		r.checks[name] = c
+	}
+	return c
+}
+
+// SetComponent records a component version.
+func (r *Reporter) SetComponent(name, version string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.report.Components[name] = version
+}
+
+// CollectVersions records the gateway version from /v2/topology and the
+// build version each enabled component reports on /actuator/info.
+// Components that do not answer are recorded as "unknown" so the report
+// shows they were asked.
+func (r *Reporter) CollectVersions(ctx context.Context, c *Client, cfg *config.Config) {
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	version := "unknown"
+	if resp, err := c.GetContext(ctx, strings.TrimRight(cfg.ZeebeGatewayURL, "/")+"/v2/topology"); err == nil {
+		var topology struct {
+			GatewayVersion string `json:"gatewayVersion"`
+		}
+		if body, err := ReadBody(resp); err == nil && json.Unmarshal([]byte(body), &topology) == nil && topology.GatewayVersion != "" {
+			version = topology.GatewayVersion
+		}
+	}
+	r.SetComponent("gateway", version)
+
+	components := []struct {
+		name    string
+		url     string
+		enabled bool
+	}{
+		{"orchestration", cfg.OrchestrationURL, true},
+		{"connectors", cfg.ConnectorsURL, true},
+		{"identity", cfg.IdentityURL, cfg.IdentityURL != ""},
+		{"optimize", cfg.OptimizeURL, cfg.OptimizeEnabled},
+		{"webModeler", cfg.WebModelerURL, cfg.HubEnabled},
+	}
+	for _, comp := range components {
+		if !comp.enabled || comp.url == "" {
+			continue
+		}
+		version := "unknown"
+		if info, err := c.Info(ctx, comp.url); err == nil && info.Build.Version != "" {
+			version = info.Build.Version
+		}
+		r.SetComponent(comp.name, version)
+	}
+}
+
+// Report returns a snapshot of the report with checks sorted by name and
+// the totals filled in.
+func (r *Reporter) Report() Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rep := r.report
+	rep.Finished = time.Now().UTC()
+	rep.Components = map[string]string{}
+	for k, v := range r.report.Components {
+		rep.Components[k] = v
+	}
+	rep.Checks = make([]CheckResult, 0, len(r.checks))
+	for _, c := range r.checks {
+		rep.Checks = append(rep.Checks, *c)
+		switch c.Status {
+		case CheckPassed:
+			rep.Passed++
+		case CheckFailed:
+			rep.Failed++
+		case CheckSkipped:
+			rep.Skipped++
+		}
+	}
+	sort.Slice(rep.Checks, func(a, b int) bool { return rep.Checks[a].Name < rep.Checks[b].Name })
+	return rep
+}
+
+// Write stores the report in dir as <suite>-report.json and
+// <suite>-junit.xml, creating dir if needed.
+func (r *Reporter) Write(dir string) error {
+	rep := r.Report()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating report dir: %w", err)
+	}
+	data, err := json.MarshalIndent(rep, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, rep.Suite+"-report.json"), append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing JSON report: %w", err)
+	}
+	data, err = xml.MarshalIndent(rep.junit(), "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append([]byte(xml.Header), data...)
+	if err := os.WriteFile(filepath.Join(dir, rep.Suite+"-junit.xml"), append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing JUnit report: %w", err)
+	}
+	return nil
+}
+
+type junitSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Time       string          `xml:"time,attr"`
+	Timestamp  string          `xml:"timestamp,attr"`
+	Properties []junitProperty `xml:"properties>property"`
+	Cases      []junitCase     `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Skipped   *junitMessage `xml:"skipped"`
+	Failure   *junitMessage `xml:"failure"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+}
+
+func (rep Report) junit() junitSuites {
+	s := junitSuite{
+		Name:      rep.Suite,
+		Tests:     len(rep.Checks),
+		Failures:  rep.Failed,
+		Skipped:   rep.Skipped,
+		Time:      fmt.Sprintf("%.3f", rep.Finished.Sub(rep.Started).Seconds()),
+		Timestamp: rep.Started.Format(time.RFC3339),
+		Properties: []junitProperty{
+			{Name: "configFingerprint", Value: rep.ConfigFingerprint},
+			{Name: "authMode", Value: rep.AuthMode},
+			{Name: "secondaryStorage", Value: rep.SecondaryStorage},
+		},
+	}
+	names := make([]string, 0, len(rep.Components))
+	for name := range rep.Components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		s.Properties = append(s.Properties, junitProperty{Name: "version." + name, Value: rep.Components[name]})
+	}
+	for _, c := range rep.Checks {
+		jc := junitCase{Name: c.Name, Classname: rep.Suite, Time: fmt.Sprintf("%.3f", c.DurationSeconds)}
+		switch c.Status {
+		case CheckSkipped:
+			jc.Skipped = &junitMessage{Message: c.SkipReason}
+		case CheckFailed:
+			jc.Failure = &junitMessage{Message: fmt.Sprintf("failed after %d retries; see the test log", c.Retries)}
+		}
+		s.Cases = append(s.Cases, jc)
+	}
+	return junitSuites{Suites: []junitSuite{s}}
+}
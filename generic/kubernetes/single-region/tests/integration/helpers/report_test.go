@@ -0,0 +1,66 @@
+package helpers
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/config"
+)
+
+func TestReporter(t *testing.T) {
+	r := NewReporter("unit", &config.Config{AuthMode: "basic", SecondaryStorage: "none"})
+	t.Cleanup(func() { active = nil })
+
+	t.Run("Retried", func(t *testing.T) {
+		Track(t)
+		t.Run("Sub", func(t *testing.T) {
+			attempts := 0
+			err := ConstantRetry(3, time.Millisecond).Do(t.Context(), t, func() error {
+				if attempts++; attempts < 3 {
+					return errors.New("not yet")
+				}
+				return nil
+			})
+			require.NoError(t, err)
+		})
+	})
+	t.Run("Skipped", func(t *testing.T) {
+		Track(t)
+		Skipf(t, "feature %s disabled", "x")
+	})
+	r.SetComponent("gateway", "8.8.0")
+
+	dir := t.TempDir()
+	require.NoError(t, r.Write(dir))
+
+	data, err := os.ReadFile(filepath.Join(dir, "unit-report.json"))
+	require.NoError(t, err)
+	var rep Report
+	require.NoError(t, json.Unmarshal(data, &rep))
+	assert.Equal(t, "basic", rep.AuthMode)
+	assert.Equal(t, map[string]string{"gateway": "8.8.0"}, rep.Components)
+	require.Len(t, rep.Checks, 2)
+	assert.Equal(t, CheckResult{Name: "TestReporter/Retried", Status: CheckPassed, Retries: 2, DurationSeconds: rep.Checks[0].DurationSeconds}, rep.Checks[0])
+	assert.Equal(t, CheckSkipped, rep.Checks[1].Status)
+	assert.Equal(t, "feature x disabled", rep.Checks[1].SkipReason)
+	assert.Equal(t, 1, rep.Passed)
+	assert.Equal(t, 1, rep.Skipped)
+
+	data, err = os.ReadFile(filepath.Join(dir, "unit-junit.xml"))
+	require.NoError(t, err)
+	var suites junitSuites
+	require.NoError(t, xml.Unmarshal(data, &suites))
+	require.Len(t, suites.Suites, 1)
+	assert.Equal(t, 2, suites.Suites[0].Tests)
+	assert.Equal(t, 1, suites.Suites[0].Skipped)
+	require.NotNil(t, suites.Suites[0].Cases[1].Skipped)
+	assert.Equal(t, "feature x disabled", suites.Suites[0].Cases[1].Skipped.Message)
+}
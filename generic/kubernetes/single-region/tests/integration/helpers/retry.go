@@ -0,0 +1,239 @@
+package helpers
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// RetryPolicy retries an operation with constant, exponential or jittered
+// backoff until it succeeds, fails permanently (see IsRetryable), runs out
+// of attempts or passes its wall-clock Timeout. The zero value makes a single
+// attempt.
+type RetryPolicy struct {
+	// MaxAttempts caps the number of tries; 0 means unlimited, in which case
+	// Timeout must be set.
+	MaxAttempts int
+	// Timeout bounds total wall-clock time. Sleeps are clamped so the policy
+	// never waits past it; the last try may still run up to one request
+	// timeout beyond. 0 means no deadline.
+	Timeout time.Duration
+	// InitialDelay is the wait after the first failure. Each further wait is
+	// multiplied by Multiplier (values < 1 are treated as 1, i.e. constant)
+	// and capped at MaxDelay when MaxDelay > 0.
+	InitialDelay time.Duration
+	Multiplier   float64
+	MaxDelay     time.Duration
+	// Jitter randomises each wait by up to ±Jitter (a fraction in [0, 1]) so
+	// parallel callers do not retry in lockstep.
+	Jitter float64
+	// Retryable classifies errors; nil uses IsRetryable.
+	Retryable func(error) bool
+}
+
+// ConstantRetry retries up to attempts times with a fixed delay in between.
+func ConstantRetry(attempts int, delay time.Duration) RetryPolicy {
+	return RetryPolicy{MaxAttempts: attempts, InitialDelay: delay}
+}
+
+// DeadlineRetry polls every interval until timeout elapses, like the
+// componentReadyTimeout loop in core.
+func DeadlineRetry(timeout, interval time.Duration) RetryPolicy {
+	return RetryPolicy{Timeout: timeout, InitialDelay: interval}
+}
+
+// ExponentialRetry doubles the wait from initial up to max, with 20% jitter,
+// until timeout elapses.
+func ExponentialRetry(initial, max, timeout time.Duration) RetryPolicy {
+	return RetryPolicy{Timeout: timeout, InitialDelay: initial, Multiplier: 2, MaxDelay: max, Jitter: 0.2}
+}
+
+// propagationTimeout bounds how long a new user, membership, authorization or
+// exported record may take to become effective: basic-auth logins and
+// authorization checks are resolved against secondary storage, which the
+// exporter fills asynchronously.
+const (
+	propagationTimeout  = 2 * time.Minute
+	propagationInterval = 5 * time.Second
+)
+
+// PropagationRetry polls until a new user, membership, authorization or
+// exported record is effective. 401, 403 and 404 are the expected answers
+// until then, so they are retried on top of what IsRetryable retries.
+func PropagationRetry() RetryPolicy {
+	p := DeadlineRetry(propagationTimeout, propagationInterval)
+	p.Retryable = func(err error) bool {
+		switch StatusCode(err) {
+		case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound:
+			return true
+		}
+		return IsRetryable(err)
+	}
+	return p
+}
+
+// delay returns the wait before attempt n+1 (n is 1-based).
+func (p RetryPolicy) delay(n int) time.Duration {
+	d := float64(p.InitialDelay)
+	if p.Multiplier > 1 {
+		for i := 1; i < n; i++ {
+			d *= p.Multiplier
+			if p.MaxDelay > 0 && d > float64(p.MaxDelay) {
+				break
+			}
+		}
+	}
+	if p.MaxDelay > 0 && d > float64(p.MaxDelay) {
+		d = float64(p.MaxDelay)
+	}
+	if p.Jitter > 0 {
+		d += d * p.Jitter * (2*rand.Float64() - 1) //nolint:gosec // jitter, not crypto
+	}
+	return time.Duration(d)
+}
+
+// Do runs fn until it succeeds or the policy gives up. ctx cancels the
+// retry loop (pass t.Context()); tb, when non-nil, receives one log line per
+// failed attempt, and each retry is counted in the active Reporter. The
+// returned error wraps the last error from fn.
+func (p RetryPolicy) Do(ctx context.Context, tb testing.TB, fn func() error) error {
+	if tb != nil {
+		tb.Helper()
+	}
+	if p.MaxAttempts <= 0 && p.Timeout <= 0 {
+		p.MaxAttempts = 1
+	}
+	retryable := p.Retryable
+	if retryable == nil {
+		retryable = IsRetryable
+	}
+	var deadline time.Time
+	if p.Timeout > 0 {
+		deadline = time.Now().Add(p.Timeout)
+	}
+	start := time.Now()
+
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		if !retryable(err) {
+			return fmt.Errorf("not retryable (attempt %d): %w", attempt, err)
+		}
+		if p.MaxAttempts > 0 && attempt >= p.MaxAttempts {
+			return fmt.Errorf("failed after %d attempts: %w", attempt, err)
+		}
+		wait := p.delay(attempt)
+		if !deadline.IsZero() {
+			remaining := time.Until(deadline)
+			if remaining <= 0 {
+				return fmt.Errorf("gave up after %s (%d attempts): %w", time.Since(start).Round(time.Second), attempt, err)
+			}
+			if wait > remaining {
+				wait = remaining
+			}
+		}
+		if tb != nil {
+			tb.Logf("attempt %d failed, retrying in %s: %v", attempt, wait.Round(time.Millisecond), err)
+			countRetry(tb)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w after %d attempts: %w", ctx.Err(), attempt, err)
+		case <-time.After(wait):
+		}
+	}
+}
+
+// StatusError is an unexpected HTTP status. IsRetryable treats 5xx, 408 and
+// 429 as transient and every other status as permanent.
+type StatusError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Body       string
+}
+
+// NewStatusError builds a StatusError for resp with an already-read body.
+func NewStatusError(resp *http.Response, body string) *StatusError {
+	e := &StatusError{StatusCode: resp.StatusCode, Body: body}
+	if resp.Request != nil {
+		e.Method = resp.Request.Method
+		e.Path = resp.Request.URL.Path
+	}
+	return e
+}
+
+func (e *StatusError) Error() string {
+	if e.Method == "" {
+		return fmt.Sprintf("unexpected status %d: %s", e.StatusCode, e.Body)
+	}
+	return fmt.Sprintf("%s %s returned %d: %s", e.Method, e.Path, e.StatusCode, e.Body)
+}
+
+// StatusCode returns the status of the StatusError in err's chain, or 0 when
+// there is none.
+func StatusCode(err error) int {
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.StatusCode
+	}
+	return 0
+}
+
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as not worth retrying.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanentError{err: err}
+}
+
+// IsRetryable separates transient failures from permanent ones:
+//
+//   - permanent: errors wrapped with Permanent, 4xx StatusErrors (other than
+//     408/429), a canceled context and TLS certificate errors;
+//   - transient: 5xx/408/429 StatusErrors, connection refused/reset, DNS
+//     lookup failures (ingress/external-DNS propagation lag), timeouts and
+//     truncated responses.
+//
+// Anything else (typically "not there yet" assertions on eventually
+// consistent search results) is treated as transient.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var perm permanentError
+	if errors.As(err, &perm) {
+		return false
+	}
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.StatusCode >= 500 || se.StatusCode == http.StatusRequestTimeout || se.StatusCode == http.StatusTooManyRequests
+	}
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var (
+		unknownAuthority x509.UnknownAuthorityError
+		hostname         x509.HostnameError
+		invalid          x509.CertificateInvalidError
+	)
+	if errors.As(err, &unknownAuthority) || errors.As(err, &hostname) || errors.As(err, &invalid) {
+		return false
+	}
+	// Connection refused/reset, DNS errors, timeouts, truncated bodies and
+	// everything unclassified are retried.
+	return true
+}
@@ -0,0 +1,108 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsRetryable(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"5xx", &StatusError{StatusCode: http.StatusBadGateway}, true},
+		{"429", &StatusError{StatusCode: http.StatusTooManyRequests}, true},
+		{"4xx", fmt.Errorf("deploy: %w", &StatusError{StatusCode: http.StatusBadRequest}), false},
+		{"connection refused", &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, true},
+		{"dns not found", &net.DNSError{Err: "no such host", Name: "zeebe.example", IsNotFound: true}, true},
+		{"canceled", fmt.Errorf("GET /v2/topology: %w", context.Canceled), false},
+		{"permanent", Permanent(errors.New("bad fixture")), false},
+		{"unclassified", errors.New("process not found in search results"), true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, IsRetryable(tc.err))
+		})
+	}
+}
+
+func TestPropagationRetryable(t *testing.T) {
+	retryable := PropagationRetry().Retryable
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"401", &StatusError{StatusCode: http.StatusUnauthorized}, true},
+		{"403", fmt.Errorf("search: %w", &StatusError{StatusCode: http.StatusForbidden}), true},
+		{"404", &StatusError{StatusCode: http.StatusNotFound}, true},
+		{"400", &StatusError{StatusCode: http.StatusBadRequest}, false},
+		{"5xx", &StatusError{StatusCode: http.StatusServiceUnavailable}, true},
+		{"permanent", Permanent(errors.New("wrong tenant")), false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, retryable(tc.err))
+		})
+	}
+}
+
+func TestStatusCode(t *testing.T) {
+	assert.Equal(t, http.StatusForbidden, StatusCode(fmt.Errorf("get: %w", &StatusError{StatusCode: http.StatusForbidden})))
+	assert.Equal(t, 0, StatusCode(errors.New("connection reset")))
+	assert.Equal(t, 0, StatusCode(nil))
+}
+
+func TestRetryPolicyStopsOnPermanentError(t *testing.T) {
+	calls := 0
+	err := ConstantRetry(5, time.Millisecond).Do(t.Context(), t, func() error {
+		calls++
+		if calls == 1 {
+			return &StatusError{StatusCode: http.StatusServiceUnavailable}
+		}
+		return &StatusError{StatusCode: http.StatusForbidden}
+	})
+	require.Error(t, err)
+	assert.Equal(t, 2, calls)
+	var se *StatusError
+	require.ErrorAs(t, err, &se)
+	assert.Equal(t, http.StatusForbidden, se.StatusCode)
+}
+
+func TestRetryPolicyDeadline(t *testing.T) {
+	start := time.Now()
+	calls := 0
+	err := DeadlineRetry(50*time.Millisecond, 20*time.Millisecond).Do(t.Context(), t, func() error {
+		calls++
+		return errors.New("not ready")
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "gave up after")
+	assert.Less(t, time.Since(start), time.Second)
+	assert.GreaterOrEqual(t, calls, 3)
+}
+
+func TestRetryPolicyExponentialDelay(t *testing.T) {
+	p := RetryPolicy{InitialDelay: time.Second, Multiplier: 2, MaxDelay: 5 * time.Second}
+	assert.Equal(t, time.Second, p.delay(1))
+	assert.Equal(t, 2*time.Second, p.delay(2))
+	assert.Equal(t, 4*time.Second, p.delay(3))
+	assert.Equal(t, 5*time.Second, p.delay(4))
+
+	p.Jitter = 0.5
+	for i := 0; i < 20; i++ {
+		d := p.delay(2)
+		assert.GreaterOrEqual(t, d, time.Second)
+		assert.LessOrEqual(t, d, 3*time.Second)
+	}
+}
@@ -0,0 +1,220 @@
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/config"
+)
+
+// SearchEngineAuth returns the request authenticator for the configured
+// Elasticsearch or OpenSearch cluster: SigV4 for AWS OpenSearch when
+// TEST_OPENSEARCH_AWS_REGION is set, basic auth when a user is configured,
+// nothing otherwise.
+func SearchEngineAuth(cfg *config.Config) (func(*http.Request) error, error) {
+	user, pass := cfg.ElasticsearchUser, cfg.ElasticsearchPassword
+	if cfg.SecondaryStorage == "opensearch" {
+		if cfg.OpenSearchAWSRegion != "" {
+			creds, err := AWSCredentialsFromEnv()
+			if err != nil {
+				return nil, err
+			}
+			signer := SigV4Signer{Credentials: creds, Region: cfg.OpenSearchAWSRegion, Service: cfg.OpenSearchAWSService}
+			return func(req *http.Request) error { return signer.Sign(req, time.Now()) }, nil
+		}
+		user, pass = cfg.OpenSearchUser, cfg.OpenSearchPassword
+	}
+	return func(req *http.Request) error {
+		if user != "" {
+			req.SetBasicAuth(user, pass)
+		}
+		return nil
+	}, nil
+}
+
+// ErrNoSnapshots means the configured secondary storage has no snapshot
+// API: an RDBMS, OpenSearch Serverless or no secondary storage at all.
+var ErrNoSnapshots = errors.New("secondary storage does not support snapshots")
+
+// Snapshots drives the _snapshot API shared by Elasticsearch and OpenSearch.
+// The repository must already be registered on the cluster (the reference
+// deployments register it, e.g. camunda_backup on S3).
+type Snapshots struct {
+	baseURL string
+	auth    func(*http.Request) error
+	http    *http.Client
+}
+
+// NewSnapshots returns a Snapshots client for cfg's secondary storage, or
+// ErrNoSnapshots when it has none.
+func NewSnapshots(cfg *config.Config) (*Snapshots, error) {
+	var baseURL string
+	switch {
+	case cfg.SecondaryStorage == "elasticsearch":
+		baseURL = cfg.ElasticsearchURL
+	case cfg.SecondaryStorage == "opensearch" && cfg.OpenSearchAWSService != "aoss":
+		baseURL = cfg.OpenSearchURL
+	default:
+		return nil, fmt.Errorf("%s: %w", cfg.SecondaryStorage, ErrNoSnapshots)
+	}
+	auth, err := SearchEngineAuth(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Snapshots{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		auth:    auth,
+		http: &http.Client{
+			// Snapshots and restores wait for completion server-side.
+			Timeout: 30 * time.Minute,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, //nolint:gosec // self-signed ECK/OpenSearch certs
+			},
+		},
+	}, nil
+}
+
+// SnapshotShards are the shard totals of a snapshot or restore.
+type SnapshotShards struct {
+	Total      int `json:"total"`
+	Failed     int `json:"failed"`
+	Successful int `json:"successful"`
+}
+
+// SnapshotInfo describes a snapshot.
+type SnapshotInfo struct {
+	Snapshot string         `json:"snapshot"`
+	State    string         `json:"state"`
+	Indices  []string       `json:"indices"`
+	Shards   SnapshotShards `json:"shards"`
+}
+
+// RestoreInfo describes a completed restore.
+type RestoreInfo struct {
+	Snapshot string         `json:"snapshot"`
+	Indices  []string       `json:"indices"`
+	Shards   SnapshotShards `json:"shards"`
+}
+
+// Indices returns the names of the indices matching pattern (a
+// comma-separated list of names or wildcards), hidden ones included.
+func (s *Snapshots) Indices(ctx context.Context, pattern string) ([]string, error) {
+	var rows []struct {
+		Index string `json:"index"`
+	}
+	if err := s.do(ctx, http.MethodGet, "/_cat/indices/"+pattern+"?format=json&h=index&expand_wildcards=all", nil, &rows); err != nil {
+		return nil, err
+	}
+	out := make([]string, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, r.Index)
+	}
+	return out, nil
+}
+
+// DeleteIndices deletes the named indices. Names must be explicit: clusters
+// default to action.destructive_requires_name, which rejects wildcards.
+func (s *Snapshots) DeleteIndices(ctx context.Context, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	return s.do(ctx, http.MethodDelete, "/"+strings.Join(names, ","), nil, nil)
+}
+
+// Create takes snapshot name of indices in repository and waits for it to
+// finish. A snapshot that is not SUCCESS or has failed shards is returned
+// together with an error.
+func (s *Snapshots) Create(ctx context.Context, repository, name string, indices []string) (*SnapshotInfo, error) {
+	body := map[string]interface{}{
+		"indices":              strings.Join(indices, ","),
+		"include_global_state": false,
+	}
+	var out struct {
+		Snapshot SnapshotInfo `json:"snapshot"`
+	}
+	path := fmt.Sprintf("/_snapshot/%s/%s?wait_for_completion=true", url.PathEscape(repository), url.PathEscape(name))
+	if err := s.do(ctx, http.MethodPut, path, body, &out); err != nil {
+		return nil, err
+	}
+	info := &out.Snapshot
+	if info.State != "SUCCESS" || info.Shards.Failed > 0 {
+		return info, fmt.Errorf("snapshot %s is %s with %d of %d shards failed", name, info.State, info.Shards.Failed, info.Shards.Total)
+	}
+	return info, nil
+}
+
+// Delete removes snapshot name from repository.
+func (s *Snapshots) Delete(ctx context.Context, repository, name string) error {
+	return s.do(ctx, http.MethodDelete, fmt.Sprintf("/_snapshot/%s/%s", url.PathEscape(repository), url.PathEscape(name)), nil, nil)
+}
+
+// Restore restores indices from snapshot name and waits for it to finish.
+// The indices must not exist (or be closed) on the cluster.
+func (s *Snapshots) Restore(ctx context.Context, repository, name string, indices []string) (*RestoreInfo, error) {
+	body := map[string]interface{}{
+		"indices":              strings.Join(indices, ","),
+		"include_global_state": false,
+	}
+	var out struct {
+		Snapshot RestoreInfo `json:"snapshot"`
+	}
+	path := fmt.Sprintf("/_snapshot/%s/%s/_restore?wait_for_completion=true", url.PathEscape(repository), url.PathEscape(name))
+	if err := s.do(ctx, http.MethodPost, path, body, &out); err != nil {
+		return nil, err
+	}
+	info := &out.Snapshot
+	if info.Shards.Failed > 0 {
+		return info, fmt.Errorf("restore of %s failed on %d of %d shards", name, info.Shards.Failed, info.Shards.Total)
+	}
+	return info, nil
+}
+
+// do sends an authenticated JSON request and decodes the response into out
+// (which may be nil).
+func (s *Snapshots) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if err := s.auth(req); err != nil {
+		return fmt.Errorf("snapshot auth: %w", err)
+	}
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return requestError(req, err)
+	}
+	data, err := ReadBody(resp)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return NewStatusError(resp, data)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(data), out); err != nil {
+		return fmt.Errorf("%s %s: decoding response: %w", method, path, err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package helpers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/config"
+)
+
+func TestSnapshots(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, _ := r.BasicAuth()
+		assert.Equal(t, "elastic:secret", user+":"+pass)
+		switch {
+		case r.URL.Path == "/_cat/indices/operate-*,tasklist-*":
+			fmt.Fprint(w, `[{"index":"operate-list-view-8.3.0_"},{"index":"tasklist-task-8.5.0_"}]`)
+		case r.Method == http.MethodDelete && r.URL.Path == "/operate-list-view-8.3.0_,tasklist-task-8.5.0_":
+			fmt.Fprint(w, `{"acknowledged":true}`)
+		case r.Method == http.MethodPut && r.URL.Path == "/_snapshot/camunda_backup/ok":
+			assert.Equal(t, "true", r.URL.Query().Get("wait_for_completion"))
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "operate-*,tasklist-*", body["indices"])
+			assert.Equal(t, false, body["include_global_state"])
+			fmt.Fprint(w, `{"snapshot":{"snapshot":"ok","state":"SUCCESS","indices":["operate-list-view-8.3.0_"],"shards":{"total":2,"failed":0,"successful":2}}}`)
+		case r.Method == http.MethodPut && r.URL.Path == "/_snapshot/camunda_backup/partial":
+			fmt.Fprint(w, `{"snapshot":{"snapshot":"partial","state":"PARTIAL","shards":{"total":2,"failed":1,"successful":1}}}`)
+		case r.URL.Path == "/_snapshot/camunda_backup/ok/_restore":
+			fmt.Fprint(w, `{"snapshot":{"snapshot":"ok","indices":["operate-list-view-8.3.0_"],"shards":{"total":2,"failed":0,"successful":2}}}`)
+		case r.URL.Path == "/_snapshot/missing/ok/_restore":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":{"type":"repository_missing_exception","reason":"[missing] missing"},"status":404}`)
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusTeapot)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	s, err := NewSnapshots(&config.Config{SecondaryStorage: "elasticsearch", ElasticsearchURL: srv.URL, ElasticsearchUser: "elastic", ElasticsearchPassword: "secret"})
+	require.NoError(t, err)
+
+	names, err := s.Indices(t.Context(), "operate-*,tasklist-*")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"operate-list-view-8.3.0_", "tasklist-task-8.5.0_"}, names)
+	require.NoError(t, s.DeleteIndices(t.Context(), names))
+
+	info, err := s.Create(t.Context(), "camunda_backup", "ok", []string{"operate-*", "tasklist-*"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, info.Shards.Successful)
+
+	info, err = s.Create(t.Context(), "camunda_backup", "partial", []string{"operate-*"})
+	require.EqualError(t, err, "snapshot partial is PARTIAL with 1 of 2 shards failed")
+	assert.Equal(t, "PARTIAL", info.State)
+
+	restored, err := s.Restore(t.Context(), "camunda_backup", "ok", []string{"operate-*"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"operate-list-view-8.3.0_"}, restored.Indices)
+
+	_, err = s.Restore(t.Context(), "missing", "ok", []string{"operate-*"})
+	var se *StatusError
+	require.ErrorAs(t, err, &se)
+	assert.Contains(t, se.Body, "repository_missing_exception")
+}
+
+func TestNewSnapshotsUnsupported(t *testing.T) {
+	for _, cfg := range []*config.Config{
+		{SecondaryStorage: "rdbms"},
+		{SecondaryStorage: "none"},
+		{SecondaryStorage: "opensearch", OpenSearchAWSService: "aoss", OpenSearchAWSRegion: "eu-west-1"},
+	} {
+		_, err := NewSnapshots(cfg)
+		assert.True(t, errors.Is(err, ErrNoSnapshots), "%s: %v", cfg.SecondaryStorage, err)
+	}
+}
@@ -0,0 +1,94 @@
+package helpers
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/config"
+)
+
+// Suite describes one test package for RunSuite.
+type Suite struct {
+	// Name identifies the suite in the report.
+	Name string
+	// NoPortForward leaves TEST_PORT_FORWARD to the tests themselves, for
+	// suites whose release does not exist yet when TestMain runs (upgrade).
+	// Such suites have nothing to query from TestMain, so their report does
+	// not collect component versions either.
+	NoPortForward bool
+	// Setup runs once the config is valid, the tunnels are open and the
+	// client exists, typically storing env in package variables. The cleanup
+	// it returns (which may be nil) runs after the tests, before the report
+	// is written. An error aborts the run.
+	Setup func(env *SuiteEnv) (cleanup func(), err error)
+}
+
+// SuiteEnv is what RunSuite prepares for a test package.
+type SuiteEnv struct {
+	Options *config.Options
+	Config  *config.Config
+	Client  *Client
+}
+
+// RunSuite is the whole TestMain of a test package: it loads and validates
+// the config from flags, profile and environment, opens the port-forwards
+// (see StartPortForwards), calls s.Setup, runs the tests, writes the JSON
+// and JUnit reports to TEST_REPORT_DIR and exits with the tests' code.
+func RunSuite(m *testing.M, s Suite) {
+	os.Exit(s.run(m))
+}
+
+func (s Suite) run(m *testing.M) int {
+	opts := config.BindFlags(flag.CommandLine)
+	flag.Parse()
+
+	cfg, err := config.Load(*opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
+		return 1
+	}
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	if cfg.PortForward && !s.NoPortForward {
+		tunnels, err := StartPortForwards(cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to set up port-forwards: %v\n", err)
+			return 1
+		}
+		defer tunnels.Close()
+	}
+	if testing.Verbose() {
+		fmt.Fprintf(os.Stderr, "config sources:\n%s", cfg.SourceReport())
+	}
+	report := NewReporter(s.Name, cfg)
+	env := &SuiteEnv{Options: opts, Config: cfg, Client: NewClient(cfg)}
+	var cleanup func()
+	if s.Setup != nil {
+		if cleanup, err = s.Setup(env); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to set up the %s suite: %v\n", s.Name, err)
+			return 1
+		}
+	}
+
+	code := m.Run()
+	if cleanup != nil {
+		cleanup()
+	}
+	if cfg.ReportDir != "" {
+		if !s.NoPortForward {
+			report.CollectVersions(context.Background(), env.Client, cfg)
+		}
+		if err := report.Write(cfg.ReportDir); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write report: %v\n", err)
+			if code == 0 {
+				code = 1
+			}
+		}
+	}
+	return code
+}
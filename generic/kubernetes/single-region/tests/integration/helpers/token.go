@@ -0,0 +1,207 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokenExpirySkew is subtracted from expires_in so a token is refreshed
+// before the server rejects it (clock drift, request latency).
+// defaultTokenLifetime is used when the token endpoint omits expires_in; it
+// matches the lifetime the client assumed before expires_in was honoured.
+const (
+	tokenExpirySkew      = 30 * time.Second
+	defaultTokenLifetime = 4 * time.Minute
+)
+
+// TokenSource fetches OAuth2 client_credentials tokens for one client and
+// caches them until shortly before they expire. It is safe for concurrent
+// use, so parallel subtests share a single token instead of racing to fetch
+// their own.
+type TokenSource struct {
+	http         *http.Client
+	tokenURL     string
+	clientID     string
+	clientSecret string
+	// extra holds additional form parameters (audience, scope) sent with
+	// every token request.
+	extra url.Values
+	// assertion, when set, authenticates the client with a signed JWT
+	// (RFC 7523) instead of client_secret.
+	assertion func() (string, error)
+
+	mu            sync.Mutex
+	token         string
+	expiry        time.Time
+	refreshToken  string
+	refreshExpiry time.Time
+	now           func() time.Time
+}
+
+// NewTokenSource returns a TokenSource that posts to tokenURL with hc.
+func NewTokenSource(hc *http.Client, tokenURL, clientID, clientSecret string) *TokenSource {
+	return &TokenSource{
+		http:         hc,
+		tokenURL:     tokenURL,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		now:          time.Now,
+	}
+}
+
+// WithParams adds form parameters (e.g. audience, scope) to every token
+// request. Call it before the source is first used.
+func (s *TokenSource) WithParams(extra url.Values) *TokenSource {
+	s.extra = extra
+	return s
+}
+
+// WithClientAssertion makes the source authenticate with the JWT returned
+// by sign (client_secret_jwt / private_key_jwt) instead of posting the
+// client secret. sign is called for every token request because assertions
+// are single-use. Call it before the source is first used.
+func (s *TokenSource) WithClientAssertion(sign func() (string, error)) *TokenSource {
+	s.assertion = sign
+	return s
+}
+
+// Token returns a cached token, fetching a new one when none is cached or
+// the cached one is within tokenExpirySkew of expiry. A still-valid refresh
+// token is tried first; if the refresh grant fails the source falls back to
+// client_credentials.
+func (s *TokenSource) Token() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if s.token != "" && now.Before(s.expiry) {
+		return s.token, nil
+	}
+
+	if s.refreshToken != "" && now.Before(s.refreshExpiry) {
+		err := s.fetch(url.Values{
+			"grant_type":    {"refresh_token"},
+			"refresh_token": {s.refreshToken},
+		})
+		if err == nil {
+			return s.token, nil
+		}
+		s.refreshToken = ""
+	}
+
+	if err := s.fetch(url.Values{"grant_type": {"client_credentials"}}); err != nil {
+		return "", err
+	}
+	return s.token, nil
+}
+
+// Invalidate drops the cached access token if it is still rejected, so the
+// next Token call fetches a fresh one. Used after a 401, when the server no
+// longer accepts a token that has not reached its advertised expiry. Passing
+// the rejected token means parallel callers hitting the same 401 trigger a
+// single refetch rather than one each.
+func (s *TokenSource) Invalidate(rejected string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != rejected {
+		return
+	}
+	s.token = ""
+	s.expiry = time.Time{}
+}
+
+// fetch performs one token request with grant-specific params and stores the
+// result. Caller must hold s.mu.
+func (s *TokenSource) fetch(params url.Values) error {
+	data := url.Values{"client_id": {s.clientID}}
+	switch {
+	case s.assertion != nil:
+		jwt, err := s.assertion()
+		if err != nil {
+			return fmt.Errorf("signing client assertion: %w", err)
+		}
+		data.Set("client_assertion_type", "urn:ietf:params:oauth:client-assertion-type:jwt-bearer")
+		data.Set("client_assertion", jwt)
+	case s.clientSecret != "":
+		data.Set("client_secret", s.clientSecret)
+	}
+	for k, v := range s.extra {
+		data[k] = v
+	}
+	for k, v := range params {
+		data[k] = v
+	}
+
+	resp, err := s.http.Post(s.tokenURL, "application/x-www-form-urlencoded", strings.NewReader(data.Encode()))
+	if err != nil {
+		return fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("token endpoint returned %d: %s", resp.StatusCode, string(body))
+	}
+	var result struct {
+		AccessToken      string `json:"access_token"`
+		ExpiresIn        int    `json:"expires_in"`
+		RefreshToken     string `json:"refresh_token"`
+		RefreshExpiresIn int    `json:"refresh_expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if result.AccessToken == "" {
+		return fmt.Errorf("empty access_token in response")
+	}
+
+	now := s.now()
+	s.token = result.AccessToken
+	s.expiry = now.Add(lifetime(result.ExpiresIn, defaultTokenLifetime))
+	s.refreshToken = result.RefreshToken
+	if result.RefreshToken != "" {
+		// Keycloak reports refresh_expires_in=0 for offline tokens, which do
+		// not expire on their own; treat that as "as long as the access token".
+		s.refreshExpiry = now.Add(lifetime(result.RefreshExpiresIn, lifetime(result.ExpiresIn, defaultTokenLifetime)))
+	}
+	return nil
+}
+
+// lifetime converts an expires_in value to a cache lifetime, subtracting the
+// skew and never going below half the advertised lifetime for short tokens.
+func lifetime(expiresIn int, def time.Duration) time.Duration {
+	if expiresIn <= 0 {
+		return def
+	}
+	d := time.Duration(expiresIn) * time.Second
+	if d > 2*tokenExpirySkew {
+		return d - tokenExpirySkew
+	}
+	return d / 2
+}
+
+// tokenCache holds one TokenSource per (token URL, client ID) pair.
+type tokenCache struct {
+	mu      sync.Mutex
+	sources map[string]*TokenSource
+}
+
+func (c *tokenCache) get(hc *http.Client, tokenURL, clientID, clientSecret string) *TokenSource {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.sources == nil {
+		c.sources = map[string]*TokenSource{}
+	}
+	key := tokenURL + "\x00" + clientID
+	s, ok := c.sources[key]
+	if !ok || s.clientSecret != clientSecret {
+		s = NewTokenSource(hc, tokenURL, clientID, clientSecret)
+		c.sources[key] = s
+	}
+	return s
+}
@@ -0,0 +1,107 @@
+package helpers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/config"
+)
+
+// tokenServer issues tok-1, tok-2, ... with the given expires_in.
+func tokenServer(t *testing.T, expiresIn int) (*httptest.Server, *int32) {
+	t.Helper()
+	var issued int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&issued, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"tok-%d","expires_in":%d}`, n, expiresIn)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &issued
+}
+
+func TestTokenSourceHonoursExpiresIn(t *testing.T) {
+	srv, issued := tokenServer(t, 120)
+	ts := NewTokenSource(srv.Client(), srv.URL, "client", "secret")
+	now := time.Now()
+	ts.now = func() time.Time { return now }
+
+	tok, err := ts.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "tok-1", tok)
+
+	now = now.Add(80 * time.Second)
+	tok, err = ts.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "tok-1", tok, "token should be cached before expires_in minus skew")
+
+	now = now.Add(20 * time.Second)
+	tok, err = ts.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "tok-2", tok, "token should be refreshed within the skew window")
+	assert.EqualValues(t, 2, atomic.LoadInt32(issued))
+}
+
+func TestTokenSourceConcurrentCallersShareOneFetch(t *testing.T) {
+	srv, issued := tokenServer(t, 300)
+	ts := NewTokenSource(srv.Client(), srv.URL, "client", "secret")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := ts.Token()
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.EqualValues(t, 1, atomic.LoadInt32(issued))
+}
+
+func TestClientRetriesOnceOn401(t *testing.T) {
+	tokens, issued := tokenServer(t, 300)
+
+	var calls int32
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.Header.Get("Authorization") != "Bearer tok-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"filter":{}}` {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(api.Close)
+
+	c := NewClient(&config.Config{
+		AuthMode:     "oidc",
+		OIDCTokenURL: tokens.URL,
+		OIDCClientID: "client",
+		OIDCSecret:   "secret",
+		HTTPTimeout:  5 * time.Second,
+	})
+	resp, err := c.PostJSON(api.URL, `{"filter":{}}`)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.EqualValues(t, 2, atomic.LoadInt32(&calls))
+	assert.EqualValues(t, 2, atomic.LoadInt32(issued))
+
+	tok, err := c.GetTokenForClient(tokens.URL, "client", "secret")
+	require.NoError(t, err)
+	assert.Equal(t, "tok-2", tok, "GetTokenForClient should share the cache used by Do")
+}
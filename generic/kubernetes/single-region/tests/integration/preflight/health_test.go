@@ -0,0 +1,117 @@
+package preflight
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/helpers"
+)
+
+// deepHealthComponents lists every component whose /actuator/health is
+// inspected by TestHealthIndicators. Identity, Optimize and Web Modeler
+// often expose the actuator only on their management port, so they are
+// skipped (not failed) when the configured URL does not serve it or asks
+// for credentials the suite does not have.
+func deepHealthComponents() []Component {
+	components := healthComponents()
+	if cfg.IdentityURL != "" {
+		components = append(components, Component{Name: "Identity", URL: cfg.IdentityURL, Optional: true})
+	}
+	if cfg.OptimizeEnabled && cfg.OptimizeURL != "" {
+		components = append(components, Component{Name: "Optimize", URL: cfg.OptimizeURL, Optional: true})
+	}
+	if cfg.HubEnabled && cfg.WebModelerURL != "" {
+		components = append(components, Component{Name: "WebModeler", URL: cfg.WebModelerURL, Optional: true})
+	}
+	return components
+}
+
+// TestHealthIndicators inspects the sub-indicators of each component's
+// actuator health (broker partitions, exporters, secondary storage, disk
+// space, ...) rather than only the overall status. A component that reports
+// UP overall while an indicator is DOWN or OUT_OF_SERVICE fails with the
+// indicator name; UNKNOWN or custom statuses are logged as warnings.
+func TestHealthIndicators(t *testing.T) {
+	helpers.Track(t)
+	for _, comp := range deepHealthComponents() {
+		t.Run(comp.Name, func(t *testing.T) {
+			// Probe once first: a host the runner cannot resolve or an
+			// endpoint that is not an actuator will not fix itself.
+			_, err := client.Health(t.Context(), comp.URL, "")
+			skipIfNoActuator(t, comp, err)
+
+			var health *helpers.Health
+			err = helpers.ConstantRetry(cfg.RetryAttempts, cfg.RetryDelay).Do(t.Context(), t, func() error {
+				h, err := client.Health(t.Context(), comp.URL, "")
+				if err != nil {
+					return err
+				}
+				health = h
+				if h.Status != helpers.StatusUp {
+					return fmt.Errorf("%s health is %s; failing indicators: %s", comp.Name, h.Status, joinIndicators(h.Down()))
+				}
+				if down := h.Down(); len(down) > 0 {
+					return fmt.Errorf("%s health is UP but indicators are down: %s", comp.Name, joinIndicators(down))
+				}
+				return nil
+			})
+			if health != nil {
+				logIndicators(t, comp, health)
+			}
+			if err != nil {
+				t.Fatalf("%s health: %v", comp.Name, err)
+			}
+			for _, i := range health.Degraded() {
+				t.Logf("WARNING: %s indicator %s", comp.Name, i)
+			}
+		})
+	}
+}
+
+// skipIfNoActuator skips the subtest when comp.URL is unreachable from the
+// runner or does not serve actuator health, and when an optional component
+// answers 401/403 (its health sits behind the application's own login).
+func skipIfNoActuator(t *testing.T, comp Component, err error) {
+	t.Helper()
+	var dnsErr *net.DNSError
+	var se *helpers.StatusError
+	switch {
+	case err == nil:
+	case errors.As(err, &dnsErr):
+		helpers.Skipf(t, "%s URL %s is not resolvable from the runner: %v", comp.Name, comp.URL, err)
+	case errors.Is(err, helpers.ErrNoHealthDocument):
+		helpers.Skipf(t, "%s does not serve actuator health at %s", comp.Name, comp.URL)
+	case errors.As(err, &se) && se.StatusCode == http.StatusNotFound:
+		helpers.Skipf(t, "%s actuator health not exposed at %s (404)", comp.Name, comp.URL)
+	case comp.Optional && errors.As(err, &se) && (se.StatusCode == http.StatusUnauthorized || se.StatusCode == http.StatusForbidden):
+		helpers.Skipf(t, "%s actuator health at %s requires credentials the suite does not have (%d); point the URL at the management port to check it", comp.Name, comp.URL, se.StatusCode)
+	}
+}
+
+func logIndicators(t *testing.T, comp Component, h *helpers.Health) {
+	t.Helper()
+	indicators := h.Indicators()
+	if len(indicators) == 0 {
+		t.Logf("%s health is %s; no sub-indicators exposed (management.endpoint.health.show-components)", comp.Name, h.Status)
+		return
+	}
+	t.Logf("%s health is %s:", comp.Name, h.Status)
+	for _, i := range indicators {
+		t.Logf("  %-40s %-14s %s", i.Path, i.Status, i.Kind())
+	}
+}
+
+func joinIndicators(indicators []helpers.HealthIndicator) string {
+	if len(indicators) == 0 {
+		return "none reported"
+	}
+	parts := make([]string, len(indicators))
+	for i, ind := range indicators {
+		parts[i] = ind.String()
+	}
+	return strings.Join(parts, ", ")
+}
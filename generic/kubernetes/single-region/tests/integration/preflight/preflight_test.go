@@ -3,13 +3,9 @@
 package preflight
 
 import (
-	"context"
-	"crypto/tls"
+	"errors"
 	"fmt"
-	"net/http"
-	"os"
 	"testing"
-	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -23,81 +19,79 @@ var (
 	client *helpers.Client
 )
 
-func TestMain(m *testing.M) {
-	var err error
-	cfg, err = config.FromEnv()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
-		os.Exit(1)
-	}
-	client = helpers.NewClient(cfg)
-	os.Exit(m.Run())
+func TestMain(m *testing.M) { helpers.RunSuite(m, helpers.Suite{Name: "preflight", Setup: setupSuite}) }
+
+func setupSuite(env *helpers.SuiteEnv) (func(), error) {
+	cfg, client = env.Config, env.Client
+	return nil, nil
 }
 
-// esGet performs a GET against Elasticsearch using its own basic-auth
-// credentials (when configured). This bypasses the Camunda OIDC/basic auth
-// client because ES has its own user database (e.g. ECK 'elastic' user).
-func esGet(url string) (*http.Response, error) {
-	c := &http.Client{
-		Timeout: 10 * time.Second,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, //nolint:gosec // self-signed ECK cert
-		},
-	}
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
-	if err != nil {
-		return nil, err
+// secondaryStorage returns the configured backend, closed when t ends, and
+// skips t when none is configured.
+func secondaryStorage(t *testing.T) SecondaryStorage {
+	t.Helper()
+	s, err := NewSecondaryStorage(cfg)
+	require.NoError(t, err)
+	if s == nil {
+		helpers.Skip(t, "no secondary storage configured (TEST_SECONDARY_STORAGE=none)")
 	}
-	if cfg.ElasticsearchUser != "" {
-		req.SetBasicAuth(cfg.ElasticsearchUser, cfg.ElasticsearchPassword)
-	}
-	return c.Do(req)
+	t.Cleanup(func() { _ = s.Close() })
+	return s
 }
 
-// TestElasticsearchReadiness checks that Elasticsearch cluster is reachable.
-func TestElasticsearchReadiness(t *testing.T) {
-	if !cfg.ElasticsearchEnabled {
-		t.Skip("Elasticsearch is not enabled")
-	}
-	url := cfg.ElasticsearchURL + "/_cluster/health?timeout=1s"
-	err := helpers.Retry(cfg.RetryAttempts, cfg.RetryDelay, func() error {
-		resp, err := esGet(url)
-		if err != nil {
-			return fmt.Errorf("request failed: %w", err)
-		}
-		defer resp.Body.Close()
-		if resp.StatusCode != http.StatusOK {
-			return fmt.Errorf("expected 200, got %d", resp.StatusCode)
-		}
-		return nil
+// TestSecondaryStorageReadiness checks that the secondary storage answers.
+func TestSecondaryStorageReadiness(t *testing.T) {
+	helpers.Track(t)
+	s := secondaryStorage(t)
+	err := helpers.ConstantRetry(cfg.RetryAttempts, cfg.RetryDelay).Do(t.Context(), t, func() error {
+		return s.Ready(t.Context())
 	})
-	assert.NoError(t, err, "Elasticsearch should be ready")
+	assert.NoError(t, err, "%s should be ready", s.Name())
 }
 
-// TestElasticsearchLiveness checks Elasticsearch cluster status is green.
-func TestElasticsearchLiveness(t *testing.T) {
-	if !cfg.ElasticsearchEnabled {
-		t.Skip("Elasticsearch is not enabled")
-	}
-	url := cfg.ElasticsearchURL + "/_cluster/health?wait_for_status=green&timeout=1s"
-	err := helpers.Retry(cfg.RetryAttempts, cfg.RetryDelay, func() error {
-		resp, err := esGet(url)
-		if err != nil {
-			return fmt.Errorf("request failed: %w", err)
-		}
-		defer resp.Body.Close()
-		if resp.StatusCode != http.StatusOK {
-			return fmt.Errorf("expected 200, got %d", resp.StatusCode)
+// TestSecondaryStorageHealth checks the cluster colour is green
+// (Elasticsearch/OpenSearch) or that the database is a writable primary
+// (RDBMS), logging shard or replica counts.
+func TestSecondaryStorageHealth(t *testing.T) {
+	helpers.Track(t)
+	s := secondaryStorage(t)
+	var health StorageHealth
+	err := helpers.ConstantRetry(cfg.RetryAttempts, cfg.RetryDelay).Do(t.Context(), t, func() error {
+		var err error
+		health, err = s.Health(t.Context())
+		if errors.Is(err, ErrUnsupported) {
+			helpers.Skipf(t, "%s: %v", s.Name(), err)
 		}
-		return nil
+		return err
+	})
+	if health.Summary != "" {
+		t.Log(health.Summary)
+	}
+	assert.NoError(t, err, "%s should be healthy", s.Name())
+}
+
+// TestSecondaryStorageSchema checks that the orchestration cluster created
+// its indices or tables in the configured secondary storage.
+func TestSecondaryStorageSchema(t *testing.T) {
+	helpers.Track(t)
+	s := secondaryStorage(t)
+	var found []string
+	err := helpers.ConstantRetry(cfg.RetryAttempts, cfg.RetryDelay).Do(t.Context(), t, func() error {
+		var err error
+		found, err = s.CamundaSchema(t.Context())
+		return err
 	})
-	assert.NoError(t, err, "Elasticsearch cluster should be green")
+	require.NoError(t, err, "Camunda schema should exist in %s", s.Name())
+	t.Logf("%s: found %d Camunda indices/tables", s.Name(), len(found))
 }
 
 // Component represents a Camunda component to health-check.
 type Component struct {
 	Name string
 	URL  string
+	// Optional components are skipped instead of failed when their health
+	// endpoint rejects the suite's credentials.
+	Optional bool
 }
 
 func healthComponents() []Component {
@@ -112,42 +106,35 @@ func healthComponents() []Component {
 
 // TestReadiness checks /actuator/health/readiness for each component.
 func TestReadiness(t *testing.T) {
+	helpers.Track(t)
 	for _, comp := range healthComponents() {
 		t.Run(comp.Name, func(t *testing.T) {
-			url := comp.URL + "/actuator/health/readiness"
-			err := helpers.Retry(cfg.RetryAttempts, cfg.RetryDelay, func() error {
-				resp, err := client.Get(url)
-				if err != nil {
-					return fmt.Errorf("request to %s failed: %w", url, err)
-				}
-				defer resp.Body.Close()
-				if resp.StatusCode != http.StatusOK {
-					return fmt.Errorf("%s readiness: expected 200, got %d", comp.Name, resp.StatusCode)
-				}
-				return nil
-			})
-			require.NoError(t, err, "%s should be ready", comp.Name)
+			require.NoError(t, checkHealthGroup(t, comp, "readiness"), "%s should be ready", comp.Name)
 		})
 	}
 }
 
 // TestLiveness checks /actuator/health/liveness for each component.
 func TestLiveness(t *testing.T) {
+	helpers.Track(t)
 	for _, comp := range healthComponents() {
 		t.Run(comp.Name, func(t *testing.T) {
-			url := comp.URL + "/actuator/health/liveness"
-			err := helpers.Retry(cfg.RetryAttempts, cfg.RetryDelay, func() error {
-				resp, err := client.Get(url)
-				if err != nil {
-					return fmt.Errorf("request to %s failed: %w", url, err)
-				}
-				defer resp.Body.Close()
-				if resp.StatusCode != http.StatusOK {
-					return fmt.Errorf("%s liveness: expected 200, got %d", comp.Name, resp.StatusCode)
-				}
-				return nil
-			})
-			require.NoError(t, err, "%s should be alive", comp.Name)
+			require.NoError(t, checkHealthGroup(t, comp, "liveness"), "%s should be alive", comp.Name)
 		})
 	}
 }
+
+// checkHealthGroup retries until the health group reports UP, naming any
+// DOWN sub-indicators in the error.
+func checkHealthGroup(t *testing.T, comp Component, group string) error {
+	return helpers.ConstantRetry(cfg.RetryAttempts, cfg.RetryDelay).Do(t.Context(), t, func() error {
+		h, err := client.Health(t.Context(), comp.URL, group)
+		if err != nil {
+			return fmt.Errorf("%s %s: %w", comp.Name, group, err)
+		}
+		if h.Status != helpers.StatusUp {
+			return fmt.Errorf("%s %s is %s; failing indicators: %s", comp.Name, group, h.Status, joinIndicators(h.Down()))
+		}
+		return nil
+	})
+}
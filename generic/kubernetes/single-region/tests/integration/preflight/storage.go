@@ -0,0 +1,330 @@
+package preflight
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/config"
+	"github.com/camunda/camunda-deployment-references/tests/integration/helpers"
+)
+
+// SecondaryStorage is the backend the orchestration cluster exports to and
+// searches in. NewSecondaryStorage picks the implementation from
+// Config.SecondaryStorage.
+type SecondaryStorage interface {
+	// Name is the backend name as configured, e.g. "opensearch".
+	Name() string
+	// Ready returns nil once the backend answers requests.
+	Ready(ctx context.Context) error
+	// Health reports cluster colour (search engines) or replication state
+	// (RDBMS). The error is non-nil when the backend is not healthy.
+	Health(ctx context.Context) (StorageHealth, error)
+	// CamundaSchema checks that the Camunda indices or tables exist and
+	// returns the ones found.
+	CamundaSchema(ctx context.Context) ([]string, error)
+	Close() error
+}
+
+// StorageHealth is the outcome of SecondaryStorage.Health.
+type StorageHealth struct {
+	// Status is green/yellow/red for search engines and primary/replica for
+	// an RDBMS.
+	Status string
+	// Summary is a one-line description for the test log.
+	Summary string
+}
+
+// ErrUnsupported is returned by checks a backend cannot perform, e.g.
+// cluster health on OpenSearch Serverless.
+var ErrUnsupported = errors.New("not supported by this backend")
+
+// NewSecondaryStorage returns the backend selected by cfg, or nil when
+// cfg.SecondaryStorage is "none".
+func NewSecondaryStorage(cfg *config.Config) (SecondaryStorage, error) {
+	switch cfg.SecondaryStorage {
+	case "none":
+		return nil, nil
+	case "elasticsearch", "opensearch":
+		auth, err := helpers.SearchEngineAuth(cfg)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.SecondaryStorage == "elasticsearch" {
+			return newSearchStorage("elasticsearch", cfg.ElasticsearchURL, cfg.IndexPrefix, auth), nil
+		}
+		s := newSearchStorage("opensearch", cfg.OpenSearchURL, cfg.IndexPrefix, auth)
+		s.serverless = cfg.OpenSearchAWSService == "aoss"
+		return s, nil
+	case "rdbms":
+		s, err := newRDBMSStorage(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+	return nil, fmt.Errorf("unsupported secondary storage %q", cfg.SecondaryStorage)
+}
+
+// camundaIndexPatterns are the index families the orchestration cluster
+// creates at startup; at least one index must match each.
+var camundaIndexPatterns = []string{"operate-*", "tasklist-*"}
+
+// searchStorage implements SecondaryStorage for Elasticsearch and
+// OpenSearch, which share the REST APIs used here.
+type searchStorage struct {
+	name    string
+	baseURL string
+	prefix  string
+	auth    func(*http.Request) error
+	http    *http.Client
+	// serverless marks OpenSearch Serverless, which has no cluster APIs.
+	serverless bool
+}
+
+func newSearchStorage(name, baseURL, prefix string, auth func(*http.Request) error) *searchStorage {
+	return &searchStorage{
+		name:    name,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		prefix:  prefix,
+		auth:    auth,
+		http: &http.Client{
+			Timeout: 10 * time.Second,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, //nolint:gosec // self-signed ECK/OpenSearch certs
+			},
+		},
+	}
+}
+
+func (s *searchStorage) Name() string { return s.name }
+
+func (s *searchStorage) Close() error { return nil }
+
+// get performs an authenticated GET and decodes the JSON response into out.
+func (s *searchStorage) get(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	if err := s.auth(req); err != nil {
+		return fmt.Errorf("%s auth: %w", s.name, err)
+	}
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s request failed: %w", s.name, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return helpers.NewStatusError(resp, string(body))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(body, out)
+}
+
+type clusterHealth struct {
+	ClusterName         string  `json:"cluster_name"`
+	Status              string  `json:"status"`
+	NumberOfNodes       int     `json:"number_of_nodes"`
+	ActiveShards        int     `json:"active_shards"`
+	UnassignedShards    int     `json:"unassigned_shards"`
+	RelocatingShards    int     `json:"relocating_shards"`
+	ActiveShardsPercent float64 `json:"active_shards_percent_as_number"`
+}
+
+func (s *searchStorage) Ready(ctx context.Context) error {
+	if s.serverless {
+		return s.get(ctx, "/_cat/indices?format=json", nil)
+	}
+	return s.get(ctx, "/_cluster/health?timeout=1s", nil)
+}
+
+func (s *searchStorage) Health(ctx context.Context) (StorageHealth, error) {
+	if s.serverless {
+		return StorageHealth{}, fmt.Errorf("cluster health on OpenSearch Serverless: %w", ErrUnsupported)
+	}
+	var h clusterHealth
+	if err := s.get(ctx, "/_cluster/health", &h); err != nil {
+		return StorageHealth{}, err
+	}
+	out := StorageHealth{
+		Status: h.Status,
+		Summary: fmt.Sprintf("%s is %s: %d node(s), %d active shard(s) (%.0f%%), %d unassigned, %d relocating",
+			h.ClusterName, h.Status, h.NumberOfNodes, h.ActiveShards, h.ActiveShardsPercent, h.UnassignedShards, h.RelocatingShards),
+	}
+	if h.Status != "green" {
+		return out, fmt.Errorf("%s cluster is %s with %d unassigned shard(s)", s.name, h.Status, h.UnassignedShards)
+	}
+	return out, nil
+}
+
+func (s *searchStorage) CamundaSchema(ctx context.Context) ([]string, error) {
+	var found, missing []string
+	for _, pattern := range camundaIndexPatterns {
+		if s.prefix != "" {
+			pattern = s.prefix + "-" + pattern
+		}
+		var indices []struct {
+			Index string `json:"index"`
+		}
+		if err := s.get(ctx, "/_cat/indices/"+pattern+"?format=json&h=index", &indices); err != nil {
+			return found, err
+		}
+		if len(indices) == 0 {
+			missing = append(missing, pattern)
+		}
+		for _, i := range indices {
+			found = append(found, i.Index)
+		}
+	}
+	if len(missing) > 0 {
+		return found, fmt.Errorf("no %s indices match %s (check TEST_INDEX_PREFIX)", s.name, strings.Join(missing, ", "))
+	}
+	return found, nil
+}
+
+// camundaTables are created by the orchestration cluster's RDBMS schema
+// migration; their presence proves the migration ran against this database.
+var camundaTables = []string{"PROCESS_DEFINITION", "PROCESS_INSTANCE", "FLOW_NODE_INSTANCE", "VARIABLE", "USER_TASK", "INCIDENT"}
+
+// rdbmsStorage implements SecondaryStorage for PostgreSQL and Aurora
+// PostgreSQL. The connection is opened lazily so a config error surfaces
+// as a test failure rather than in TestMain.
+type rdbmsStorage struct {
+	cfg    *pgx.ConnConfig
+	prefix string
+	conn   *pgx.Conn
+}
+
+func newRDBMSStorage(cfg *config.Config) (*rdbmsStorage, error) {
+	cc, err := pgx.ParseConfig(cfg.RDBMSURL)
+	if err != nil {
+		// The URL may embed a password; do not echo it.
+		return nil, errors.New("TEST_RDBMS_URL is not a valid PostgreSQL connection URL")
+	}
+	if cfg.RDBMSUser != "" {
+		cc.User = cfg.RDBMSUser
+	}
+	if cfg.RDBMSPassword != "" {
+		cc.Password = cfg.RDBMSPassword
+	}
+	cc.ConnectTimeout = 10 * time.Second
+	return &rdbmsStorage{cfg: cc, prefix: cfg.RDBMSTablePrefix}, nil
+}
+
+func (s *rdbmsStorage) Name() string { return "rdbms" }
+
+func (s *rdbmsStorage) connect(ctx context.Context) (*pgx.Conn, error) {
+	if s.conn != nil && !s.conn.IsClosed() {
+		return s.conn, nil
+	}
+	conn, err := pgx.ConnectConfig(ctx, s.cfg)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to %s:%d/%s: %w", s.cfg.Host, s.cfg.Port, s.cfg.Database, err)
+	}
+	s.conn = conn
+	return conn, nil
+}
+
+func (s *rdbmsStorage) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	return s.conn.Close(context.Background())
+}
+
+func (s *rdbmsStorage) Ready(ctx context.Context) error {
+	conn, err := s.connect(ctx)
+	if err != nil {
+		return err
+	}
+	return conn.Ping(ctx)
+}
+
+// Health requires a writable primary (Camunda writes to it) and reports the
+// number of replicas: streaming replicas for PostgreSQL, reader instances
+// for Aurora, which does not populate pg_stat_replication.
+func (s *rdbmsStorage) Health(ctx context.Context) (StorageHealth, error) {
+	conn, err := s.connect(ctx)
+	if err != nil {
+		return StorageHealth{}, err
+	}
+	var inRecovery bool
+	var version string
+	if err := conn.QueryRow(ctx, "SELECT pg_is_in_recovery(), current_setting('server_version')").Scan(&inRecovery, &version); err != nil {
+		return StorageHealth{}, fmt.Errorf("querying recovery state: %w", err)
+	}
+	if inRecovery {
+		return StorageHealth{Status: "replica", Summary: "PostgreSQL " + version + " is a read-only replica"},
+			fmt.Errorf("connected to a read-only replica; Camunda needs the writer endpoint")
+	}
+
+	flavour, replicas := "PostgreSQL", 0
+	var aurora string
+	if err := conn.QueryRow(ctx, "SELECT aurora_version()").Scan(&aurora); err == nil {
+		flavour = "Aurora PostgreSQL " + aurora
+		err = conn.QueryRow(ctx, "SELECT count(*) FROM aurora_replica_status() WHERE session_id <> 'MASTER_SESSION_ID'").Scan(&replicas)
+		if err != nil {
+			return StorageHealth{}, fmt.Errorf("querying Aurora replicas: %w", err)
+		}
+	} else {
+		err = conn.QueryRow(ctx, "SELECT count(*) FROM pg_stat_replication WHERE state = 'streaming'").Scan(&replicas)
+		if err != nil {
+			return StorageHealth{}, fmt.Errorf("querying streaming replicas: %w", err)
+		}
+	}
+	return StorageHealth{
+		Status:  "primary",
+		Summary: fmt.Sprintf("%s %s primary with %d replica(s)", flavour, version, replicas),
+	}, nil
+}
+
+func (s *rdbmsStorage) CamundaSchema(ctx context.Context) ([]string, error) {
+	conn, err := s.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	want := map[string]bool{}
+	names := make([]string, 0, len(camundaTables))
+	for _, t := range camundaTables {
+		name := strings.ToLower(s.prefix + t)
+		want[name] = true
+		names = append(names, name)
+	}
+	rows, err := conn.Query(ctx,
+		"SELECT lower(table_name) FROM information_schema.tables WHERE table_schema = current_schema() AND lower(table_name) = ANY($1)", names)
+	if err != nil {
+		return nil, fmt.Errorf("listing tables: %w", err)
+	}
+	found, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("listing tables: %w", err)
+	}
+	for _, f := range found {
+		delete(want, f)
+	}
+	if len(want) > 0 {
+		var missing []string
+		for _, n := range names {
+			if want[n] {
+				missing = append(missing, n)
+			}
+		}
+		return found, fmt.Errorf("missing Camunda tables in the current schema: %s (check TEST_RDBMS_TABLE_PREFIX and search_path)", strings.Join(missing, ", "))
+	}
+	return found, nil
+}
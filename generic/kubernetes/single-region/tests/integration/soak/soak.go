@@ -0,0 +1,330 @@
+// Package soak puts sustained load on an orchestration cluster: it deploys a
+// one-service-task process, starts instances at a fixed rate for a fixed
+// time with bounded concurrency, completes their jobs through the REST job
+// API and summarises throughput, latency, errors and backlog. It reuses the
+// suite's config and helpers.Client, so it runs against anything the
+// integration tests can reach.
+//
+// cmd/soak is the command around Run; it checks the Summary against the
+// configured thresholds and writes it as JSON.
+package soak
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/camunda"
+	"github.com/camunda/camunda-deployment-references/tests/integration/camunda/fixtures"
+	"github.com/camunda/camunda-deployment-references/tests/integration/config"
+)
+
+// ProcessID is the BPMN process ID the soak run deploys and starts.
+const ProcessID = "soak-service-task"
+
+// maxErrorSamples bounds the distinct error messages kept in a Summary.
+const maxErrorSamples = 10
+
+// Options controls a Run.
+type Options struct {
+	// Rate is the number of process instances started per second.
+	Rate float64
+	// Duration is how long instances are started for.
+	Duration time.Duration
+	// Concurrency bounds both the start requests in flight and the job
+	// workers. When every start slot is busy the run falls behind Rate
+	// rather than queueing unboundedly; the achieved rate is reported.
+	Concurrency int
+	// Drain is how long Run keeps completing jobs after the last start
+	// before giving up on the remaining backlog.
+	Drain time.Duration
+}
+
+// OptionsFromConfig returns the Options configured through the TEST_SOAK_*
+// keys. The drain window is TEST_E2E_MAX_LATENCY, the time a single
+// instance may take end to end.
+func OptionsFromConfig(cfg *config.Config) Options {
+	return Options{
+		Rate:        cfg.SoakRate,
+		Duration:    cfg.SoakDuration,
+		Concurrency: cfg.SoakConcurrency,
+		Drain:       cfg.E2EMaxLatency,
+	}
+}
+
+// Run deploys the soak process and drives it as opts describe. The Summary
+// is returned even when ctx ends early, covering the part that ran; the
+// error is only set when the process could not be deployed.
+func Run(ctx context.Context, api *camunda.Client, opts Options) (*Summary, error) {
+	if opts.Rate <= 0 || opts.Concurrency < 1 {
+		return nil, fmt.Errorf("soak: rate and concurrency must be positive, got %g and %d", opts.Rate, opts.Concurrency)
+	}
+	// A job type unique to this run keeps workers of a concurrent or
+	// aborted run from completing (and timing) jobs of this one.
+	jobType := fmt.Sprintf("soak-%d", time.Now().UnixNano())
+	deployment, err := api.Deploy(ctx, "", camunda.Resource{Name: "soak.bpmn", Content: []byte(fixtures.ServiceTaskBPMN(ProcessID, jobType))})
+	if err != nil {
+		return nil, fmt.Errorf("soak: deploying %s: %w", ProcessID, err)
+	}
+	processes := deployment.Processes()
+	if len(processes) != 1 {
+		return nil, fmt.Errorf("soak: deployment contains %d processes, want 1", len(processes))
+	}
+
+	r := &run{
+		api:           api,
+		opts:          opts,
+		jobType:       jobType,
+		definitionKey: processes[0].ProcessDefinitionKey,
+		rec:           newRecorder(),
+	}
+	return r.execute(ctx), nil
+}
+
+type run struct {
+	api           *camunda.Client
+	opts          Options
+	jobType       string
+	definitionKey string
+	rec           *recorder
+}
+
+func (r *run) execute(ctx context.Context) *Summary {
+	started := time.Now()
+	workCtx, stopWorkers := context.WithCancel(ctx)
+	defer stopWorkers()
+
+	var workers sync.WaitGroup
+	for i := 0; i < r.opts.Concurrency; i++ {
+		workers.Add(1)
+		go func(id int) {
+			defer workers.Done()
+			r.work(workCtx, fmt.Sprintf("soak-worker-%d", id))
+		}(i)
+	}
+
+	r.startInstances(ctx)
+	startedFor := time.Since(started)
+	r.drain(ctx)
+	stopWorkers()
+	workers.Wait()
+
+	return r.rec.summary(started, startedFor, time.Since(started), r.opts)
+}
+
+// startInstances starts one instance per tick until Duration has passed or
+// ctx ends, with at most Concurrency requests in flight, and waits for the
+// outstanding requests.
+func (r *run) startInstances(ctx context.Context) {
+	interval := time.Duration(float64(time.Second) / r.opts.Rate)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	deadline := time.NewTimer(r.opts.Duration)
+	defer deadline.Stop()
+
+	slots := make(chan struct{}, r.opts.Concurrency)
+	var inflight sync.WaitGroup
+	defer inflight.Wait()
+	for index := 0; ; index++ {
+		select {
+		case <-ctx.Done():
+			return
+		case <-deadline.C:
+			return
+		case <-ticker.C:
+		}
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+		inflight.Add(1)
+		go func(index int) {
+			defer inflight.Done()
+			defer func() { <-slots }()
+			sent := time.Now()
+			created, err := r.api.CreateProcessInstance(ctx, camunda.CreateProcessInstanceRequest{
+				ProcessDefinitionKey: r.definitionKey,
+				Variables:            map[string]interface{}{"index": index},
+			})
+			if err != nil {
+				r.rec.failed(ctx, "start", err)
+				return
+			}
+			r.rec.started(created.ProcessInstanceKey, sent)
+		}(index)
+	}
+}
+
+// drain waits until every started instance is completed, Drain has passed
+// or ctx ends.
+func (r *run) drain(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, r.opts.Drain)
+	defer cancel()
+	ticker := time.NewTicker(200 * time.Millisecond)
+	defer ticker.Stop()
+	for r.rec.backlog() > 0 {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// work activates and completes jobs of the run's job type until ctx ends.
+func (r *run) work(ctx context.Context, name string) {
+	for ctx.Err() == nil {
+		jobs, err := r.api.ActivateJobs(ctx, camunda.ActivateJobsRequest{
+			Type:              r.jobType,
+			Worker:            name,
+			Timeout:           time.Minute.Milliseconds(),
+			MaxJobsToActivate: 32,
+			RequestTimeout:    (5 * time.Second).Milliseconds(),
+		})
+		r.rec.operation()
+		if err != nil {
+			r.rec.failed(ctx, "activate", err)
+			sleep(ctx, time.Second)
+			continue
+		}
+		for _, job := range jobs {
+			r.rec.operation()
+			if err := r.api.CompleteJob(ctx, job.JobKey, nil); err != nil {
+				r.rec.failed(ctx, "complete", err)
+				continue
+			}
+			r.rec.completed(job.ProcessInstanceKey, time.Now())
+		}
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+	case <-t.C:
+	}
+}
+
+// recorder tracks the instances of a run. A worker may complete a job
+// before the start request that created it has returned, so completions of
+// unknown instances are parked until the start is recorded.
+type recorder struct {
+	mu          sync.Mutex
+	starts      map[string]time.Time
+	early       map[string]time.Time
+	latencies   []time.Duration
+	launched    int
+	done        int
+	operations  int
+	errors      int
+	samples     []string
+	peakBacklog int
+}
+
+func newRecorder() *recorder {
+	return &recorder{starts: map[string]time.Time{}, early: map[string]time.Time{}}
+}
+
+func (r *recorder) started(key string, at time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.operations++
+	r.launched++
+	if end, ok := r.early[key]; ok {
+		delete(r.early, key)
+		r.finish(at, end)
+	} else {
+		r.starts[key] = at
+	}
+	if b := r.launched - r.done; b > r.peakBacklog {
+		r.peakBacklog = b
+	}
+}
+
+func (r *recorder) completed(key string, at time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if start, ok := r.starts[key]; ok {
+		delete(r.starts, key)
+		r.finish(start, at)
+	} else {
+		r.early[key] = at
+	}
+}
+
+// finish records one completed instance. r.mu must be held.
+func (r *recorder) finish(start, end time.Time) {
+	r.done++
+	r.latencies = append(r.latencies, end.Sub(start))
+}
+
+// operation counts a job activation or completion; starts are counted by
+// started and failed.
+func (r *recorder) operation() {
+	r.mu.Lock()
+	r.operations++
+	r.mu.Unlock()
+}
+
+// failed counts err against the run unless it is only ctx ending, which is
+// how the run stops rather than a failure of the cluster.
+func (r *recorder) failed(ctx context.Context, op string, err error) {
+	if ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if op == "start" {
+		r.operations++
+	}
+	r.errors++
+	msg := op + ": " + err.Error()
+	if len(r.samples) < maxErrorSamples {
+		for _, s := range r.samples {
+			if s == msg {
+				return
+			}
+		}
+		r.samples = append(r.samples, msg)
+	}
+}
+
+func (r *recorder) backlog() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.launched - r.done
+}
+
+func (r *recorder) summary(started time.Time, startedFor, elapsed time.Duration, opts Options) *Summary {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := &Summary{
+		Started:            started.UTC(),
+		DurationSeconds:    elapsed.Seconds(),
+		TargetRate:         opts.Rate,
+		Concurrency:        opts.Concurrency,
+		InstancesStarted:   r.launched,
+		InstancesCompleted: r.done,
+		Operations:         r.operations,
+		Errors:             r.errors,
+		PeakBacklog:        r.peakBacklog,
+		FinalBacklog:       r.launched - r.done,
+		LatencyMillis:      percentiles(r.latencies),
+		ErrorSamples:       append([]string(nil), r.samples...),
+	}
+	if startedFor > 0 {
+		s.StartRate = float64(r.launched) / startedFor.Seconds()
+	}
+	if elapsed > 0 {
+		s.Throughput = float64(r.done) / elapsed.Seconds()
+	}
+	if r.operations > 0 {
+		s.ErrorRate = float64(r.errors) / float64(r.operations)
+	}
+	return s
+}
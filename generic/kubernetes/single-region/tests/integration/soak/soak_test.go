@@ -0,0 +1,101 @@
+package soak
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/camunda"
+	"github.com/camunda/camunda-deployment-references/tests/integration/config"
+	"github.com/camunda/camunda-deployment-references/tests/integration/helpers"
+)
+
+// fakeEngine answers the deployment, instance and job endpoints: every
+// started instance gets one job, and every failEvery-th start returns 503.
+type fakeEngine struct {
+	mu        sync.Mutex
+	next      int
+	pending   []string
+	completed map[string]bool
+	failEvery int
+	starts    int
+}
+
+func (e *fakeEngine) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	switch r.URL.Path {
+	case "/v2/deployments":
+		fmt.Fprint(w, `{"deploymentKey":"1","deployments":[{"processDefinition":{"processDefinitionId":"soak-service-task","processDefinitionKey":"2"}}]}`)
+	case "/v2/process-instances":
+		e.starts++
+		if e.failEvery > 0 && e.starts%e.failEvery == 0 {
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+			return
+		}
+		e.next++
+		key := fmt.Sprintf("%d", 100+e.next)
+		e.pending = append(e.pending, key)
+		fmt.Fprintf(w, `{"processInstanceKey":%q,"processDefinitionKey":"2"}`, key)
+	case "/v2/jobs/activation":
+		var jobs []camunda.ActivatedJob
+		for _, key := range e.pending {
+			jobs = append(jobs, camunda.ActivatedJob{JobKey: "job-" + key, ProcessInstanceKey: key})
+		}
+		e.pending = nil
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jobs": jobs})
+	default:
+		// /v2/jobs/job-<key>/completion
+		var key string
+		if _, err := fmt.Sscanf(r.URL.Path, "/v2/jobs/job-%s", &key); err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		e.completed[key] = true
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestRun(t *testing.T) {
+	engine := &fakeEngine{completed: map[string]bool{}, failEvery: 10}
+	srv := httptest.NewServer(engine)
+	t.Cleanup(srv.Close)
+	api := camunda.New(helpers.NewClient(&config.Config{AuthMode: "none", HTTPTimeout: 5 * time.Second}), srv.URL)
+
+	s, err := Run(t.Context(), api, Options{Rate: 200, Duration: 500 * time.Millisecond, Concurrency: 4, Drain: 5 * time.Second})
+	require.NoError(t, err)
+
+	assert.Greater(t, s.InstancesStarted, 20, "about 100 ticks minus every tenth start")
+	assert.Equal(t, s.InstancesStarted, s.InstancesCompleted)
+	assert.Zero(t, s.FinalBacklog)
+	assert.Positive(t, s.Errors)
+	assert.Positive(t, s.ErrorRate)
+	assert.Len(t, s.ErrorSamples, 1, "identical errors are sampled once")
+	assert.Positive(t, s.Throughput)
+	assert.LessOrEqual(t, s.LatencyMillis.P50, s.LatencyMillis.P99)
+
+	require.Error(t, s.Check(0.001, 1000))
+	assert.Len(t, s.Violations, 1)
+	assert.Contains(t, s.Violations[0], "error rate")
+}
+
+func TestRecorderEarlyCompletion(t *testing.T) {
+	r := newRecorder()
+	start := time.Now()
+	r.completed("1", start.Add(30*time.Millisecond))
+	r.started("1", start)
+	r.started("2", start)
+
+	s := r.summary(start, time.Second, time.Second, Options{Rate: 1, Concurrency: 1})
+	assert.Equal(t, 2, s.InstancesStarted)
+	assert.Equal(t, 1, s.InstancesCompleted)
+	assert.Equal(t, 1, s.FinalBacklog)
+	assert.Equal(t, 30.0, s.LatencyMillis.Max)
+}
@@ -0,0 +1,113 @@
+package soak
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Summary is the result of a Run. Rates are per second; an operation is an
+// instance start, a job activation or a job completion.
+type Summary struct {
+	Started            time.Time `json:"started"`
+	DurationSeconds    float64   `json:"durationSeconds"`
+	TargetRate         float64   `json:"targetRate"`
+	StartRate          float64   `json:"startRate"`
+	Concurrency        int       `json:"concurrency"`
+	InstancesStarted   int       `json:"instancesStarted"`
+	InstancesCompleted int       `json:"instancesCompleted"`
+	Throughput         float64   `json:"throughput"`
+	Operations         int       `json:"operations"`
+	Errors             int       `json:"errors"`
+	ErrorRate          float64   `json:"errorRate"`
+	// PeakBacklog is the most instances started but not yet completed at
+	// any one time; FinalBacklog is what was still open when the run ended.
+	PeakBacklog   int      `json:"peakBacklog"`
+	FinalBacklog  int      `json:"finalBacklog"`
+	LatencyMillis Latency  `json:"latencyMillis"`
+	ErrorSamples  []string `json:"errorSamples,omitempty"`
+	// Violations lists the thresholds Check found exceeded.
+	Violations []string `json:"violations"`
+}
+
+// Latency holds start-to-job-completion latency percentiles in
+// milliseconds.
+type Latency struct {
+	P50 float64 `json:"p50"`
+	P95 float64 `json:"p95"`
+	P99 float64 `json:"p99"`
+	Max float64 `json:"max"`
+}
+
+// percentiles returns the nearest-rank percentiles of d (which it sorts).
+func percentiles(d []time.Duration) Latency {
+	if len(d) == 0 {
+		return Latency{}
+	}
+	sort.Slice(d, func(a, b int) bool { return d[a] < d[b] })
+	rank := func(p float64) float64 {
+		i := int(math.Ceil(p/100*float64(len(d)))) - 1
+		if i < 0 {
+			i = 0
+		}
+		return float64(d[i]) / float64(time.Millisecond)
+	}
+	return Latency{P50: rank(50), P95: rank(95), P99: rank(99), Max: rank(100)}
+}
+
+// Check records every exceeded threshold in s.Violations and returns an
+// error listing them, or nil when the run stayed within bounds. A run that
+// completed nothing is always a violation.
+func (s *Summary) Check(maxErrorRate float64, maxBacklog int) error {
+	s.Violations = []string{}
+	if s.InstancesCompleted == 0 {
+		s.Violations = append(s.Violations, "no process instance completed")
+	}
+	if s.ErrorRate > maxErrorRate {
+		s.Violations = append(s.Violations, fmt.Sprintf("error rate %.4f exceeds %.4f (%d of %d operations failed)", s.ErrorRate, maxErrorRate, s.Errors, s.Operations))
+	}
+	if s.PeakBacklog > maxBacklog {
+		s.Violations = append(s.Violations, fmt.Sprintf("backlog peaked at %d open instances, limit %d", s.PeakBacklog, maxBacklog))
+	}
+	if s.FinalBacklog > 0 {
+		s.Violations = append(s.Violations, fmt.Sprintf("%d instances were still open after draining", s.FinalBacklog))
+	}
+	if len(s.Violations) == 0 {
+		return nil
+	}
+	return fmt.Errorf("soak thresholds exceeded:\n  - %s", strings.Join(s.Violations, "\n  - "))
+}
+
+// String renders s for a log.
+func (s *Summary) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "soak run of %s at target %g/s, concurrency %d\n", time.Duration(s.DurationSeconds*float64(time.Second)).Round(time.Second), s.TargetRate, s.Concurrency)
+	fmt.Fprintf(&b, "  instances:  %d started (%.2f/s), %d completed (%.2f/s)\n", s.InstancesStarted, s.StartRate, s.InstancesCompleted, s.Throughput)
+	fmt.Fprintf(&b, "  latency:    p50 %.0fms, p95 %.0fms, p99 %.0fms, max %.0fms\n", s.LatencyMillis.P50, s.LatencyMillis.P95, s.LatencyMillis.P99, s.LatencyMillis.Max)
+	fmt.Fprintf(&b, "  errors:     %d of %d operations (%.4f)\n", s.Errors, s.Operations, s.ErrorRate)
+	fmt.Fprintf(&b, "  backlog:    peak %d, final %d\n", s.PeakBacklog, s.FinalBacklog)
+	for _, e := range s.ErrorSamples {
+		fmt.Fprintf(&b, "  error:      %s\n", e)
+	}
+	return b.String()
+}
+
+// WriteJSON stores s at path, creating its directory if needed.
+func (s *Summary) WriteJSON(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating summary dir: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing soak summary: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package soak
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPercentiles(t *testing.T) {
+	var d []time.Duration
+	for i := 100; i >= 1; i-- {
+		d = append(d, time.Duration(i)*time.Millisecond)
+	}
+	assert.Equal(t, Latency{P50: 50, P95: 95, P99: 99, Max: 100}, percentiles(d))
+	assert.Equal(t, Latency{P50: 7, P95: 7, P99: 7, Max: 7}, percentiles([]time.Duration{7 * time.Millisecond}))
+	assert.Equal(t, Latency{}, percentiles(nil))
+}
+
+func TestSummaryCheck(t *testing.T) {
+	s := &Summary{InstancesStarted: 100, InstancesCompleted: 100, Operations: 300, Errors: 3, ErrorRate: 0.01, PeakBacklog: 40}
+	assert.NoError(t, s.Check(0.01, 50))
+	assert.Empty(t, s.Violations)
+
+	s.PeakBacklog, s.FinalBacklog = 60, 2
+	err := s.Check(0.001, 50)
+	require.Error(t, err)
+	assert.Len(t, s.Violations, 3)
+	assert.Contains(t, err.Error(), "error rate 0.0100 exceeds 0.0010 (3 of 300 operations failed)")
+	assert.Contains(t, err.Error(), "backlog peaked at 60 open instances, limit 50")
+	assert.Contains(t, err.Error(), "2 instances were still open after draining")
+
+	assert.ErrorContains(t, (&Summary{}).Check(1, 1), "no process instance completed")
+}
+
+func TestSummaryWriteJSON(t *testing.T) {
+	s := &Summary{InstancesStarted: 3, LatencyMillis: Latency{P99: 12.5}}
+	require.Error(t, s.Check(0, 10))
+	path := filepath.Join(t.TempDir(), "out", "soak-summary.json")
+	require.NoError(t, s.WriteJSON(path))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var got map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, 12.5, got["latencyMillis"].(map[string]interface{})["p99"])
+	assert.Equal(t, []interface{}{"no process instance completed"}, got["violations"])
+}
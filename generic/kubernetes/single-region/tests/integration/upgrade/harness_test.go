@@ -0,0 +1,300 @@
+package upgrade
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/camunda"
+	"github.com/camunda/camunda-deployment-references/tests/integration/camunda/fixtures"
+	"github.com/camunda/camunda-deployment-references/tests/integration/config"
+	"github.com/camunda/camunda-deployment-references/tests/integration/helpers"
+)
+
+const upgradeProcessID = "integration-test-upgrade"
+
+// seeded is what the harness creates before the upgrade and expects to find
+// afterwards.
+type seeded struct {
+	definitionKey string
+	version       int
+	jobType       string
+	instances     map[string]bool
+}
+
+// TestUpgrade installs chart N-1, seeds data, upgrades to chart N and
+// checks that the cluster still works and still holds the seeded data.
+func TestUpgrade(t *testing.T) {
+	helpers.Track(t)
+	if cfg.UpgradeFromVersion == "" {
+		helpers.Skip(t, "set TEST_UPGRADE_FROM_VERSION (and TEST_UPGRADE_VALUES) to run the upgrade harness")
+	}
+	if !cfg.Authenticated() {
+		helpers.Skip(t, "the upgrade harness requires authentication to seed data")
+	}
+
+	helm, err := helpers.NewHelm(cfg, cfg.UpgradeTimeout, t.Logf)
+	require.NoError(t, err)
+	current, err := helm.Current()
+	require.NoError(t, err)
+	require.Nil(t, current, "release %s already exists in %s; uninstall it so the harness starts from chart N-1", cfg.ReleaseName, cfg.Namespace)
+
+	from := helpers.HelmChart{Ref: cfg.UpgradeFromChart, RepoURL: cfg.UpgradeRepoURL, Version: cfg.UpgradeFromVersion}
+	to := helpers.HelmChart{Ref: cfg.UpgradeToChart, RepoURL: cfg.UpgradeRepoURL, Version: cfg.UpgradeToVersion}
+
+	// The harness owns the release from here on, including a failed install,
+	// so a rerun against the same namespace starts from chart N-1 again.
+	t.Cleanup(func() {
+		if cfg.UpgradeKeepRelease {
+			t.Logf("keeping release %s in %s (TEST_UPGRADE_KEEP_RELEASE)", cfg.ReleaseName, cfg.Namespace)
+			return
+		}
+		if rel, err := helm.Current(); err == nil && rel == nil {
+			return
+		}
+		if err := helm.Uninstall(); err != nil {
+			t.Errorf("uninstalling release %s: %v", cfg.ReleaseName, err)
+			return
+		}
+		t.Logf("uninstalled release %s from %s", cfg.ReleaseName, cfg.Namespace)
+	})
+
+	var seed seeded
+	ok := t.Run("InstallPrevious", func(t *testing.T) {
+		rel, err := helm.Install(t.Context(), from, cfg.UpgradeFromValues)
+		require.NoError(t, err)
+		t.Logf("installed %s revision %d: chart %s, app %s", rel.Name, rel.Version, rel.Chart.Metadata.Version, rel.Chart.Metadata.AppVersion)
+
+		api := connect(t)
+		seed = seedData(t, api)
+	})
+	require.True(t, ok, "installing chart N-1 and seeding failed; not upgrading")
+
+	ok = t.Run("Upgrade", func(t *testing.T) {
+		rel, err := helm.Upgrade(t.Context(), to, cfg.UpgradeValues)
+		require.NoError(t, err)
+		t.Logf("upgraded %s to revision %d: chart %s, app %s", rel.Name, rel.Version, rel.Chart.Metadata.Version, rel.Chart.Metadata.AppVersion)
+	})
+	require.True(t, ok, "upgrade failed")
+
+	t.Run("SeededDataSurvived", func(t *testing.T) {
+		api := connect(t)
+		verifySeed(t, api, seed)
+	})
+
+	t.Run("CoreSuite", func(t *testing.T) {
+		runCoreSuite(t)
+	})
+}
+
+// connect opens port-forwards to the current pods (when TEST_PORT_FORWARD
+// is set) and waits until every partition has a leader. Tunnels die with
+// the pods they point at, so each phase connects afresh; they are closed
+// when t ends.
+func connect(t *testing.T) *camunda.Client {
+	t.Helper()
+	c := *cfg
+	if c.PortForward {
+		tunnels, err := helpers.StartPortForwards(&c)
+		require.NoError(t, err, "port-forwarding to the release")
+		t.Cleanup(tunnels.Close)
+	}
+	api := camunda.New(helpers.NewClient(&c), c.ZeebeGatewayURL)
+
+	err := helpers.DeadlineRetry(5*time.Minute, 5*time.Second).Do(t.Context(), t, func() error {
+		topo, err := api.Topology(t.Context())
+		if err != nil {
+			return err
+		}
+		if leaders := len(topo.Leaders()); leaders < topo.PartitionsCount {
+			return fmt.Errorf("%d of %d partitions have a leader", leaders, topo.PartitionsCount)
+		}
+		return nil
+	})
+	require.NoError(t, err, "cluster should have a leader for every partition")
+	return api
+}
+
+// seedData deploys a process whose service task waits for a job worker,
+// starts TEST_E2E_INSTANCES instances and waits until they are searchable.
+func seedData(t *testing.T, api *camunda.Client) seeded {
+	t.Helper()
+	s := seeded{jobType: fmt.Sprintf("integration-test-upgrade-%d", time.Now().UnixNano()), instances: map[string]bool{}}
+	resource := camunda.Resource{Name: "upgrade.bpmn", Content: []byte(fixtures.ServiceTaskBPMN(upgradeProcessID, s.jobType))}
+	err := helpers.ConstantRetry(5, cfg.RetryDelay).Do(t.Context(), t, func() error {
+		deployment, err := api.Deploy(t.Context(), "", resource)
+		if err != nil {
+			return err
+		}
+		processes := deployment.Processes()
+		if len(processes) != 1 {
+			return helpers.Permanent(fmt.Errorf("deployment contains %d processes, want 1", len(processes)))
+		}
+		s.definitionKey = processes[0].ProcessDefinitionKey
+		s.version = processes[0].ProcessDefinitionVersion
+		return nil
+	})
+	require.NoError(t, err, "deploy should succeed")
+
+	for i := 0; i < cfg.E2EInstances; i++ {
+		created, err := api.CreateProcessInstance(t.Context(), camunda.CreateProcessInstanceRequest{ProcessDefinitionKey: s.definitionKey})
+		require.NoError(t, err, "starting instance %d should succeed", i)
+		s.instances[created.ProcessInstanceKey] = true
+	}
+	err = helpers.DeadlineRetry(cfg.E2EMaxLatency, 2*time.Second).Do(t.Context(), t, func() error {
+		states, err := instanceStates(t.Context(), api, s.definitionKey)
+		if err != nil {
+			return err
+		}
+		if len(states) < len(s.instances) {
+			return fmt.Errorf("%d of %d instances searchable", len(states), len(s.instances))
+		}
+		return nil
+	})
+	require.NoError(t, err, "seeded instances should be searchable before the upgrade")
+	t.Logf("seeded %s version %d (key %s) with %d instances", upgradeProcessID, s.version, s.definitionKey, len(s.instances))
+	return s
+}
+
+// verifySeed checks the definition and instances seeded before the upgrade
+// are still searchable, and that the instances can still be completed,
+// which proves the broker state and not only the exported data survived.
+func verifySeed(t *testing.T, api *camunda.Client, s seeded) {
+	t.Helper()
+	err := helpers.DeadlineRetry(2*time.Minute, 5*time.Second).Do(t.Context(), t, func() error {
+		r, err := api.SearchProcessDefinitions(t.Context(), camunda.ProcessDefinitionFilter{ProcessDefinitionKey: s.definitionKey}, camunda.Page{})
+		if err != nil {
+			return err
+		}
+		if len(r.Items) != 1 {
+			return fmt.Errorf("definition %s not searchable after the upgrade", s.definitionKey)
+		}
+		if got := r.Items[0]; got.ProcessDefinitionID != upgradeProcessID || got.Version != s.version {
+			return fmt.Errorf("definition %s is %s version %d, want %s version %d", s.definitionKey, got.ProcessDefinitionID, got.Version, upgradeProcessID, s.version)
+		}
+		return nil
+	})
+	require.NoError(t, err, "the pre-upgrade process definition should survive")
+
+	states, err := instanceStates(t.Context(), api, s.definitionKey)
+	require.NoError(t, err)
+	for key := range s.instances {
+		assert.Equal(t, camunda.StateActive, states[key], "instance %s after the upgrade", key)
+	}
+
+	// Activation backs off while the upgraded gateway is still unavailable.
+	completed := map[string]bool{}
+	err = helpers.ExponentialRetry(time.Second, 10*time.Second, cfg.E2EMaxLatency).Do(t.Context(), t, func() error {
+		jobs, err := api.ActivateJobs(t.Context(), camunda.ActivateJobsRequest{
+			Type:              s.jobType,
+			Worker:            "integration-test-upgrade",
+			Timeout:           time.Minute.Milliseconds(),
+			MaxJobsToActivate: len(s.instances) - len(completed),
+			RequestTimeout:    (10 * time.Second).Milliseconds(),
+		})
+		if err != nil {
+			return err
+		}
+		for _, job := range jobs {
+			if err := api.CompleteJob(t.Context(), job.JobKey, nil); err != nil {
+				return fmt.Errorf("completing job %s: %w", job.JobKey, err)
+			}
+			completed[job.ProcessInstanceKey] = true
+		}
+		if len(completed) < len(s.instances) {
+			return fmt.Errorf("%d of %d pre-upgrade jobs completed", len(completed), len(s.instances))
+		}
+		return nil
+	})
+	require.NoError(t, err, "every pre-upgrade instance should still have its job")
+
+	err = helpers.DeadlineRetry(cfg.E2EMaxLatency, 2*time.Second).Do(t.Context(), t, func() error {
+		states, err := instanceStates(t.Context(), api, s.definitionKey)
+		if err != nil {
+			return err
+		}
+		for key := range s.instances {
+			if states[key] != camunda.StateCompleted {
+				return fmt.Errorf("instance %s is %q", key, states[key])
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err, "pre-upgrade instances should complete after the upgrade")
+}
+
+func instanceStates(ctx context.Context, api *camunda.Client, definitionKey string) (map[string]string, error) {
+	items, err := camunda.Collect(ctx, 100, func(ctx context.Context, p camunda.Page) (*camunda.SearchResult[camunda.ProcessInstance], error) {
+		return api.SearchProcessInstances(ctx, camunda.ProcessInstanceFilter{ProcessDefinitionKey: definitionKey}, p)
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]string, len(items))
+	for _, pi := range items {
+		out[pi.ProcessInstanceKey] = pi.State
+	}
+	return out, nil
+}
+
+// runCoreSuite runs `go test ./core/...` against the upgraded release with
+// this run's profile and -set overrides, streaming its output into the test
+// log. The environment is inherited, so the core suite sees the same
+// TEST_* settings and opens its own port-forwards.
+func runCoreSuite(t *testing.T) {
+	t.Helper()
+	args := []string{"test", "-count=1", "-timeout", "30m"}
+	if testing.Verbose() {
+		args = append(args, "-v")
+	}
+	args = append(args, "./core/...", "-args")
+	args = append(args, forwardedFlags(t, opts)...)
+
+	cmd := exec.CommandContext(t.Context(), "go", args...)
+	cmd.Dir = ".." // the module root
+	cmd.Env = os.Environ()
+	pr, pw := io.Pipe()
+	cmd.Stdout, cmd.Stderr = pw, pw
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		scanner := bufio.NewScanner(pr)
+		for scanner.Scan() {
+			t.Log("core: " + scanner.Text())
+		}
+	}()
+	err := cmd.Run()
+	_ = pw.Close()
+	<-done
+	require.NoError(t, err, "core suite should pass against the upgraded release")
+}
+
+// forwardedFlags rebuilds the -profile and -set flags of this run for a
+// child test binary that runs in the module root.
+func forwardedFlags(t *testing.T, o *config.Options) []string {
+	var out []string
+	if o.ProfilePath != "" {
+		abs, err := filepath.Abs(o.ProfilePath)
+		require.NoError(t, err)
+		out = append(out, "-profile", abs)
+	}
+	keys := make([]string, 0, len(o.Overrides))
+	for k := range o.Overrides {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		out = append(out, "-set", k+"="+o.Overrides[k])
+	}
+	return out
+}
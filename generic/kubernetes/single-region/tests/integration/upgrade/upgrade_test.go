@@ -0,0 +1,44 @@
+// Package upgrade is the chart upgrade harness: it installs the previous
+// chart version (TEST_UPGRADE_FROM_VERSION) through the Helm SDK, seeds a
+// process definition and running instances through the REST API, upgrades
+// the release to the target chart with the reference helm-values
+// (TEST_UPGRADE_VALUES), checks the seeded data survived and can still be
+// worked on, and finally re-runs the core suite against the upgraded
+// release.
+//
+// The namespace must be prepared the way the reference procedure prepares
+// it before `helm install` (secrets, operators, Elasticsearch); the release
+// itself must not exist yet. On kind, for example:
+//
+//	TEST_PORT_FORWARD=true TEST_AUTH_MODE=basic ... \
+//	TEST_UPGRADE_FROM_VERSION=14.0.0 \
+//	TEST_UPGRADE_TO_CHART=/path/to/built/camunda-platform \
+//	TEST_UPGRADE_VALUES=../../../../../../local/kubernetes/kind-single-region/helm-values/values-no-domain.yml \
+//	go test ./upgrade/... -timeout 90m
+//
+// The release is uninstalled when the test ends; set
+// TEST_UPGRADE_KEEP_RELEASE=true to leave it installed for inspection.
+package upgrade
+
+import (
+	"testing"
+
+	"github.com/camunda/camunda-deployment-references/tests/integration/config"
+	"github.com/camunda/camunda-deployment-references/tests/integration/helpers"
+)
+
+var (
+	opts *config.Options
+	cfg  *config.Config
+)
+
+// TestMain opens no port-forwards, unlike the other suites: the release does
+// not exist until the test installs it.
+func TestMain(m *testing.M) {
+	helpers.RunSuite(m, helpers.Suite{Name: "upgrade", NoPortForward: true, Setup: setupSuite})
+}
+
+func setupSuite(env *helpers.SuiteEnv) (func(), error) {
+	opts, cfg = env.Options, env.Config
+	return nil, nil
+}